@@ -1,37 +1,107 @@
-package main
-
-import (
-	"log"
-	"os"
-
-	"github.com/joho/godotenv"
-	"github.com/shubh-man007/Chirpy/cmd/internal/database"
-	"github.com/shubh-man007/Chirpy/cmd/internal/server"
-)
-
-func main() {
-	const port = "8080"
-
-	err := godotenv.Load()
-	if err != nil {
-		log.Printf("could not load .env file: %s", err)
-	}
-
-	connStr := os.Getenv("DB_URL")
-	jwtSecret := os.Getenv("JWT_SECRET")
-	polkaAPI := os.Getenv("POLKA_API_KEY")
-	platform := os.Getenv("PLATFORM")
-
-	pgx, err := database.NewDbPgx(connStr)
-	if err != nil {
-		log.Fatalf("failed connecting to DB: %v", err)
-	}
-	defer pgx.Close()
-
-	log.Print("connected to DB")
-
-	srv := server.New(port, pgx.Queries, platform, jwtSecret, polkaAPI)
-	if err := srv.Start(); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
-	}
-}
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+	"github.com/shubh-man007/Chirpy/cmd/internal/config"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+	"github.com/shubh-man007/Chirpy/cmd/internal/server"
+)
+
+func main() {
+	const port = "8080"
+
+	err := godotenv.Load()
+	if err != nil {
+		log.Printf("could not load .env file: %s", err)
+	}
+
+	connStr := os.Getenv("DB_URL")
+	polkaAPI := os.Getenv("POLKA_API_KEY")
+	platform := os.Getenv("PLATFORM")
+	filterFile := os.Getenv("CONTENT_FILTER_FILE")
+
+	editWindow := config.DefaultEditWindow
+	if window := os.Getenv("CHIRP_EDIT_WINDOW"); window != "" {
+		editWindow, err = time.ParseDuration(window)
+		if err != nil {
+			log.Fatalf("invalid CHIRP_EDIT_WINDOW: %v", err)
+		}
+		if editWindow <= 0 {
+			log.Fatalf("invalid CHIRP_EDIT_WINDOW: must be positive, got %s", editWindow)
+		}
+	}
+
+	keys, err := loadKeys()
+	if err != nil {
+		log.Fatalf("failed loading signing keys: %v", err)
+	}
+
+	totpKey, err := auth.ParseSecretKey(os.Getenv("TOTP_ENCRYPTION_KEY"))
+	if err != nil {
+		log.Fatalf("invalid TOTP_ENCRYPTION_KEY: %v", err)
+	}
+	totpSecrets, err := auth.NewSecretBox(totpKey)
+	if err != nil {
+		log.Fatalf("invalid TOTP_ENCRYPTION_KEY: %v", err)
+	}
+
+	pgx, err := database.NewDbPgx(connStr)
+	if err != nil {
+		log.Fatalf("failed connecting to DB: %v", err)
+	}
+	defer pgx.Close()
+
+	log.Print("connected to DB")
+
+	srv := server.New(port, pgx.Queries, platform, keys, polkaAPI, filterFile, editWindow, totpSecrets)
+	if err := srv.Start(); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+}
+
+// loadKeys signs access tokens with the keys in JWT_KEY_DIR when it is set
+// and with the JWT_SECRET HMAC secret otherwise.
+func loadKeys() (*auth.KeySet, error) {
+	issuer := envOr("JWT_ISSUER", auth.DefaultIssuer)
+	audience := envOr("JWT_AUDIENCE", auth.DefaultAudience)
+
+	dir := os.Getenv("JWT_KEY_DIR")
+	if dir == "" {
+		return auth.NewHMACKeySet(os.Getenv("JWT_SECRET"), issuer, audience), nil
+	}
+
+	opts := auth.KeyDirOptions{
+		Dir:       dir,
+		Algorithm: envOr("JWT_KEY_ALGORITHM", auth.AlgEdDSA),
+		Grace:     auth.DefaultKeyGrace,
+	}
+
+	var err error
+	if rotation := os.Getenv("JWT_KEY_ROTATION"); rotation != "" {
+		opts.Rotation, err = time.ParseDuration(rotation)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_KEY_ROTATION: %w", err)
+		}
+	}
+	if grace := os.Getenv("JWT_KEY_GRACE"); grace != "" {
+		opts.Grace, err = time.ParseDuration(grace)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_KEY_GRACE: %w", err)
+		}
+	}
+
+	return auth.LoadKeyDir(opts, issuer, audience)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -1,57 +1,65 @@
-package auth
-
-import (
-	"crypto/rand"
-	"encoding/hex"
-	"errors"
-	"net/http"
-	"strings"
-)
-
-func GetBearerToken(headers http.Header) (string, error) {
-	authHeader := headers.Get("Authorization")
-	if authHeader == "" {
-		return "", errors.New("no authorization header included")
-	}
-
-	token, ok := strings.CutPrefix(authHeader, "Bearer ")
-	if !ok {
-		return "", errors.New("authorization header must start with Bearer")
-	}
-
-	if token == "" {
-		return "", errors.New("bearer token is empty")
-	}
-
-	return token, nil
-}
-
-func GetAPIKey(headers http.Header) (string, error) {
-	authHeader := headers.Get("Authorization")
-	if authHeader == "" {
-		return "", errors.New("no authorization header included")
-	}
-
-	token, ok := strings.CutPrefix(authHeader, "ApiKey ")
-	if !ok {
-		return "", errors.New("authorization header must start with ApiKey")
-	}
-
-	if token == "" {
-		return "", errors.New("API Key is empty")
-	}
-
-	return token, nil
-}
-
-func MakeRefreshToken() (string, error) {
-	token := make([]byte, 32)
-	_, err := rand.Read(token)
-	if err != nil {
-		return "", errors.New("Could not generate random data")
-	}
-
-	encodedToken := hex.EncodeToString(token)
-
-	return encodedToken, nil
-}
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+func GetBearerToken(headers http.Header) (string, error) {
+	authHeader := headers.Get("Authorization")
+	if authHeader == "" {
+		return "", errors.New("no authorization header included")
+	}
+
+	token, ok := strings.CutPrefix(authHeader, "Bearer ")
+	if !ok {
+		return "", errors.New("authorization header must start with Bearer")
+	}
+
+	if token == "" {
+		return "", errors.New("bearer token is empty")
+	}
+
+	return token, nil
+}
+
+func GetAPIKey(headers http.Header) (string, error) {
+	authHeader := headers.Get("Authorization")
+	if authHeader == "" {
+		return "", errors.New("no authorization header included")
+	}
+
+	token, ok := strings.CutPrefix(authHeader, "ApiKey ")
+	if !ok {
+		return "", errors.New("authorization header must start with ApiKey")
+	}
+
+	if token == "" {
+		return "", errors.New("API Key is empty")
+	}
+
+	return token, nil
+}
+
+func MakeRefreshToken() (string, error) {
+	token := make([]byte, 32)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", errors.New("Could not generate random data")
+	}
+
+	encodedToken := hex.EncodeToString(token)
+
+	return encodedToken, nil
+}
+
+// HashRefreshToken returns the hex SHA-256 digest under which a refresh token
+// is stored. Tokens are 32 random bytes, so an unsalted fast hash is enough.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,27 +1,27 @@
-package auth
-
-import (
-	"log"
-
-	"github.com/alexedwards/argon2id"
-)
-
-func HashPassword(password string) (string, error) {
-	hash, err := argon2id.CreateHash(password, argon2id.DefaultParams)
-	if err != nil {
-		log.Printf("Error creating hash: %v", err)
-		return "", err
-	}
-
-	return hash, nil
-}
-
-func CheckPasswordHash(password, hash string) (bool, error) {
-	match, err := argon2id.ComparePasswordAndHash(password, hash)
-	if err != nil {
-		log.Printf("Error validating password: %v", err)
-		return false, err
-	}
-
-	return match, err
-}
+package auth
+
+import (
+	"log"
+
+	"github.com/alexedwards/argon2id"
+)
+
+func HashPassword(password string) (string, error) {
+	hash, err := argon2id.CreateHash(password, argon2id.DefaultParams)
+	if err != nil {
+		log.Printf("Error creating hash: %v", err)
+		return "", err
+	}
+
+	return hash, nil
+}
+
+func CheckPasswordHash(password, hash string) (bool, error) {
+	match, err := argon2id.ComparePasswordAndHash(password, hash)
+	if err != nil {
+		log.Printf("Error validating password: %v", err)
+		return false, err
+	}
+
+	return match, err
+}
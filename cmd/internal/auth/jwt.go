@@ -1,52 +1,103 @@
-package auth
-
-import (
-	"fmt"
-	"time"
-
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
-)
-
-func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration) (string, error) {
-	claims := &jwt.RegisteredClaims{
-		Issuer:    "chirpy",
-		IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
-		Subject:   userID.String(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	tokenString, err := token.SignedString([]byte(tokenSecret))
-	if err != nil {
-		return "", err
-	}
-
-	return tokenString, nil
-}
-
-func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
-	token, err := jwt.ParseWithClaims(
-		tokenString,
-		&jwt.RegisteredClaims{},
-		func(token *jwt.Token) (any, error) {
-			return []byte(tokenSecret), nil
-		},
-	)
-	if err != nil {
-		return uuid.Nil, err
-	}
-
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
-	if !ok {
-		return uuid.Nil, fmt.Errorf("invalid token claims")
-	}
-
-	userID, err := uuid.Parse(claims.Subject)
-	if err != nil {
-		return uuid.Nil, err
-	}
-
-	return userID, nil
-}
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ErrTokenRevoked is returned for access tokens minted before their owner
+// logged out everywhere.
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// TokenVersions looks up a user's current token version. Logging out
+// everywhere bumps the version, which invalidates every access token
+// issued before.
+type TokenVersions interface {
+	GetUserTokenVersion(ctx context.Context, id uuid.UUID) (int32, error)
+}
+
+type accessClaims struct {
+	jwt.RegisteredClaims
+	Version int32 `json:"ver"`
+}
+
+// MakeJWT signs an access token with the current signing key of keys.
+func MakeJWT(userID uuid.UUID, version int32, keys *KeySet, expiresIn time.Duration) (string, error) {
+	k := keys.current()
+
+	claims := &accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    keys.issuer,
+			Audience:  jwt.ClaimStrings{keys.audience},
+			IssuedAt:  jwt.NewNumericDate(time.Now().UTC()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().UTC().Add(expiresIn)),
+			Subject:   userID.String(),
+		},
+		Version: version,
+	}
+
+	token := jwt.NewWithClaims(k.method, claims)
+	if k.id != "" {
+		token.Header["kid"] = k.id
+	}
+
+	tokenString, err := token.SignedString(k.private)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+// ValidateJWT verifies an access token against any key of keys that is
+// still within its grace window, checks its issuer, audience and expiry,
+// and rejects it if its owner has since logged out everywhere.
+func ValidateJWT(ctx context.Context, tokenString string, keys *KeySet, versions TokenVersions) (uuid.UUID, error) {
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		&accessClaims{},
+		func(token *jwt.Token) (any, error) {
+			kid, _ := token.Header["kid"].(string)
+			k, ok := keys.lookup(kid)
+			if !ok {
+				return nil, fmt.Errorf("unknown signing key %q", kid)
+			}
+			if token.Method.Alg() != k.method.Alg() {
+				return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+			}
+			return k.public, nil
+		},
+		jwt.WithIssuer(keys.issuer),
+		jwt.WithAudience(keys.audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	claims, ok := token.Claims.(*accessClaims)
+	if !ok {
+		return uuid.Nil, fmt.Errorf("invalid token claims")
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	current, err := versions.GetUserTokenVersion(ctx, userID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("looking up token version: %w", err)
+	}
+
+	if claims.Version != current {
+		return uuid.Nil, ErrTokenRevoked
+	}
+
+	return userID, nil
+}
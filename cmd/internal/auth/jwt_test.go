@@ -1,179 +1,218 @@
-package auth
-
-import (
-	"testing"
-	"time"
-
-	"github.com/google/uuid"
-)
-
-func TestMakeJWT(t *testing.T) {
-	userID := uuid.New()
-	secret := "test-secret"
-	expiresIn := time.Hour
-
-	token, err := MakeJWT(userID, secret, expiresIn)
-	if err != nil {
-		t.Fatalf("MakeJWT failed: %v", err)
-	}
-
-	if token == "" {
-		t.Error("Expected non-empty token")
-	}
-
-	// Token should be a valid JWT format (3 parts separated by dots)
-	// This is a basic check - proper validation happens in ValidateJWT tests
-	if len(token) < 10 {
-		t.Error("Token seems too short to be valid")
-	}
-}
-
-func TestValidateJWT_Success(t *testing.T) {
-	userID := uuid.New()
-	secret := "test-secret"
-	expiresIn := time.Hour
-
-	// Create a valid token
-	token, err := MakeJWT(userID, secret, expiresIn)
-	if err != nil {
-		t.Fatalf("MakeJWT failed: %v", err)
-	}
-
-	// Validate the token
-	parsedUserID, err := ValidateJWT(token, secret)
-	if err != nil {
-		t.Fatalf("ValidateJWT failed: %v", err)
-	}
-
-	// Check that we get back the same user ID
-	if parsedUserID != userID {
-		t.Errorf("Expected user ID %v, got %v", userID, parsedUserID)
-	}
-}
-
-func TestValidateJWT_ExpiredToken(t *testing.T) {
-	userID := uuid.New()
-	secret := "test-secret"
-	expiresIn := -time.Hour // Token expired 1 hour ago
-
-	// Create an already-expired token
-	token, err := MakeJWT(userID, secret, expiresIn)
-	if err != nil {
-		t.Fatalf("MakeJWT failed: %v", err)
-	}
-
-	// Try to validate the expired token
-	_, err = ValidateJWT(token, secret)
-	if err == nil {
-		t.Error("Expected error for expired token, got nil")
-	}
-}
-
-func TestValidateJWT_WrongSecret(t *testing.T) {
-	userID := uuid.New()
-	correctSecret := "correct-secret"
-	wrongSecret := "wrong-secret"
-	expiresIn := time.Hour
-
-	// Create token with correct secret
-	token, err := MakeJWT(userID, correctSecret, expiresIn)
-	if err != nil {
-		t.Fatalf("MakeJWT failed: %v", err)
-	}
-
-	// Try to validate with wrong secret
-	_, err = ValidateJWT(token, wrongSecret)
-	if err == nil {
-		t.Error("Expected error for wrong secret, got nil")
-	}
-}
-
-func TestValidateJWT_InvalidToken(t *testing.T) {
-	secret := "test-secret"
-	invalidToken := "this.is.invalid"
-
-	_, err := ValidateJWT(invalidToken, secret)
-	if err == nil {
-		t.Error("Expected error for invalid token format, got nil")
-	}
-}
-
-func TestValidateJWT_EmptyToken(t *testing.T) {
-	secret := "test-secret"
-	emptyToken := ""
-
-	_, err := ValidateJWT(emptyToken, secret)
-	if err == nil {
-		t.Error("Expected error for empty token, got nil")
-	}
-}
-
-func TestValidateJWT_MalformedToken(t *testing.T) {
-	secret := "test-secret"
-	malformedToken := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.invalid.signature"
-
-	_, err := ValidateJWT(malformedToken, secret)
-	if err == nil {
-		t.Error("Expected error for malformed token, got nil")
-	}
-}
-
-func TestMakeJWT_ShortExpiration(t *testing.T) {
-	userID := uuid.New()
-	secret := "test-secret"
-	expiresIn := time.Millisecond * 100
-
-	token, err := MakeJWT(userID, secret, expiresIn)
-	if err != nil {
-		t.Fatalf("MakeJWT failed: %v", err)
-	}
-
-	// Wait for token to expire
-	time.Sleep(time.Millisecond * 200)
-
-	// Token should be expired now
-	_, err = ValidateJWT(token, secret)
-	if err == nil {
-		t.Error("Expected error for expired token, got nil")
-	}
-}
-
-func TestMakeJWT_DifferentUserIDs(t *testing.T) {
-	secret := "test-secret"
-	expiresIn := time.Hour
-
-	userID1 := uuid.New()
-	userID2 := uuid.New()
-
-	token1, err := MakeJWT(userID1, secret, expiresIn)
-	if err != nil {
-		t.Fatalf("MakeJWT failed for user 1: %v", err)
-	}
-
-	token2, err := MakeJWT(userID2, secret, expiresIn)
-	if err != nil {
-		t.Fatalf("MakeJWT failed for user 2: %v", err)
-	}
-
-	// Tokens should be different
-	if token1 == token2 {
-		t.Error("Expected different tokens for different users")
-	}
-
-	// Validate both tokens return correct user IDs
-	parsedID1, err := ValidateJWT(token1, secret)
-	if err != nil {
-		t.Fatalf("ValidateJWT failed for token1: %v", err)
-	}
-	if parsedID1 != userID1 {
-		t.Errorf("Expected user ID %v, got %v", userID1, parsedID1)
-	}
-
-	parsedID2, err := ValidateJWT(token2, secret)
-	if err != nil {
-		t.Fatalf("ValidateJWT failed for token2: %v", err)
-	}
-	if parsedID2 != userID2 {
-		t.Errorf("Expected user ID %v, got %v", userID2, parsedID2)
-	}
-}
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// versionMap serves token versions from memory; unknown users are at 0.
+type versionMap map[uuid.UUID]int32
+
+func (m versionMap) GetUserTokenVersion(_ context.Context, id uuid.UUID) (int32, error) {
+	return m[id], nil
+}
+
+func TestMakeJWT(t *testing.T) {
+	userID := uuid.New()
+	keys := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+	expiresIn := time.Hour
+
+	token, err := MakeJWT(userID, 0, keys, expiresIn)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+
+	if token == "" {
+		t.Error("Expected non-empty token")
+	}
+
+	// Token should be a valid JWT format (3 parts separated by dots)
+	// This is a basic check - proper validation happens in ValidateJWT tests
+	if len(token) < 10 {
+		t.Error("Token seems too short to be valid")
+	}
+}
+
+func TestValidateJWT_Success(t *testing.T) {
+	userID := uuid.New()
+	keys := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+	expiresIn := time.Hour
+
+	// Create a valid token
+	token, err := MakeJWT(userID, 0, keys, expiresIn)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+
+	// Validate the token
+	parsedUserID, err := ValidateJWT(context.Background(), token, keys, versionMap{})
+	if err != nil {
+		t.Fatalf("ValidateJWT failed: %v", err)
+	}
+
+	// Check that we get back the same user ID
+	if parsedUserID != userID {
+		t.Errorf("Expected user ID %v, got %v", userID, parsedUserID)
+	}
+}
+
+func TestValidateJWT_ExpiredToken(t *testing.T) {
+	userID := uuid.New()
+	keys := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+	expiresIn := -time.Hour // Token expired 1 hour ago
+
+	// Create an already-expired token
+	token, err := MakeJWT(userID, 0, keys, expiresIn)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+
+	// Try to validate the expired token
+	_, err = ValidateJWT(context.Background(), token, keys, versionMap{})
+	if err == nil {
+		t.Error("Expected error for expired token, got nil")
+	}
+}
+
+func TestValidateJWT_WrongSecret(t *testing.T) {
+	userID := uuid.New()
+	correctKeys := NewHMACKeySet("correct-secret", DefaultIssuer, DefaultAudience)
+	wrongKeys := NewHMACKeySet("wrong-secret", DefaultIssuer, DefaultAudience)
+	expiresIn := time.Hour
+
+	// Create token with correct secret
+	token, err := MakeJWT(userID, 0, correctKeys, expiresIn)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+
+	// Try to validate with wrong secret
+	_, err = ValidateJWT(context.Background(), token, wrongKeys, versionMap{})
+	if err == nil {
+		t.Error("Expected error for wrong secret, got nil")
+	}
+}
+
+func TestValidateJWT_InvalidToken(t *testing.T) {
+	keys := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+	invalidToken := "this.is.invalid"
+
+	_, err := ValidateJWT(context.Background(), invalidToken, keys, versionMap{})
+	if err == nil {
+		t.Error("Expected error for invalid token format, got nil")
+	}
+}
+
+func TestValidateJWT_EmptyToken(t *testing.T) {
+	keys := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+	emptyToken := ""
+
+	_, err := ValidateJWT(context.Background(), emptyToken, keys, versionMap{})
+	if err == nil {
+		t.Error("Expected error for empty token, got nil")
+	}
+}
+
+func TestValidateJWT_MalformedToken(t *testing.T) {
+	keys := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+	malformedToken := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.invalid.signature"
+
+	_, err := ValidateJWT(context.Background(), malformedToken, keys, versionMap{})
+	if err == nil {
+		t.Error("Expected error for malformed token, got nil")
+	}
+}
+
+func TestMakeJWT_ShortExpiration(t *testing.T) {
+	userID := uuid.New()
+	keys := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+	expiresIn := time.Millisecond * 100
+
+	token, err := MakeJWT(userID, 0, keys, expiresIn)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+
+	// Wait for token to expire
+	time.Sleep(time.Millisecond * 200)
+
+	// Token should be expired now
+	_, err = ValidateJWT(context.Background(), token, keys, versionMap{})
+	if err == nil {
+		t.Error("Expected error for expired token, got nil")
+	}
+}
+
+func TestMakeJWT_DifferentUserIDs(t *testing.T) {
+	keys := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+	expiresIn := time.Hour
+
+	userID1 := uuid.New()
+	userID2 := uuid.New()
+
+	token1, err := MakeJWT(userID1, 0, keys, expiresIn)
+	if err != nil {
+		t.Fatalf("MakeJWT failed for user 1: %v", err)
+	}
+
+	token2, err := MakeJWT(userID2, 0, keys, expiresIn)
+	if err != nil {
+		t.Fatalf("MakeJWT failed for user 2: %v", err)
+	}
+
+	// Tokens should be different
+	if token1 == token2 {
+		t.Error("Expected different tokens for different users")
+	}
+
+	// Validate both tokens return correct user IDs
+	parsedID1, err := ValidateJWT(context.Background(), token1, keys, versionMap{})
+	if err != nil {
+		t.Fatalf("ValidateJWT failed for token1: %v", err)
+	}
+	if parsedID1 != userID1 {
+		t.Errorf("Expected user ID %v, got %v", userID1, parsedID1)
+	}
+
+	parsedID2, err := ValidateJWT(context.Background(), token2, keys, versionMap{})
+	if err != nil {
+		t.Fatalf("ValidateJWT failed for token2: %v", err)
+	}
+	if parsedID2 != userID2 {
+		t.Errorf("Expected user ID %v, got %v", userID2, parsedID2)
+	}
+}
+
+func TestValidateJWT_RevokedVersion(t *testing.T) {
+	userID := uuid.New()
+	keys := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+	versions := versionMap{userID: 2}
+
+	stale, err := MakeJWT(userID, 1, keys, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+
+	// Logging out everywhere bumped the version past the one in the token
+	_, err = ValidateJWT(context.Background(), stale, keys, versions)
+	if !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected %v for stale token, got %v", ErrTokenRevoked, err)
+	}
+
+	current, err := MakeJWT(userID, 2, keys, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+
+	parsedUserID, err := ValidateJWT(context.Background(), current, keys, versions)
+	if err != nil {
+		t.Fatalf("ValidateJWT failed: %v", err)
+	}
+	if parsedUserID != userID {
+		t.Errorf("Expected user ID %v, got %v", userID, parsedUserID)
+	}
+}
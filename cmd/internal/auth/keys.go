@@ -0,0 +1,356 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgEdDSA = "EdDSA"
+	AlgRS256 = "RS256"
+
+	DefaultIssuer            = "chirpy"
+	DefaultAudience          = "chirpy-api"
+	DefaultKeyGrace          = 24 * time.Hour
+	DefaultKeyReloadInterval = 5 * time.Minute
+
+	rsaKeyBits = 2048
+	// An unknown kid may belong to a key another instance just rotated in,
+	// so it triggers a reload, but not more often than this.
+	minReloadGap = 5 * time.Second
+)
+
+// KeyDirOptions configures a KeySet backed by a directory of PEM encoded
+// PKCS #8 private keys. Each file is one key; its name without the .pem
+// extension is the kid and its modification time is when it took over
+// signing.
+type KeyDirOptions struct {
+	Dir string
+	// Algorithm of keys generated by rotation, AlgEdDSA or AlgRS256.
+	Algorithm string
+	// Rotation is how old the newest key may get before a new one is
+	// generated. Zero leaves rotation to whoever manages the directory.
+	Rotation time.Duration
+	// Grace is how long a key keeps verifying after a newer one took over.
+	// It must outlast the access token lifetime.
+	Grace time.Duration
+}
+
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private any
+	public  any
+	created time.Time
+}
+
+// KeySet holds the key that signs access tokens and every key that still
+// verifies them. It is either a single HMAC secret or a key directory.
+type KeySet struct {
+	issuer   string
+	audience string
+	opts     KeyDirOptions
+
+	// reloadMu serializes reloads so two of them never both rotate.
+	reloadMu sync.Mutex
+
+	mu       sync.RWMutex
+	signing  *signingKey
+	verify   map[string]*signingKey
+	loadedAt time.Time
+}
+
+// NewHMACKeySet signs and verifies tokens with a single HS256 secret.
+func NewHMACKeySet(secret, issuer, audience string) *KeySet {
+	k := &signingKey{
+		method:  jwt.SigningMethodHS256,
+		private: []byte(secret),
+		public:  []byte(secret),
+	}
+
+	return &KeySet{
+		issuer:   issuer,
+		audience: audience,
+		signing:  k,
+		verify:   map[string]*signingKey{"": k},
+	}
+}
+
+// LoadKeyDir loads the keys in opts.Dir, generating a first key when the
+// directory holds none.
+func LoadKeyDir(opts KeyDirOptions, issuer, audience string) (*KeySet, error) {
+	if opts.Algorithm == "" {
+		opts.Algorithm = AlgEdDSA
+	}
+	if opts.Algorithm != AlgEdDSA && opts.Algorithm != AlgRS256 {
+		return nil, fmt.Errorf("unsupported key algorithm %q", opts.Algorithm)
+	}
+	if opts.Grace <= 0 {
+		opts.Grace = DefaultKeyGrace
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	ks := &KeySet{
+		issuer:   issuer,
+		audience: audience,
+		opts:     opts,
+	}
+	if err := ks.reload(); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// Watch reloads the key directory every interval, generating a new key
+// whenever rotation is due. It returns when ctx is cancelled and does
+// nothing for HMAC key sets.
+func (ks *KeySet) Watch(ctx context.Context, interval time.Duration) {
+	if ks.opts.Dir == "" {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultKeyReloadInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ks.reload(); err != nil {
+				log.Printf("Error reloading signing keys: %v", err)
+			}
+		}
+	}
+}
+
+func (ks *KeySet) reload() error {
+	ks.reloadMu.Lock()
+	defer ks.reloadMu.Unlock()
+
+	keys, err := readKeyDir(ks.opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if len(keys) == 0 || (ks.opts.Rotation > 0 && now.Sub(keys[len(keys)-1].created) >= ks.opts.Rotation) {
+		k, err := generateKey(ks.opts.Dir, ks.opts.Algorithm)
+		if err != nil {
+			return fmt.Errorf("rotating signing key: %w", err)
+		}
+		log.Printf("Generated %s signing key %s", ks.opts.Algorithm, k.id)
+		keys = append(keys, k)
+	}
+
+	verify := make(map[string]*signingKey, len(keys))
+	for i, k := range keys {
+		if i+1 < len(keys) && now.After(keys[i+1].created.Add(ks.opts.Grace)) {
+			// Retired long enough that no token it signed is still valid.
+			if ks.opts.Rotation > 0 {
+				if err := os.Remove(filepath.Join(ks.opts.Dir, k.id+".pem")); err != nil {
+					log.Printf("Error removing retired signing key %s: %v", k.id, err)
+				}
+			}
+			continue
+		}
+		verify[k.id] = k
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.signing = keys[len(keys)-1]
+	ks.verify = verify
+	ks.loadedAt = now
+	return nil
+}
+
+// readKeyDir returns the keys in dir, oldest first.
+func readKeyDir(dir string) ([]*signingKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*signingKey
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pem" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		k, err := parseKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		k.id = strings.TrimSuffix(entry.Name(), ".pem")
+		k.created = info.ModTime()
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].created.Equal(keys[j].created) {
+			return keys[i].created.Before(keys[j].created)
+		}
+		return keys[i].id < keys[j].id
+	})
+
+	return keys, nil
+}
+
+func parseKey(data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("expected a PEM encoded PKCS #8 private key")
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch private := private.(type) {
+	case ed25519.PrivateKey:
+		return &signingKey{
+			method:  jwt.SigningMethodEdDSA,
+			private: private,
+			public:  private.Public(),
+		}, nil
+	case *rsa.PrivateKey:
+		if private.N.BitLen() < rsaKeyBits {
+			return nil, fmt.Errorf("RSA keys must be at least %d bits", rsaKeyBits)
+		}
+		return &signingKey{
+			method:  jwt.SigningMethodRS256,
+			private: private,
+			public:  &private.PublicKey,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", private)
+	}
+}
+
+func generateKey(dir, algorithm string) (*signingKey, error) {
+	var private any
+	switch algorithm {
+	case AlgEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	case AlgRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	k, err := parseKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	k.id = time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix)
+
+	// Write under a name readKeyDir skips, then rename, so other instances
+	// never load a half written key.
+	tmp, err := os.CreateTemp(dir, ".key-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, k.id+".pem")
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	k.created = info.ModTime()
+	return k, nil
+}
+
+func (ks *KeySet) current() *signingKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.signing
+}
+
+// lookup returns the verification key for kid. Unknown kids trigger a
+// reload in case another instance has just rotated.
+func (ks *KeySet) lookup(kid string) (*signingKey, bool) {
+	ks.mu.RLock()
+	k, ok := ks.verify[kid]
+	stale := ks.opts.Dir != "" && time.Since(ks.loadedAt) > minReloadGap
+	ks.mu.RUnlock()
+
+	if ok || !stale {
+		return k, ok
+	}
+
+	if err := ks.reload(); err != nil {
+		log.Printf("Error reloading signing keys: %v", err)
+		return nil, false
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	k, ok = ks.verify[kid]
+	return k, ok
+}
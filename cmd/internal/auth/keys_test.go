@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestLoadKeyDir(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		wantAlg   string
+		wantKty   string
+	}{
+		{name: "eddsa", algorithm: AlgEdDSA, wantAlg: "EdDSA", wantKty: "OKP"},
+		{name: "rs256", algorithm: AlgRS256, wantAlg: "RS256", wantKty: "RSA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := LoadKeyDir(KeyDirOptions{Dir: t.TempDir(), Algorithm: tt.algorithm}, DefaultIssuer, DefaultAudience)
+			if err != nil {
+				t.Fatalf("LoadKeyDir failed: %v", err)
+			}
+
+			userID := uuid.New()
+			token, err := MakeJWT(userID, 0, keys, time.Hour)
+			if err != nil {
+				t.Fatalf("MakeJWT failed: %v", err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &accessClaims{})
+			if err != nil {
+				t.Fatalf("ParseUnverified failed: %v", err)
+			}
+			if parsed.Header["alg"] != tt.wantAlg {
+				t.Errorf("alg = %v, want %s", parsed.Header["alg"], tt.wantAlg)
+			}
+			if parsed.Header["kid"] != keys.current().id {
+				t.Errorf("kid = %v, want %s", parsed.Header["kid"], keys.current().id)
+			}
+
+			gotUserID, err := ValidateJWT(context.Background(), token, keys, versionMap{})
+			if err != nil {
+				t.Fatalf("ValidateJWT failed: %v", err)
+			}
+			if gotUserID != userID {
+				t.Errorf("user ID = %v, want %v", gotUserID, userID)
+			}
+
+			set := keys.JWKS()
+			if len(set.Keys) != 1 {
+				t.Fatalf("JWKS has %d keys, want 1", len(set.Keys))
+			}
+			if k := set.Keys[0]; k.KeyType != tt.wantKty || k.Algorithm != tt.wantAlg || k.KeyID != keys.current().id || k.Use != "sig" {
+				t.Errorf("JWKS key = %+v", k)
+			}
+		})
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+	opts := KeyDirOptions{Dir: dir, Algorithm: AlgEdDSA, Rotation: time.Hour, Grace: 30 * time.Minute}
+
+	keys, err := LoadKeyDir(opts, DefaultIssuer, DefaultAudience)
+	if err != nil {
+		t.Fatalf("LoadKeyDir failed: %v", err)
+	}
+	first := keys.current().id
+	userID := uuid.New()
+
+	oldToken, err := MakeJWT(userID, 0, keys, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+
+	// Age the first key past the rotation period
+	age := func(kid string, d time.Duration) {
+		t.Helper()
+		at := time.Now().Add(-d)
+		if err := os.Chtimes(filepath.Join(dir, kid+".pem"), at, at); err != nil {
+			t.Fatalf("Chtimes failed: %v", err)
+		}
+	}
+	age(first, 2*time.Hour)
+
+	if err := keys.reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	second := keys.current().id
+	if second == first {
+		t.Fatal("Expected a new signing key after rotation")
+	}
+
+	// Within the grace window both keys verify and both are published
+	if _, err := ValidateJWT(context.Background(), oldToken, keys, versionMap{}); err != nil {
+		t.Errorf("old token rejected during grace window: %v", err)
+	}
+	if n := len(keys.JWKS().Keys); n != 2 {
+		t.Errorf("JWKS has %d keys during grace window, want 2", n)
+	}
+
+	// Once the successor has signed for longer than the grace window the old
+	// key is dropped
+	age(second, 45*time.Minute)
+	if err := keys.reload(); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if _, err := ValidateJWT(context.Background(), oldToken, keys, versionMap{}); err == nil {
+		t.Error("Expected old token to be rejected after grace window")
+	}
+	if n := len(keys.JWKS().Keys); n != 1 {
+		t.Errorf("JWKS has %d keys after grace window, want 1", n)
+	}
+	if _, err := os.Stat(filepath.Join(dir, first+".pem")); !os.IsNotExist(err) {
+		t.Errorf("retired key file still present: %v", err)
+	}
+}
+
+func TestValidateJWT_Claims(t *testing.T) {
+	dir := t.TempDir()
+	keys, err := LoadKeyDir(KeyDirOptions{Dir: dir}, DefaultIssuer, DefaultAudience)
+	if err != nil {
+		t.Fatalf("LoadKeyDir failed: %v", err)
+	}
+	other, err := LoadKeyDir(KeyDirOptions{Dir: t.TempDir()}, DefaultIssuer, DefaultAudience)
+	if err != nil {
+		t.Fatalf("LoadKeyDir failed: %v", err)
+	}
+	sameKeyOtherIssuer, err := LoadKeyDir(KeyDirOptions{Dir: dir}, "someone-else", DefaultAudience)
+	if err != nil {
+		t.Fatalf("LoadKeyDir failed: %v", err)
+	}
+	sameKeyOtherAudience, err := LoadKeyDir(KeyDirOptions{Dir: dir}, DefaultIssuer, "another-api")
+	if err != nil {
+		t.Fatalf("LoadKeyDir failed: %v", err)
+	}
+	hmac := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+
+	tests := []struct {
+		name   string
+		signer *KeySet
+	}{
+		{name: "unknown kid", signer: other},
+		{name: "wrong issuer", signer: sameKeyOtherIssuer},
+		{name: "wrong audience", signer: sameKeyOtherAudience},
+		{name: "hmac token against key directory", signer: hmac},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := MakeJWT(uuid.New(), 0, tt.signer, time.Hour)
+			if err != nil {
+				t.Fatalf("MakeJWT failed: %v", err)
+			}
+
+			if _, err := ValidateJWT(context.Background(), token, keys, versionMap{}); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestHMACKeySetJWKS(t *testing.T) {
+	keys := NewHMACKeySet("test-secret", DefaultIssuer, DefaultAudience)
+	if n := len(keys.JWKS().Keys); n != 0 {
+		t.Errorf("JWKS has %d keys, want the HMAC secret to stay private", n)
+	}
+}
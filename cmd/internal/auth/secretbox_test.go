@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func newTestSecretBox(t *testing.T, fill byte) *SecretBox {
+	t.Helper()
+	box, err := NewSecretBox(bytes.Repeat([]byte{fill}, SecretKeySize))
+	if err != nil {
+		t.Fatalf("NewSecretBox failed: %v", err)
+	}
+	return box
+}
+
+func TestSecretBoxRoundTrip(t *testing.T) {
+	box := newTestSecretBox(t, 1)
+
+	sealed, err := box.Seal("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	if strings.Contains(sealed, "JBSWY3DPEHPK3PXP") {
+		t.Fatalf("sealed value %q contains the plaintext", sealed)
+	}
+
+	again, err := box.Seal("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	if again == sealed {
+		t.Error("Expected each seal to use a fresh nonce")
+	}
+
+	opened, err := box.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if opened != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Open = %q, want the original secret", opened)
+	}
+}
+
+func TestSecretBoxOpen(t *testing.T) {
+	box := newTestSecretBox(t, 1)
+	sealed, err := box.Seal("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+
+	if opened, err := box.Open("JBSWY3DPEHPK3PXP"); err != nil || opened != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Open(legacy) = (%q, %v), want the value unchanged", opened, err)
+	}
+	if _, err := newTestSecretBox(t, 2).Open(sealed); err != ErrSealedSecret {
+		t.Errorf("Open with another key error = %v, want ErrSealedSecret", err)
+	}
+	if _, err := box.Open(sealed[:len(sealed)-2]); err != ErrSealedSecret {
+		t.Errorf("Open(truncated) error = %v, want ErrSealedSecret", err)
+	}
+}
+
+func TestParseSecretKey(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, SecretKeySize)
+
+	got, err := ParseSecretKey(base64.StdEncoding.EncodeToString(key) + "\n")
+	if err != nil || !bytes.Equal(got, key) {
+		t.Errorf("ParseSecretKey = (%v, %v), want the decoded key", got, err)
+	}
+	if _, err := ParseSecretKey("not base64!"); err == nil {
+		t.Error("Expected an error for invalid base64")
+	}
+	if _, err := NewSecretBox(key[:16]); err == nil {
+		t.Error("Expected an error for a short key")
+	}
+}
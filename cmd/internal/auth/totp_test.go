@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The SHA-1 test vectors from RFC 6238 appendix B.
+var rfc6238Secret = []byte("12345678901234567890")
+
+func TestHOTP_RFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "94287082"},
+		{unix: 1111111109, want: "07081804"},
+		{unix: 1111111111, want: "14050471"},
+		{unix: 1234567890, want: "89005924"},
+		{unix: 2000000000, want: "69279037"},
+		{unix: 20000000000, want: "65353130"},
+	}
+
+	for _, tt := range tests {
+		if got := hotp(rfc6238Secret, uint64(tt.unix/30), 8); got != tt.want {
+			t.Errorf("hotp at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238Secret)
+	now := time.Unix(1111111111, 0)
+
+	tests := []struct {
+		name     string
+		code     string
+		at       time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", code: "050471", at: now, wantStep: 37037037, wantOK: true},
+		{name: "spaces ignored", code: "050 471", at: now, wantStep: 37037037, wantOK: true},
+		{name: "previous step within skew", code: "050471", at: now.Add(TOTPPeriod), wantStep: 37037037, wantOK: true},
+		{name: "too old", code: "050471", at: now.Add(2 * TOTPPeriod)},
+		{name: "wrong code", code: "123456", at: now},
+		{name: "wrong length", code: "14050471", at: now},
+		{name: "empty", code: "", at: now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(secret, tt.code, tt.at)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret failed: %v", err)
+	}
+
+	now := time.Now()
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret is not base32: %v", err)
+	}
+	code := hotp(key, uint64(now.Unix()/30), TOTPDigits)
+
+	if _, ok := ValidateTOTP(secret, code, now); !ok {
+		t.Error("Expected a code generated from the secret to validate")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("Chirpy", "alice@example.com", "JBSWY3DPEHPK3PXP")
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("url.Parse failed: %v", err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Chirpy:alice@example.com" {
+		t.Errorf("URI = %s", uri)
+	}
+
+	q := u.Query()
+	for key, want := range map[string]string{
+		"secret": "JBSWY3DPEHPK3PXP",
+		"issuer": "Chirpy",
+		"digits": "6",
+		"period": "30",
+	} {
+		if got := q.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes failed: %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("got %d codes, want 10", len(codes))
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		groups := strings.Split(code, "-")
+		if len(groups) != 4 {
+			t.Errorf("code %q is not four dash-separated groups", code)
+		}
+		for _, group := range groups {
+			if len(group) != recoveryCodeGroup {
+				t.Errorf("code %q has a group of %d characters, want %d", code, len(group), recoveryCodeGroup)
+			}
+		}
+		if seen[code] {
+			t.Errorf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+
+	code := codes[0]
+	loose := strings.ToUpper(strings.ReplaceAll(code, "-", " "))
+	if HashRecoveryCode(loose) != HashRecoveryCode(code) {
+		t.Error("Expected case, spaces and dashes to be ignored")
+	}
+	if HashRecoveryCode(codes[1]) == HashRecoveryCode(code) {
+		t.Error("Expected different codes to hash differently")
+	}
+}
@@ -1,24 +1,48 @@
-package config
-
-import (
-	"sync/atomic"
-
-	"github.com/shubh-man007/Chirpy/cmd/internal/database"
-)
-
-type ApiConfig struct {
-	FileserverHits atomic.Int32
-	DB             *database.Queries
-	Platform       string
-	JWTSecret      string
-	PolkaAPIKey    string
-}
-
-func NewApiCfg(db *database.Queries, platform string, jwtSecret string, polkaAPI string) *ApiConfig {
-	return &ApiConfig{
-		DB:          db,
-		Platform:    platform,
-		JWTSecret:   jwtSecret,
-		PolkaAPIKey: polkaAPI,
-	}
-}
+package config
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+	"github.com/shubh-man007/Chirpy/cmd/internal/moderation"
+	"github.com/shubh-man007/Chirpy/cmd/internal/storage"
+	"github.com/shubh-man007/Chirpy/cmd/internal/stream"
+	"github.com/shubh-man007/Chirpy/cmd/internal/timeline"
+)
+
+const (
+	// DefaultEditWindow is how long after posting a chirp can be edited.
+	DefaultEditWindow = 30 * time.Minute
+	// DefaultTrashRetention is how long deleted chirps can be restored.
+	DefaultTrashRetention = 30 * 24 * time.Hour
+	// DefaultAccountGracePeriod is how long a deleted account can be
+	// recovered by logging in.
+	DefaultAccountGracePeriod = 14 * 24 * time.Hour
+)
+
+type ApiConfig struct {
+	FileserverHits atomic.Int32
+	DB             *database.Queries
+	Platform       string
+	Keys           *auth.KeySet
+	TOTPSecrets    *auth.SecretBox
+	PolkaAPIKey    string
+	Blobs          storage.BlobStore
+	Stream         *stream.Hub
+	Timeline       *timeline.Worker
+	Filter         *moderation.Engine
+	EditWindow     time.Duration
+	TrashRetention time.Duration
+	AccountGrace   time.Duration
+}
+
+func NewApiCfg(db *database.Queries, platform string, keys *auth.KeySet, polkaAPI string) *ApiConfig {
+	return &ApiConfig{
+		DB:          db,
+		Platform:    platform,
+		Keys:        keys,
+		PolkaAPIKey: polkaAPI,
+	}
+}
@@ -7,29 +7,87 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 const createChirp = `-- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, parent_id, root_id, kind, original_id)
 VALUES (
     gen_random_uuid(),
     NOW(),
     NOW(),
     $1,
-    $2
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
 )
-RETURNING id, created_at, updated_at, body, user_id
+RETURNING id, created_at, updated_at, body, user_id, parent_id, root_id, kind, original_id, search_vector, edit_count, edited, deleted_at
 `
 
 type CreateChirpParams struct {
-	Body   string    `json:"body"`
-	UserID uuid.UUID `json:"user_id"`
+	Body       string        `json:"body"`
+	UserID     uuid.UUID     `json:"user_id"`
+	ParentID   uuid.NullUUID `json:"parent_id"`
+	RootID     uuid.NullUUID `json:"root_id"`
+	Kind       string        `json:"kind"`
+	OriginalID uuid.NullUUID `json:"original_id"`
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID)
+	row := q.db.QueryRowContext(ctx, createChirp,
+		arg.Body,
+		arg.UserID,
+		arg.ParentID,
+		arg.RootID,
+		arg.Kind,
+		arg.OriginalID,
+	)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ParentID,
+		&i.RootID,
+		&i.Kind,
+		&i.OriginalID,
+		&i.SearchVector,
+		&i.EditCount,
+		&i.Edited,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createRechirp = `-- name: CreateRechirp :one
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, kind, original_id)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    '',
+    $1,
+    'rechirp',
+    $2
+)
+ON CONFLICT (user_id, original_id) WHERE kind = 'rechirp' DO NOTHING
+RETURNING id, created_at, updated_at, body, user_id, parent_id, root_id, kind, original_id, search_vector, edit_count, edited, deleted_at
+`
+
+type CreateRechirpParams struct {
+	UserID     uuid.UUID     `json:"user_id"`
+	OriginalID uuid.NullUUID `json:"original_id"`
+}
+
+func (q *Queries) CreateRechirp(ctx context.Context, arg CreateRechirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, createRechirp, arg.UserID, arg.OriginalID)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -37,6 +95,14 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.ParentID,
+		&i.RootID,
+		&i.Kind,
+		&i.OriginalID,
+		&i.SearchVector,
+		&i.EditCount,
+		&i.Edited,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -50,25 +116,102 @@ func (q *Queries) DeleteChirp(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
-const getAllChirps = `-- name: GetAllChirps :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps ORDER BY created_at ASC
+const deleteRechirp = `-- name: DeleteRechirp :execrows
+DELETE FROM chirps
+WHERE user_id = $1 AND original_id = $2 AND kind = 'rechirp'
 `
 
-func (q *Queries) GetAllChirps(ctx context.Context) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getAllChirps)
+type DeleteRechirpParams struct {
+	UserID     uuid.UUID     `json:"user_id"`
+	OriginalID uuid.NullUUID `json:"original_id"`
+}
+
+func (q *Queries) DeleteRechirp(ctx context.Context, arg DeleteRechirpParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRechirp, arg.UserID, arg.OriginalID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getChirp = `-- name: GetChirp :one
+SELECT id, created_at, updated_at, body, user_id, parent_id, root_id, kind, original_id, search_vector, edit_count, edited, deleted_at FROM chirps WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirp, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ParentID,
+		&i.RootID,
+		&i.Kind,
+		&i.OriginalID,
+		&i.SearchVector,
+		&i.EditCount,
+		&i.Edited,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getChirpAncestors = `-- name: GetChirpAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT p.id, p.created_at, p.updated_at, p.edit_count, p.edited, p.body, p.user_id, p.parent_id, p.root_id, p.deleted_at, 1 AS depth
+    FROM chirps p
+    WHERE p.id = (SELECT c.parent_id FROM chirps c WHERE c.id = $1)
+    UNION ALL
+    SELECT p.id, p.created_at, p.updated_at, p.edit_count, p.edited, p.body, p.user_id, p.parent_id, p.root_id, p.deleted_at, a.depth + 1
+    FROM chirps p
+    INNER JOIN ancestors a ON p.id = a.parent_id
+)
+SELECT id, created_at, updated_at, edit_count, edited, body, user_id, parent_id, root_id
+FROM ancestors
+WHERE deleted_at IS NULL
+AND can_view_chirps(user_id, $2)
+ORDER BY depth DESC
+`
+
+type GetChirpAncestorsParams struct {
+	ChirpID  uuid.UUID     `json:"chirp_id"`
+	ViewerID uuid.NullUUID `json:"viewer_id"`
+}
+
+type GetChirpAncestorsRow struct {
+	ID        uuid.UUID     `json:"id"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	EditCount int32         `json:"edit_count"`
+	Edited    bool          `json:"edited"`
+	Body      string        `json:"body"`
+	UserID    uuid.UUID     `json:"user_id"`
+	ParentID  uuid.NullUUID `json:"parent_id"`
+	RootID    uuid.NullUUID `json:"root_id"`
+}
+
+func (q *Queries) GetChirpAncestors(ctx context.Context, arg GetChirpAncestorsParams) ([]GetChirpAncestorsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpAncestors, arg.ChirpID, arg.ViewerID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Chirp
+	var items []GetChirpAncestorsRow
 	for rows.Next() {
-		var i Chirp
+		var i GetChirpAncestorsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EditCount,
+			&i.Edited,
 			&i.Body,
 			&i.UserID,
+			&i.ParentID,
+			&i.RootID,
 		); err != nil {
 			return nil, err
 		}
@@ -83,29 +226,192 @@ func (q *Queries) GetAllChirps(ctx context.Context) ([]Chirp, error) {
 	return items, nil
 }
 
-const getChirp = `-- name: GetChirp :one
-SELECT id, created_at, updated_at, body, user_id FROM chirps WHERE id = $1
+const getChirpDescendants = `-- name: GetChirpDescendants :many
+WITH RECURSIVE descendants AS (
+    SELECT c.id, c.created_at, c.updated_at, c.edit_count, c.edited, c.body, c.user_id, c.parent_id, c.root_id, 1 AS depth
+    FROM chirps c
+    WHERE c.parent_id = $1::uuid
+    AND c.deleted_at IS NULL
+    UNION ALL
+    SELECT c.id, c.created_at, c.updated_at, c.edit_count, c.edited, c.body, c.user_id, c.parent_id, c.root_id, d.depth + 1
+    FROM chirps c
+    INNER JOIN descendants d ON c.parent_id = d.id
+    WHERE c.deleted_at IS NULL
+)
+SELECT 
+    d.id,
+    d.created_at,
+    d.updated_at,
+    d.edit_count,
+    d.edited,
+    d.body,
+    d.user_id,
+    d.parent_id,
+    d.root_id,
+    d.depth,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = d.id AND r.deleted_at IS NULL) as reply_count
+FROM descendants d
+WHERE can_view_chirps(d.user_id, $2)
+AND (
+    $3::uuid IS NULL OR 
+    (d.created_at, d.id) > (
+        SELECT created_at, id FROM chirps WHERE id = $3
+    )
+)
+ORDER BY d.created_at ASC, d.id ASC
+LIMIT $4
 `
 
-func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, getChirp, id)
-	var i Chirp
+type GetChirpDescendantsParams struct {
+	ChirpID   uuid.UUID     `json:"chirp_id"`
+	ViewerID  uuid.NullUUID `json:"viewer_id"`
+	Cursor    uuid.NullUUID `json:"cursor"`
+	PageLimit int32         `json:"page_limit"`
+}
+
+type GetChirpDescendantsRow struct {
+	ID         uuid.UUID     `json:"id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	EditCount  int32         `json:"edit_count"`
+	Edited     bool          `json:"edited"`
+	Body       string        `json:"body"`
+	UserID     uuid.UUID     `json:"user_id"`
+	ParentID   uuid.NullUUID `json:"parent_id"`
+	RootID     uuid.NullUUID `json:"root_id"`
+	Depth      int32         `json:"depth"`
+	ReplyCount int64         `json:"reply_count"`
+}
+
+func (q *Queries) GetChirpDescendants(ctx context.Context, arg GetChirpDescendantsParams) ([]GetChirpDescendantsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpDescendants,
+		arg.ChirpID,
+		arg.ViewerID,
+		arg.Cursor,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChirpDescendantsRow
+	for rows.Next() {
+		var i GetChirpDescendantsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EditCount,
+			&i.Edited,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.RootID,
+			&i.Depth,
+			&i.ReplyCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getChirpWithStats = `-- name: GetChirpWithStats :one
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.root_id,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = $1
+    ) as liked_by_viewer
+FROM chirps c
+WHERE c.id = $2
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, $1)
+`
+
+type GetChirpWithStatsParams struct {
+	ViewerID uuid.NullUUID `json:"viewer_id"`
+	ID       uuid.UUID     `json:"id"`
+}
+
+type GetChirpWithStatsRow struct {
+	ID            uuid.UUID     `json:"id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	EditCount     int32         `json:"edit_count"`
+	Edited        bool          `json:"edited"`
+	Body          string        `json:"body"`
+	UserID        uuid.UUID     `json:"user_id"`
+	ParentID      uuid.NullUUID `json:"parent_id"`
+	RootID        uuid.NullUUID `json:"root_id"`
+	ReplyCount    int64         `json:"reply_count"`
+	LikeCount     int64         `json:"like_count"`
+	LikedByViewer bool          `json:"liked_by_viewer"`
+}
+
+func (q *Queries) GetChirpWithStats(ctx context.Context, arg GetChirpWithStatsParams) (GetChirpWithStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getChirpWithStats, arg.ViewerID, arg.ID)
+	var i GetChirpWithStatsRow
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EditCount,
+		&i.Edited,
 		&i.Body,
 		&i.UserID,
+		&i.ParentID,
+		&i.RootID,
+		&i.ReplyCount,
+		&i.LikeCount,
+		&i.LikedByViewer,
 	)
 	return i, err
 }
 
-const getChirpsByUser = `-- name: GetChirpsByUser :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps WHERE user_id = $1 ORDER BY created_at DESC
+const getDeletedChirps = `-- name: GetDeletedChirps :many
+SELECT * FROM chirps
+WHERE user_id = $1
+AND deleted_at IS NOT NULL
+AND (
+    $2::timestamp IS NULL OR
+    (deleted_at, id) < ($2::timestamp, $3::uuid)
+)
+ORDER BY deleted_at DESC, id DESC
+LIMIT $4
 `
 
-func (q *Queries) GetChirpsByUser(ctx context.Context, userID uuid.UUID) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getChirpsByUser, userID)
+type GetDeletedChirpsParams struct {
+	UserID    uuid.UUID     `json:"user_id"`
+	CursorAt  sql.NullTime  `json:"cursor_at"`
+	CursorID  uuid.NullUUID `json:"cursor_id"`
+	PageLimit int32         `json:"page_limit"`
+}
+
+func (q *Queries) GetDeletedChirps(ctx context.Context, arg GetDeletedChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getDeletedChirps,
+		arg.UserID,
+		arg.CursorAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +425,14 @@ func (q *Queries) GetChirpsByUser(ctx context.Context, userID uuid.UUID) ([]Chir
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.ParentID,
+			&i.RootID,
+			&i.Kind,
+			&i.OriginalID,
+			&i.SearchVector,
+			&i.EditCount,
+			&i.Edited,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -133,21 +447,315 @@ func (q *Queries) GetChirpsByUser(ctx context.Context, userID uuid.UUID) ([]Chir
 	return items, nil
 }
 
-const updateChirpBody = `-- name: UpdateChirpBody :one
+const listChirpsAsc = `-- name: ListChirpsAsc :many
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    u.handle as author_handle,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = $1
+    ) as liked_by_viewer
+FROM chirps c
+JOIN users u ON u.id = c.user_id
+WHERE ($2::uuid IS NULL OR c.user_id = $2)
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, $1)
+AND ($3::timestamp IS NULL OR c.created_at >= $3)
+AND ($4::timestamp IS NULL OR c.created_at < $4)
+AND (
+    $5::timestamp IS NULL OR
+    (c.created_at, c.id) > ($5::timestamp, $6::uuid)
+)
+ORDER BY c.created_at ASC, c.id ASC
+LIMIT $7
+`
+
+type ListChirpsAscParams struct {
+	ViewerID  uuid.NullUUID `json:"viewer_id"`
+	AuthorID  uuid.NullUUID `json:"author_id"`
+	Since     sql.NullTime  `json:"since"`
+	Until     sql.NullTime  `json:"until"`
+	CursorAt  sql.NullTime  `json:"cursor_at"`
+	CursorID  uuid.NullUUID `json:"cursor_id"`
+	PageLimit int32         `json:"page_limit"`
+}
+
+type ListChirpsAscRow struct {
+	ID            uuid.UUID     `json:"id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	EditCount     int32         `json:"edit_count"`
+	Edited        bool          `json:"edited"`
+	Body          string        `json:"body"`
+	UserID        uuid.UUID     `json:"user_id"`
+	ParentID      uuid.NullUUID `json:"parent_id"`
+	Kind          string        `json:"kind"`
+	OriginalID    uuid.NullUUID `json:"original_id"`
+	AuthorHandle  string        `json:"author_handle"`
+	ReplyCount    int64         `json:"reply_count"`
+	LikeCount     int64         `json:"like_count"`
+	LikedByViewer bool          `json:"liked_by_viewer"`
+}
+
+// Lists chirps oldest first, after the cursor when one is given.
+func (q *Queries) ListChirpsAsc(ctx context.Context, arg ListChirpsAscParams) ([]ListChirpsAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsAsc,
+		arg.ViewerID,
+		arg.AuthorID,
+		arg.Since,
+		arg.Until,
+		arg.CursorAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListChirpsAscRow
+	for rows.Next() {
+		var i ListChirpsAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EditCount,
+			&i.Edited,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.Kind,
+			&i.OriginalID,
+			&i.AuthorHandle,
+			&i.ReplyCount,
+			&i.LikeCount,
+			&i.LikedByViewer,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsDesc = `-- name: ListChirpsDesc :many
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    u.handle as author_handle,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = $1
+    ) as liked_by_viewer
+FROM chirps c
+JOIN users u ON u.id = c.user_id
+WHERE ($2::uuid IS NULL OR c.user_id = $2)
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, $1)
+AND ($3::timestamp IS NULL OR c.created_at >= $3)
+AND ($4::timestamp IS NULL OR c.created_at < $4)
+AND (
+    $5::timestamp IS NULL OR
+    (c.created_at, c.id) < ($5::timestamp, $6::uuid)
+)
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT $7
+`
+
+type ListChirpsDescParams struct {
+	ViewerID  uuid.NullUUID `json:"viewer_id"`
+	AuthorID  uuid.NullUUID `json:"author_id"`
+	Since     sql.NullTime  `json:"since"`
+	Until     sql.NullTime  `json:"until"`
+	CursorAt  sql.NullTime  `json:"cursor_at"`
+	CursorID  uuid.NullUUID `json:"cursor_id"`
+	PageLimit int32         `json:"page_limit"`
+}
+
+type ListChirpsDescRow struct {
+	ID            uuid.UUID     `json:"id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	EditCount     int32         `json:"edit_count"`
+	Edited        bool          `json:"edited"`
+	Body          string        `json:"body"`
+	UserID        uuid.UUID     `json:"user_id"`
+	ParentID      uuid.NullUUID `json:"parent_id"`
+	Kind          string        `json:"kind"`
+	OriginalID    uuid.NullUUID `json:"original_id"`
+	AuthorHandle  string        `json:"author_handle"`
+	ReplyCount    int64         `json:"reply_count"`
+	LikeCount     int64         `json:"like_count"`
+	LikedByViewer bool          `json:"liked_by_viewer"`
+}
+
+// Lists chirps newest first, before the cursor when one is given.
+func (q *Queries) ListChirpsDesc(ctx context.Context, arg ListChirpsDescParams) ([]ListChirpsDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsDesc,
+		arg.ViewerID,
+		arg.AuthorID,
+		arg.Since,
+		arg.Until,
+		arg.CursorAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListChirpsDescRow
+	for rows.Next() {
+		var i ListChirpsDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EditCount,
+			&i.Edited,
+			&i.Body,
+			&i.UserID,
+			&i.ParentID,
+			&i.Kind,
+			&i.OriginalID,
+			&i.AuthorHandle,
+			&i.ReplyCount,
+			&i.LikeCount,
+			&i.LikedByViewer,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedChirps = `-- name: PurgeDeletedChirps :execrows
+DELETE FROM chirps
+WHERE deleted_at < NOW() - $1::int * INTERVAL '1 second'
+`
+
+func (q *Queries) PurgeDeletedChirps(ctx context.Context, retentionSeconds int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedChirps, retentionSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreChirp = `-- name: RestoreChirp :one
+UPDATE chirps
+SET deleted_at = NULL
+WHERE id = $1
+AND user_id = $2
+AND deleted_at IS NOT NULL
+RETURNING id, created_at, updated_at, body, user_id, parent_id, root_id, kind, original_id, search_vector, edit_count, edited, deleted_at
+`
+
+type RestoreChirpParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RestoreChirp(ctx context.Context, arg RestoreChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, restoreChirp, arg.ID, arg.UserID)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.ParentID,
+		&i.RootID,
+		&i.Kind,
+		&i.OriginalID,
+		&i.SearchVector,
+		&i.EditCount,
+		&i.Edited,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const softDeleteChirp = `-- name: SoftDeleteChirp :one
 UPDATE chirps
-SET body = $1,
+SET deleted_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING deleted_at
+`
+
+func (q *Queries) SoftDeleteChirp(ctx context.Context, id uuid.UUID) (sql.NullTime, error) {
+	row := q.db.QueryRowContext(ctx, softDeleteChirp, id)
+	var deleted_at sql.NullTime
+	err := row.Scan(&deleted_at)
+	return deleted_at, err
+}
+
+const updateChirpBody = `-- name: UpdateChirpBody :one
+WITH editable AS (
+    SELECT id, body, updated_at FROM chirps
+    WHERE id = $1
+    AND deleted_at IS NULL
+    AND created_at > NOW() - $2::int * INTERVAL '1 second'
+    FOR UPDATE
+),
+revision AS (
+    INSERT INTO chirp_revisions (chirp_id, body, created_at)
+    SELECT id, body, updated_at FROM editable
+)
+UPDATE chirps c
+SET body = $3,
+    edit_count = c.edit_count + 1,
     updated_at = NOW()
-WHERE id = $2
-RETURNING id, created_at, updated_at, body, user_id
+FROM editable e
+WHERE c.id = e.id
+RETURNING c.*
 `
 
 type UpdateChirpBodyParams struct {
-	Body string    `json:"body"`
-	ID   uuid.UUID `json:"id"`
+	ID                uuid.UUID `json:"id"`
+	EditWindowSeconds int32     `json:"edit_window_seconds"`
+	Body              string    `json:"body"`
 }
 
+// Saves the current body as a revision and replaces it. Nothing is updated
+// once the chirp is older than the edit window or has been deleted.
 func (q *Queries) UpdateChirpBody(ctx context.Context, arg UpdateChirpBodyParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, updateChirpBody, arg.Body, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateChirpBody, arg.ID, arg.EditWindowSeconds, arg.Body)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -155,6 +763,14 @@ func (q *Queries) UpdateChirpBody(ctx context.Context, arg UpdateChirpBodyParams
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.ParentID,
+		&i.RootID,
+		&i.Kind,
+		&i.OriginalID,
+		&i.SearchVector,
+		&i.EditCount,
+		&i.Edited,
+		&i.DeletedAt,
 	)
 	return i, err
 }
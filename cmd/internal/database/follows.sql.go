@@ -10,12 +10,35 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-const followUser = `-- name: FollowUser :exec
-INSERT INTO follows (follower_id, followee_id)
-VALUES ($1, $2)
-ON CONFLICT DO NOTHING
+const canViewChirps = `-- name: CanViewChirps :one
+SELECT COALESCE(can_view_chirps($1, $2), FALSE)::boolean AS can_view
+`
+
+type CanViewChirpsParams struct {
+	AuthorID uuid.UUID     `json:"author_id"`
+	ViewerID uuid.NullUUID `json:"viewer_id"`
+}
+
+func (q *Queries) CanViewChirps(ctx context.Context, arg CanViewChirpsParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, canViewChirps, arg.AuthorID, arg.ViewerID)
+	var can_view bool
+	err := row.Scan(&can_view)
+	return can_view, err
+}
+
+const followUser = `-- name: FollowUser :execrows
+WITH inserted AS (
+    INSERT INTO follows (follower_id, followee_id)
+    VALUES ($1, $2)
+    ON CONFLICT DO NOTHING
+    RETURNING followee_id
+)
+UPDATE users
+SET follower_count = follower_count + 1
+WHERE id IN (SELECT followee_id FROM inserted)
 `
 
 type FollowUserParams struct {
@@ -23,42 +46,117 @@ type FollowUserParams struct {
 	FolloweeID uuid.UUID `json:"followee_id"`
 }
 
-func (q *Queries) FollowUser(ctx context.Context, arg FollowUserParams) error {
-	_, err := q.db.ExecContext(ctx, followUser, arg.FollowerID, arg.FolloweeID)
-	return err
+func (q *Queries) FollowUser(ctx context.Context, arg FollowUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, followUser, arg.FollowerID, arg.FolloweeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getFeed = `-- name: GetFeed :many
-SELECT c.id, c.created_at, c.updated_at, c.body, c.user_id, u.email as author_email
-FROM chirps c
+WITH feed_items AS (
+    SELECT 
+        CASE WHEN c.kind = 'rechirp' THEN c.original_id ELSE c.id END as chirp_id,
+        CASE WHEN c.kind = 'rechirp' THEN c.user_id END as rechirped_by,
+        c.created_at as activity_at,
+        c.id as activity_id
+    FROM chirps c
+    WHERE EXISTS (
+        SELECT 1
+        FROM follows f
+        WHERE f.follower_id = $1
+          AND f.followee_id = c.user_id
+    )
+    AND (c.kind <> 'rechirp' OR c.original_id IS NOT NULL)
+    AND c.deleted_at IS NULL
+),
+latest_items AS (
+    SELECT DISTINCT ON (chirp_id) chirp_id, rechirped_by, activity_at, activity_id
+    FROM feed_items
+    ORDER BY chirp_id, activity_at DESC, activity_id DESC
+)
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    u.handle as author_handle,
+    li.rechirped_by,
+    li.activity_at,
+    li.activity_id,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM chirps rc WHERE rc.original_id = c.id AND rc.kind = 'rechirp' AND rc.deleted_at IS NULL) as rechirp_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = $1
+    ) as liked_by_viewer
+FROM latest_items li
+INNER JOIN chirps c ON c.id = li.chirp_id
 INNER JOIN users u ON c.user_id = u.id
-WHERE EXISTS (
-    SELECT 1
-    FROM follows f
-    WHERE f.follower_id = $1
-      AND f.followee_id = c.user_id
+WHERE c.deleted_at IS NULL
+AND (
+    $2::uuid IS NULL OR 
+    (li.activity_at, li.activity_id) < (
+        SELECT created_at, id FROM chirps WHERE id = $2
+    )
+)
+AND (
+    $3::uuid IS NULL OR 
+    (li.activity_at, li.activity_id) > (
+        SELECT created_at, id FROM chirps WHERE id = $3
+    )
 )
-ORDER BY c.created_at DESC
-LIMIT $2 OFFSET $3
+ORDER BY
+    CASE WHEN $3::uuid IS NULL THEN li.activity_at END DESC,
+    CASE WHEN $3::uuid IS NULL THEN li.activity_id END DESC,
+    li.activity_at ASC,
+    li.activity_id ASC
+LIMIT $4
 `
 
 type GetFeedParams struct {
-	FollowerID uuid.UUID `json:"follower_id"`
-	Limit      int32     `json:"limit"`
-	Offset     int32     `json:"offset"`
+	FollowerID uuid.UUID     `json:"follower_id"`
+	Cursor     uuid.NullUUID `json:"cursor"`
+	NewerThan  uuid.NullUUID `json:"newer_than"`
+	PageLimit  int32         `json:"page_limit"`
 }
 
 type GetFeedRow struct {
-	ID          uuid.UUID `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Body        string    `json:"body"`
-	UserID      uuid.UUID `json:"user_id"`
-	AuthorEmail string    `json:"author_email"`
+	ID            uuid.UUID     `json:"id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	EditCount     int32         `json:"edit_count"`
+	Edited        bool          `json:"edited"`
+	Body          string        `json:"body"`
+	UserID        uuid.UUID     `json:"user_id"`
+	ParentID      uuid.NullUUID `json:"parent_id"`
+	Kind          string        `json:"kind"`
+	OriginalID    uuid.NullUUID `json:"original_id"`
+	AuthorHandle  string        `json:"author_handle"`
+	RechirpedBy   uuid.NullUUID `json:"rechirped_by"`
+	ActivityAt    time.Time     `json:"activity_at"`
+	ActivityID    uuid.UUID     `json:"activity_id"`
+	ReplyCount    int64         `json:"reply_count"`
+	RechirpCount  int64         `json:"rechirp_count"`
+	LikeCount     int64         `json:"like_count"`
+	LikedByViewer bool          `json:"liked_by_viewer"`
 }
 
 func (q *Queries) GetFeed(ctx context.Context, arg GetFeedParams) ([]GetFeedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getFeed, arg.FollowerID, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, getFeed,
+		arg.FollowerID,
+		arg.Cursor,
+		arg.NewerThan,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -70,9 +168,21 @@ func (q *Queries) GetFeed(ctx context.Context, arg GetFeedParams) ([]GetFeedRow,
 			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EditCount,
+			&i.Edited,
 			&i.Body,
 			&i.UserID,
-			&i.AuthorEmail,
+			&i.ParentID,
+			&i.Kind,
+			&i.OriginalID,
+			&i.AuthorHandle,
+			&i.RechirpedBy,
+			&i.ActivityAt,
+			&i.ActivityID,
+			&i.ReplyCount,
+			&i.RechirpCount,
+			&i.LikeCount,
+			&i.LikedByViewer,
 		); err != nil {
 			return nil, err
 		}
@@ -87,10 +197,50 @@ func (q *Queries) GetFeed(ctx context.Context, arg GetFeedParams) ([]GetFeedRow,
 	return items, nil
 }
 
+const getFollowerIDs = `-- name: GetFollowerIDs :many
+SELECT follower_id
+FROM follows f
+WHERE f.followee_id = $1
+AND NOT EXISTS (
+    SELECT 1 FROM unnest($2::uuid[]) AS s(id)
+    WHERE is_silenced(f.follower_id, s.id)
+)
+`
+
+type GetFollowerIDsParams struct {
+	FolloweeID uuid.UUID   `json:"followee_id"`
+	SubjectIds []uuid.UUID `json:"subject_ids"`
+}
+
+// Followers who have silenced the account, or any other account in
+// subject_ids, are left out; they do not want those chirps pushed to them.
+func (q *Queries) GetFollowerIDs(ctx context.Context, arg GetFollowerIDsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowerIDs, arg.FolloweeID, pq.Array(arg.SubjectIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var follower_id uuid.UUID
+		if err := rows.Scan(&follower_id); err != nil {
+			return nil, err
+		}
+		items = append(items, follower_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFollowers = `-- name: GetFollowers :many
 SELECT 
     u.id, 
-    u.email, 
+    u.handle, 
     u.is_chirpy_red, 
     u.created_at, 
     f.created_at as followed_at,
@@ -98,12 +248,14 @@ SELECT
 FROM follows f
 INNER JOIN users u ON f.follower_id = u.id
 WHERE f.followee_id = $1
+AND u.deleted_at IS NULL
+AND NOT is_blocked(f.followee_id, f.follower_id)
 ORDER BY f.created_at DESC
 `
 
 type GetFollowersRow struct {
 	ID             uuid.UUID `json:"id"`
-	Email          string    `json:"email"`
+	Handle         string    `json:"handle"`
 	IsChirpyRed    bool      `json:"is_chirpy_red"`
 	CreatedAt      time.Time `json:"created_at"`
 	FollowedAt     time.Time `json:"followed_at"`
@@ -121,7 +273,7 @@ func (q *Queries) GetFollowers(ctx context.Context, followeeID uuid.UUID) ([]Get
 		var i GetFollowersRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
+			&i.Handle,
 			&i.IsChirpyRed,
 			&i.CreatedAt,
 			&i.FollowedAt,
@@ -143,7 +295,7 @@ func (q *Queries) GetFollowers(ctx context.Context, followeeID uuid.UUID) ([]Get
 const getFollowing = `-- name: GetFollowing :many
 SELECT 
     u.id, 
-    u.email, 
+    u.handle, 
     u.is_chirpy_red, 
     u.created_at, 
     f.created_at as followed_at,
@@ -151,12 +303,14 @@ SELECT
 FROM follows f
 INNER JOIN users u ON f.followee_id = u.id
 WHERE f.follower_id = $1
+AND u.deleted_at IS NULL
+AND NOT is_blocked(f.follower_id, f.followee_id)
 ORDER BY f.created_at DESC
 `
 
 type GetFollowingRow struct {
 	ID             uuid.UUID `json:"id"`
-	Email          string    `json:"email"`
+	Handle         string    `json:"handle"`
 	IsChirpyRed    bool      `json:"is_chirpy_red"`
 	CreatedAt      time.Time `json:"created_at"`
 	FollowedAt     time.Time `json:"followed_at"`
@@ -174,7 +328,7 @@ func (q *Queries) GetFollowing(ctx context.Context, followerID uuid.UUID) ([]Get
 		var i GetFollowingRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
+			&i.Handle,
 			&i.IsChirpyRed,
 			&i.CreatedAt,
 			&i.FollowedAt,
@@ -213,8 +367,14 @@ func (q *Queries) IsFollowing(ctx context.Context, arg IsFollowingParams) (bool,
 }
 
 const unfollowUser = `-- name: UnfollowUser :exec
-DELETE FROM follows
-WHERE follower_id = $1 AND followee_id = $2
+WITH deleted AS (
+    DELETE FROM follows
+    WHERE follower_id = $1 AND followee_id = $2
+    RETURNING followee_id
+)
+UPDATE users
+SET follower_count = follower_count - 1
+WHERE id IN (SELECT followee_id FROM deleted)
 `
 
 type UnfollowUserParams struct {
@@ -1,5 +1,5 @@
--- +goose Up
-ALTER TABLE users ADD COLUMN hashed_password TEXT NOT NULL DEFAULT 'unset';
-
--- +goose Down
+-- +goose Up
+ALTER TABLE users ADD COLUMN hashed_password TEXT NOT NULL DEFAULT 'unset';
+
+-- +goose Down
 ALTER TABLE users DROP COLUMN hashed_password;
\ No newline at end of file
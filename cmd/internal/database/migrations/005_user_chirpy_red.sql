@@ -1,5 +1,5 @@
--- +goose Up
-ALTER TABLE users ADD COLUMN is_chirpy_red BOOLEAN NOT NULL DEFAULT FALSE;
-
--- +goose Down
+-- +goose Up
+ALTER TABLE users ADD COLUMN is_chirpy_red BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
 ALTER TABLE users DROP COLUMN is_chirpy_red;
\ No newline at end of file
@@ -1,14 +1,14 @@
--- +goose Up
-CREATE TABLE follows (
-    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-    PRIMARY KEY (follower_id, followee_id),
-    CHECK (follower_id != followee_id)
-);
-
-CREATE INDEX idx_follows_follower ON follows(follower_id);
-CREATE INDEX idx_follows_followee ON follows(followee_id);
-
--- +goose Down
+-- +goose Up
+CREATE TABLE follows (
+    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id != followee_id)
+);
+
+CREATE INDEX idx_follows_follower ON follows(follower_id);
+CREATE INDEX idx_follows_followee ON follows(followee_id);
+
+-- +goose Down
 DROP TABLE follows;
\ No newline at end of file
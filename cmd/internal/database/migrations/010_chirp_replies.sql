@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE chirps ADD COLUMN parent_id UUID REFERENCES chirps(id) ON DELETE SET NULL;
+ALTER TABLE chirps ADD COLUMN root_id UUID REFERENCES chirps(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_chirps_parent_created_id ON chirps(parent_id, created_at, id);
+CREATE INDEX idx_chirps_root_id ON chirps(root_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_chirps_root_id;
+DROP INDEX IF EXISTS idx_chirps_parent_created_id;
+ALTER TABLE chirps DROP COLUMN root_id;
+ALTER TABLE chirps DROP COLUMN parent_id;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE likes (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, chirp_id)
+);
+
+CREATE INDEX idx_likes_chirp_created ON likes(chirp_id, created_at DESC);
+CREATE INDEX idx_likes_user_created ON likes(user_id, created_at DESC, chirp_id DESC);
+
+-- +goose Down
+DROP TABLE likes;
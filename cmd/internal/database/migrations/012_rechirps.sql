@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE chirps ADD COLUMN kind TEXT NOT NULL DEFAULT 'chirp'
+    CHECK (kind IN ('chirp', 'rechirp', 'quote'));
+ALTER TABLE chirps ADD COLUMN original_id UUID REFERENCES chirps(id) ON DELETE SET NULL;
+
+-- A user can rechirp a given chirp at most once
+CREATE UNIQUE INDEX idx_chirps_unique_rechirp ON chirps(user_id, original_id) WHERE kind = 'rechirp';
+CREATE INDEX idx_chirps_original_id ON chirps(original_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_chirps_original_id;
+DROP INDEX IF EXISTS idx_chirps_unique_rechirp;
+ALTER TABLE chirps DROP COLUMN original_id;
+ALTER TABLE chirps DROP COLUMN kind;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN handle TEXT;
+
+-- Existing accounts get a placeholder handle they can change later
+UPDATE users SET handle = 'user_' || substr(replace(id::text, '-', ''), 1, 12) WHERE handle IS NULL;
+
+ALTER TABLE users ALTER COLUMN handle SET NOT NULL;
+
+-- Handles are unique regardless of case
+CREATE UNIQUE INDEX idx_users_handle_lower ON users(LOWER(handle));
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_handle_lower;
+ALTER TABLE users DROP COLUMN handle;
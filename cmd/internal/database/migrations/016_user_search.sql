@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram indexes serve both prefix (LIKE 'abc%') and similarity (%) matches
+CREATE INDEX idx_users_handle_trgm ON users USING GIN (LOWER(handle) gin_trgm_ops);
+CREATE INDEX idx_users_display_name_trgm ON users USING GIN (LOWER(display_name) gin_trgm_ops);
+CREATE INDEX idx_users_email_local_trgm ON users USING GIN (LOWER(split_part(email, '@', 1)) gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_email_local_trgm;
+DROP INDEX IF EXISTS idx_users_display_name_trgm;
+DROP INDEX IF EXISTS idx_users_handle_trgm;
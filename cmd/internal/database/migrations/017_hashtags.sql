@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE hashtags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- Tags are stored lowercase without the leading '#'
+    tag TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE chirp_hashtags (
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    hashtag_id UUID NOT NULL REFERENCES hashtags(id) ON DELETE CASCADE,
+    -- Copy of the chirp's created_at so tag timelines and trending
+    -- never need to touch the chirps table to filter by time
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (chirp_id, hashtag_id)
+);
+
+CREATE INDEX idx_chirp_hashtags_timeline ON chirp_hashtags(hashtag_id, created_at DESC, chirp_id DESC);
+CREATE INDEX idx_chirp_hashtags_created_at ON chirp_hashtags(created_at DESC);
+
+-- Backfill tags for existing chirps
+INSERT INTO hashtags (tag)
+SELECT DISTINCT lower(m[1])
+FROM chirps c
+CROSS JOIN LATERAL regexp_matches(c.body, '(?:^|[^[:alnum:]_])#([[:alnum:]_]{1,50})(?![[:alnum:]_])', 'g') AS m
+WHERE m[1] ~ '[^0-9]'
+ON CONFLICT (tag) DO NOTHING;
+
+INSERT INTO chirp_hashtags (chirp_id, hashtag_id, created_at)
+SELECT DISTINCT c.id, h.id, c.created_at
+FROM chirps c
+CROSS JOIN LATERAL regexp_matches(c.body, '(?:^|[^[:alnum:]_])#([[:alnum:]_]{1,50})(?![[:alnum:]_])', 'g') AS m
+JOIN hashtags h ON h.tag = lower(m[1])
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE chirp_hashtags;
+DROP TABLE hashtags;
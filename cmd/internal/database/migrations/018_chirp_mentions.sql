@@ -0,0 +1,15 @@
+-- +goose Up
+-- Mentions resolve through users.handle, which is already unique
+-- case-insensitively (idx_users_handle_lower)
+CREATE TABLE chirp_mentions (
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Copy of the chirp's created_at for the mentions timeline
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (chirp_id, user_id)
+);
+
+CREATE INDEX idx_chirp_mentions_timeline ON chirp_mentions(user_id, created_at DESC, chirp_id DESC);
+
+-- +goose Down
+DROP TABLE chirp_mentions;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('follow', 'like', 'reply', 'rechirp', 'quote', 'mention')),
+    -- The chirp the event is about; NULL for follows
+    chirp_id UUID REFERENCES chirps(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    read_at TIMESTAMP
+);
+
+-- One notification per actor and event, so like/unlike/like does not spam
+CREATE UNIQUE INDEX idx_notifications_unique_event ON notifications(
+    user_id, actor_id, type, COALESCE(chirp_id, '00000000-0000-0000-0000-000000000000'::uuid)
+);
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, id DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+CREATE TABLE notification_mutes (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    PRIMARY KEY (user_id, type)
+);
+
+-- +goose Down
+DROP TABLE notification_mutes;
+DROP TABLE notifications;
@@ -0,0 +1,43 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN follower_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE users u
+SET follower_count = f.total
+FROM (
+    SELECT followee_id, COUNT(*) as total
+    FROM follows
+    GROUP BY followee_id
+) f
+WHERE f.followee_id = u.id;
+
+-- Materialized home feed, one row per chirp per reader. chirp_id is what the
+-- reader sees (the original for rechirps); activity_id is the chirps row that
+-- put it there and provides the (created_at, id) keyset position.
+CREATE TABLE home_timeline (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    activity_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    rechirped_by UUID REFERENCES users(id) ON DELETE CASCADE,
+    activity_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, chirp_id)
+);
+
+CREATE INDEX idx_home_timeline_user_activity ON home_timeline(user_id, activity_at DESC, activity_id DESC);
+CREATE INDEX idx_home_timeline_activity_id ON home_timeline(activity_id);
+
+-- Backfill from existing follows
+INSERT INTO home_timeline (user_id, chirp_id, activity_id, rechirped_by, activity_at)
+SELECT DISTINCT ON (f.follower_id, CASE WHEN c.kind = 'rechirp' THEN c.original_id ELSE c.id END)
+    f.follower_id,
+    CASE WHEN c.kind = 'rechirp' THEN c.original_id ELSE c.id END,
+    c.id,
+    CASE WHEN c.kind = 'rechirp' THEN c.user_id END,
+    c.created_at
+FROM follows f
+JOIN chirps c ON c.user_id = f.followee_id
+WHERE c.kind <> 'rechirp' OR c.original_id IS NOT NULL
+ORDER BY f.follower_id, CASE WHEN c.kind = 'rechirp' THEN c.original_id ELSE c.id END, c.created_at DESC, c.id DESC;
+
+-- +goose Down
+DROP TABLE home_timeline;
+ALTER TABLE users DROP COLUMN follower_count;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS idx_chirps_created_id ON chirps(created_at DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_chirps_created_id;
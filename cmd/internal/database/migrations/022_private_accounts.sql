@@ -0,0 +1,41 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE follow_requests (
+    requester_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (requester_id, target_id)
+);
+
+CREATE INDEX idx_follow_requests_target ON follow_requests(target_id, created_at DESC, requester_id DESC);
+
+-- Whether viewer may read chirps written by author. Every chirp read query
+-- filters on this so private accounts are only visible to their followers.
+-- +goose StatementBegin
+CREATE FUNCTION can_view_chirps(author UUID, viewer UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT NOT u.is_private
+        OR u.id = viewer
+        OR EXISTS (
+            SELECT 1 FROM follows f
+            WHERE f.follower_id = viewer AND f.followee_id = u.id
+        )
+    FROM users u
+    WHERE u.id = author
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('follow', 'follow_request', 'like', 'reply', 'rechirp', 'quote', 'mention'));
+
+-- +goose Down
+DELETE FROM notifications WHERE type = 'follow_request';
+ALTER TABLE notifications DROP CONSTRAINT notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('follow', 'like', 'reply', 'rechirp', 'quote', 'mention'));
+
+DROP FUNCTION can_view_chirps(UUID, UUID);
+DROP TABLE follow_requests;
+ALTER TABLE users DROP COLUMN is_private;
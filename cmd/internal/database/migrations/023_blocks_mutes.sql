@@ -0,0 +1,83 @@
+-- +goose Up
+CREATE TABLE user_blocks (
+    blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX idx_user_blocks_blocker ON user_blocks(blocker_id, created_at DESC, blocked_id DESC);
+CREATE INDEX idx_user_blocks_blocked ON user_blocks(blocked_id);
+
+CREATE TABLE user_mutes (
+    muter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id <> muted_id)
+);
+
+CREATE INDEX idx_user_mutes_muter ON user_mutes(muter_id, created_at DESC, muted_id DESC);
+
+-- Whether either user blocks the other.
+-- +goose StatementBegin
+CREATE FUNCTION is_blocked(a UUID, b UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT EXISTS (
+        SELECT 1 FROM user_blocks
+        WHERE (blocker_id = a AND blocked_id = b)
+           OR (blocker_id = b AND blocked_id = a)
+    )
+$$;
+-- +goose StatementEnd
+
+-- Whether viewer has asked not to hear from other: a mute by the viewer or a
+-- block in either direction. Applied to the home feed and notifications.
+-- +goose StatementBegin
+CREATE FUNCTION is_silenced(viewer UUID, other UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT is_blocked(viewer, other) OR EXISTS (
+        SELECT 1 FROM user_mutes
+        WHERE muter_id = viewer AND muted_id = other
+    )
+$$;
+-- +goose StatementEnd
+
+-- Blocks hide content in both directions, so every chirp read path that
+-- already checks can_view_chirps picks them up.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION can_view_chirps(author UUID, viewer UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT (
+        NOT u.is_private
+        OR u.id = viewer
+        OR EXISTS (
+            SELECT 1 FROM follows f
+            WHERE f.follower_id = viewer AND f.followee_id = u.id
+        )
+    ) AND NOT is_blocked(u.id, viewer)
+    FROM users u
+    WHERE u.id = author
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION can_view_chirps(author UUID, viewer UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT NOT u.is_private
+        OR u.id = viewer
+        OR EXISTS (
+            SELECT 1 FROM follows f
+            WHERE f.follower_id = viewer AND f.followee_id = u.id
+        )
+    FROM users u
+    WHERE u.id = author
+$$;
+-- +goose StatementEnd
+
+DROP FUNCTION is_silenced(UUID, UUID);
+DROP FUNCTION is_blocked(UUID, UUID);
+DROP TABLE user_mutes;
+DROP TABLE user_blocks;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE muted_words (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- A word, a phrase or a #hashtag, stored as entered
+    phrase TEXT NOT NULL CHECK (length(phrase) BETWEEN 1 AND 100),
+    -- Whole-word filters match complete tokens; otherwise any substring
+    whole_word BOOLEAN NOT NULL DEFAULT TRUE,
+    -- 'hide' drops matching chirps, 'flag' returns them marked as filtered
+    action TEXT NOT NULL DEFAULT 'hide' CHECK (action IN ('hide', 'flag')),
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_muted_words_user_phrase ON muted_words(user_id, LOWER(phrase));
+
+-- +goose Down
+DROP TABLE muted_words;
@@ -0,0 +1,29 @@
+-- +goose Up
+-- Words checked by the chirp content filter. Words are matched after
+-- Unicode folding, so plain lower-case spellings are enough.
+CREATE TABLE filter_words (
+    word TEXT PRIMARY KEY CHECK (length(word) BETWEEN 1 AND 100),
+    -- 'mask' replaces the word, 'reject' refuses the chirp, 'flag' queues
+    -- the chirp for review
+    action TEXT NOT NULL DEFAULT 'mask' CHECK (action IN ('mask', 'reject', 'flag')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO filter_words (word, action) VALUES
+    ('kerfuffle', 'mask'),
+    ('sharbert', 'mask'),
+    ('fornax', 'mask');
+
+-- Chirps that matched a 'flag' word and are waiting for review.
+CREATE TABLE chirp_flags (
+    chirp_id UUID PRIMARY KEY REFERENCES chirps(id) ON DELETE CASCADE,
+    words TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_chirp_flags_created ON chirp_flags(created_at DESC, chirp_id DESC);
+
+-- +goose Down
+DROP TABLE chirp_flags;
+DROP TABLE filter_words;
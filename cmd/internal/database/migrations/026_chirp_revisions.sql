@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE chirps ADD COLUMN edit_count INT NOT NULL DEFAULT 0;
+ALTER TABLE chirps
+    ADD COLUMN edited BOOLEAN
+    GENERATED ALWAYS AS (edit_count > 0) STORED;
+
+-- Bodies a chirp had before each edit. created_at is when that body was
+-- written, replaced_at when the edit replaced it.
+CREATE TABLE chirp_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    replaced_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_chirp_revisions_chirp ON chirp_revisions(chirp_id, replaced_at DESC, id DESC);
+
+-- +goose Down
+DROP TABLE chirp_revisions;
+ALTER TABLE chirps DROP COLUMN edited;
+ALTER TABLE chirps DROP COLUMN edit_count;
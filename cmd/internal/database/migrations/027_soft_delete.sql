@@ -0,0 +1,53 @@
+-- +goose Up
+-- Deleted chirps stay in the owner's trash until the purger removes them.
+ALTER TABLE chirps ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_chirps_trash ON chirps(user_id, deleted_at DESC, id DESC)
+    WHERE deleted_at IS NOT NULL;
+
+-- Accounts with deleted_at set are hidden and purged after a grace period
+-- unless their owner logs in again.
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_users_deleted_at ON users(deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+-- Chirps of accounts pending deletion are hidden from everyone.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION can_view_chirps(author UUID, viewer UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT u.deleted_at IS NULL AND (
+        NOT u.is_private
+        OR u.id = viewer
+        OR EXISTS (
+            SELECT 1 FROM follows f
+            WHERE f.follower_id = viewer AND f.followee_id = u.id
+        )
+    ) AND NOT is_blocked(u.id, viewer)
+    FROM users u
+    WHERE u.id = author
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION can_view_chirps(author UUID, viewer UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT (
+        NOT u.is_private
+        OR u.id = viewer
+        OR EXISTS (
+            SELECT 1 FROM follows f
+            WHERE f.follower_id = viewer AND f.followee_id = u.id
+        )
+    ) AND NOT is_blocked(u.id, viewer)
+    FROM users u
+    WHERE u.id = author
+$$;
+-- +goose StatementEnd
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
+
+DROP INDEX IF EXISTS idx_chirps_trash;
+ALTER TABLE chirps DROP COLUMN deleted_at;
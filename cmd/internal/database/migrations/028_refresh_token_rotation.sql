@@ -0,0 +1,23 @@
+-- +goose Up
+-- Only a SHA-256 hash of each refresh token is stored.
+ALTER TABLE refresh_tokens RENAME COLUMN token TO token_hash;
+UPDATE refresh_tokens SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');
+
+-- Every token issued by /api/refresh joins the family of the token it
+-- replaced, so reuse of a rotated token can revoke the whole chain.
+ALTER TABLE refresh_tokens ADD COLUMN family_id UUID;
+UPDATE refresh_tokens SET family_id = gen_random_uuid();
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+ALTER TABLE refresh_tokens
+ADD COLUMN parent_hash TEXT REFERENCES refresh_tokens(token_hash) ON DELETE SET NULL;
+
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family_id);
+
+-- +goose Down
+DROP INDEX idx_refresh_tokens_family;
+ALTER TABLE refresh_tokens DROP COLUMN parent_hash;
+ALTER TABLE refresh_tokens DROP COLUMN family_id;
+-- Hashes cannot be turned back into tokens, so everyone has to log in again.
+DELETE FROM refresh_tokens;
+ALTER TABLE refresh_tokens RENAME COLUMN token_hash TO token;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Admins can work the moderation queue. The flag is granted by hand, e.g.
+-- UPDATE users SET is_admin = true WHERE email = '...';
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN is_admin;
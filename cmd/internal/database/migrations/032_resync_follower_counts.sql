@@ -0,0 +1,10 @@
+-- +goose Up
+-- Account purges used to leave follower_count too high for everyone the
+-- purged accounts followed. Recount once now that purges keep it in sync.
+UPDATE users u
+SET follower_count = (
+    SELECT COUNT(*) FROM follows f WHERE f.followee_id = u.id
+);
+
+-- +goose Down
+-- Nothing to undo.
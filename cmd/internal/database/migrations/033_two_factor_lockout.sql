@@ -0,0 +1,11 @@
+-- +goose Up
+-- Failed second factor attempts across all of a user's login challenges.
+-- Reaching the limit locks 2FA logins until two_factor_locked_until.
+ALTER TABLE users
+ADD COLUMN two_factor_failures INT NOT NULL DEFAULT 0,
+ADD COLUMN two_factor_locked_until TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN two_factor_locked_until,
+DROP COLUMN two_factor_failures;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- TOTP secrets are now sealed with AES-256-GCM under TOTP_ENCRYPTION_KEY and
+-- recovery codes carry 160 bits, so their SHA-256 digests cannot be brute
+-- forced. Rows written before this migration are still readable but keep the
+-- old weaknesses: a plaintext secret, and 48-bit codes that a copy of the
+-- database can be searched for offline. They are replaced when the user
+-- disables and re-enables 2FA.
+COMMENT ON COLUMN users.totp_secret IS
+    'Sealed with TOTP_ENCRYPTION_KEY ("v1:" prefix). Unprefixed values predate encryption and are plaintext.';
+COMMENT ON COLUMN recovery_codes.code_hash IS
+    'Hex SHA-256 of a normalized 160-bit code. Codes created before migration 034 have only 48 bits.';
+
+-- +goose Down
+COMMENT ON COLUMN recovery_codes.code_hash IS NULL;
+COMMENT ON COLUMN users.totp_secret IS NULL;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Chirps that were not fanned out because their author was over the fan-out
+-- limit when they were posted. Home timelines merge these in at read time,
+-- so they stay visible after the author drops back below the limit.
+CREATE TABLE pulled_chirps (
+    chirp_id UUID PRIMARY KEY REFERENCES chirps(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_pulled_chirps_user_created_id ON pulled_chirps(user_id, created_at DESC, chirp_id DESC);
+
+-- Until now the read path pulled every chirp of an account at or above the
+-- limit (timeline.FanoutLimit), so start from exactly that set.
+INSERT INTO pulled_chirps (chirp_id, user_id, created_at)
+SELECT c.id, c.user_id, c.created_at
+FROM chirps c
+JOIN users a ON a.id = c.user_id
+WHERE a.follower_count >= 10000
+AND (c.kind <> 'rechirp' OR c.original_id IS NOT NULL);
+
+-- +goose Down
+DROP TABLE pulled_chirps;
@@ -12,11 +12,52 @@ import (
 )
 
 type Chirp struct {
-	ID        uuid.UUID `json:"id"`
+	ID           uuid.UUID     `json:"id"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	Body         string        `json:"body"`
+	UserID       uuid.UUID     `json:"user_id"`
+	ParentID     uuid.NullUUID `json:"parent_id"`
+	RootID       uuid.NullUUID `json:"root_id"`
+	Kind         string        `json:"kind"`
+	OriginalID   uuid.NullUUID `json:"original_id"`
+	SearchVector string        `json:"-"`
+	EditCount    int32         `json:"edit_count"`
+	Edited       bool          `json:"edited"`
+	DeletedAt    sql.NullTime  `json:"-"`
+}
+
+type ChirpFlag struct {
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	Words     []string  `json:"words"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Body      string    `json:"body"`
+}
+
+type ChirpHashtag struct {
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	HashtagID uuid.UUID `json:"hashtag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ChirpMention struct {
+	ChirpID   uuid.UUID `json:"chirp_id"`
 	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ChirpRevision struct {
+	ID         uuid.UUID `json:"id"`
+	ChirpID    uuid.UUID `json:"chirp_id"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+type FilterWord struct {
+	Word      string    `json:"word"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Follow struct {
@@ -25,20 +66,134 @@ type Follow struct {
 	CreatedAt  time.Time `json:"created_at"`
 }
 
-type RefreshToken struct {
-	Token     string       `json:"token"`
+type FollowRequest struct {
+	RequesterID uuid.UUID `json:"requester_id"`
+	TargetID    uuid.UUID `json:"target_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Hashtag struct {
+	ID        uuid.UUID `json:"id"`
+	Tag       string    `json:"tag"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type HomeTimeline struct {
+	UserID      uuid.UUID     `json:"user_id"`
+	ChirpID     uuid.UUID     `json:"chirp_id"`
+	ActivityID  uuid.UUID     `json:"activity_id"`
+	RechirpedBy uuid.NullUUID `json:"rechirped_by"`
+	ActivityAt  time.Time     `json:"activity_at"`
+}
+
+type Like struct {
+	UserID    uuid.UUID `json:"user_id"`
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LoginChallenge struct {
+	TokenHash  string    `json:"token_hash"`
+	UserID     uuid.UUID `json:"user_id"`
+	DeviceName string    `json:"device_name"`
+	Attempts   int32     `json:"attempts"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type MutedWord struct {
+	ID        uuid.UUID    `json:"id"`
+	UserID    uuid.UUID    `json:"user_id"`
+	Phrase    string       `json:"phrase"`
+	WholeWord bool         `json:"whole_word"`
+	Action    string       `json:"action"`
+	ExpiresAt sql.NullTime `json:"expires_at"`
 	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type Notification struct {
+	ID        int64         `json:"id"`
+	UserID    uuid.UUID     `json:"user_id"`
+	ActorID   uuid.UUID     `json:"actor_id"`
+	Type      string        `json:"type"`
+	ChirpID   uuid.NullUUID `json:"chirp_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	ReadAt    sql.NullTime  `json:"read_at"`
+}
+
+type NotificationMute struct {
+	UserID uuid.UUID `json:"user_id"`
+	Type   string    `json:"type"`
+}
+
+type PulledChirp struct {
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	ID        uuid.UUID    `json:"id"`
 	UserID    uuid.UUID    `json:"user_id"`
-	ExpiresAt time.Time    `json:"expires_at"`
-	RevokedAt sql.NullTime `json:"revoked_at"`
+	CodeHash  string       `json:"code_hash"`
+	CreatedAt time.Time    `json:"created_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+}
+
+type RefreshToken struct {
+	TokenHash  string         `json:"token_hash"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	UserID     uuid.UUID      `json:"user_id"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+	RevokedAt  sql.NullTime   `json:"revoked_at"`
+	FamilyID   uuid.UUID      `json:"family_id"`
+	ParentHash sql.NullString `json:"parent_hash"`
+}
+
+type Session struct {
+	ID         uuid.UUID      `json:"id"`
+	UserID     uuid.UUID      `json:"user_id"`
+	Name       sql.NullString `json:"name"`
+	UserAgent  string         `json:"user_agent"`
+	IpAddress  string         `json:"ip_address"`
+	CreatedAt  time.Time      `json:"created_at"`
+	LastUsedAt time.Time      `json:"last_used_at"`
 }
 
 type User struct {
-	ID             uuid.UUID `json:"id"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	Email          string    `json:"email"`
-	HashedPassword string    `json:"hashed_password"`
-	IsChirpyRed    bool      `json:"is_chirpy_red"`
+	ID                   uuid.UUID      `json:"id"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	Email                string         `json:"email"`
+	HashedPassword       string         `json:"hashed_password"`
+	IsChirpyRed          bool           `json:"is_chirpy_red"`
+	Handle               string         `json:"handle"`
+	DisplayName          string         `json:"display_name"`
+	Bio                  string         `json:"bio"`
+	Location             string         `json:"location"`
+	Website              string         `json:"website"`
+	AvatarKey            string         `json:"avatar_key"`
+	FollowerCount        int32          `json:"follower_count"`
+	IsPrivate            bool           `json:"is_private"`
+	DeletedAt            sql.NullTime   `json:"deleted_at"`
+	TokenVersion         int32          `json:"token_version"`
+	TotpSecret           sql.NullString `json:"totp_secret"`
+	TotpEnabledAt        sql.NullTime   `json:"totp_enabled_at"`
+	TotpLastStep         int64          `json:"totp_last_step"`
+	IsAdmin              bool           `json:"is_admin"`
+	TwoFactorFailures    int32          `json:"two_factor_failures"`
+	TwoFactorLockedUntil sql.NullTime   `json:"two_factor_locked_until"`
+}
+
+type UserBlock struct {
+	BlockerID uuid.UUID `json:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserMute struct {
+	MuterID   uuid.UUID `json:"muter_id"`
+	MutedID   uuid.UUID `json:"muted_id"`
+	CreatedAt time.Time `json:"created_at"`
 }
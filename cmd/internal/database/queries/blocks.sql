@@ -0,0 +1,51 @@
+-- name: BlockUser :exec
+-- Records the block and severs everything between the two accounts: follows
+-- in both directions (keeping follower counts in step) and pending follow
+-- requests.
+WITH blocked AS (
+    INSERT INTO user_blocks (blocker_id, blocked_id)
+    VALUES (sqlc.arg(blocker_id), sqlc.arg(blocked_id))
+    ON CONFLICT DO NOTHING
+),
+withdrawn AS (
+    DELETE FROM follow_requests
+    WHERE (requester_id = sqlc.arg(blocker_id) AND target_id = sqlc.arg(blocked_id))
+       OR (requester_id = sqlc.arg(blocked_id) AND target_id = sqlc.arg(blocker_id))
+),
+severed AS (
+    DELETE FROM follows
+    WHERE (follower_id = sqlc.arg(blocker_id) AND followee_id = sqlc.arg(blocked_id))
+       OR (follower_id = sqlc.arg(blocked_id) AND followee_id = sqlc.arg(blocker_id))
+    RETURNING followee_id
+)
+UPDATE users
+SET follower_count = follower_count - 1
+WHERE id IN (SELECT followee_id FROM severed);
+
+-- name: UnblockUser :execrows
+DELETE FROM user_blocks
+WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: IsBlocked :one
+-- True when either user blocks the other.
+SELECT is_blocked(sqlc.arg(user_a)::uuid, sqlc.arg(user_b)::uuid)::boolean AS blocked;
+
+-- name: GetBlockedUsers :many
+SELECT
+    u.id,
+    u.handle,
+    u.display_name,
+    u.avatar_key,
+    b.created_at as blocked_at
+FROM user_blocks b
+INNER JOIN users u ON b.blocked_id = u.id
+WHERE b.blocker_id = sqlc.arg(blocker_id)
+AND (
+    sqlc.narg(cursor)::uuid IS NULL OR
+    (b.created_at, b.blocked_id) < (
+        SELECT created_at, blocked_id FROM user_blocks
+        WHERE blocker_id = sqlc.arg(blocker_id) AND blocked_id = sqlc.narg(cursor)
+    )
+)
+ORDER BY b.created_at DESC, b.blocked_id DESC
+LIMIT sqlc.arg(page_limit);
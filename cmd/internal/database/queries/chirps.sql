@@ -1,29 +1,230 @@
--- name: CreateChirp :one
-INSERT INTO chirps (id, created_at, updated_at, body, user_id)
-VALUES (
-    gen_random_uuid(),
-    NOW(),
-    NOW(),
-    $1,
-    $2
-)
-RETURNING *;
-
--- name: GetChirp :one
-SELECT * FROM chirps WHERE id = $1;
-
--- name: GetAllChirps :many
-SELECT * FROM chirps ORDER BY created_at ASC;
-
--- name: GetChirpsByUser :many
-SELECT * FROM chirps WHERE user_id = $1 ORDER BY created_at DESC;
-
--- name: UpdateChirpBody :one
-UPDATE chirps
-SET body = $1,
-    updated_at = NOW()
-WHERE id = $2
-RETURNING * ;
-
--- name: DeleteChirp :exec
-DELETE FROM chirps WHERE id = $1;
\ No newline at end of file
+-- name: CreateChirp :one
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, parent_id, root_id, kind, original_id)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+RETURNING *;
+
+-- name: CreateRechirp :one
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, kind, original_id)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    '',
+    $1,
+    'rechirp',
+    $2
+)
+ON CONFLICT (user_id, original_id) WHERE kind = 'rechirp' DO NOTHING
+RETURNING *;
+
+-- name: DeleteRechirp :execrows
+DELETE FROM chirps
+WHERE user_id = $1 AND original_id = $2 AND kind = 'rechirp';
+
+-- name: GetChirp :one
+SELECT * FROM chirps WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetChirpWithStats :one
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.root_id,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = sqlc.narg(viewer_id)
+    ) as liked_by_viewer
+FROM chirps c
+WHERE c.id = sqlc.arg(id)
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, sqlc.narg(viewer_id));
+
+-- name: ListChirpsAsc :many
+-- Lists chirps oldest first, after the cursor when one is given.
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    u.handle as author_handle,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = sqlc.narg(viewer_id)
+    ) as liked_by_viewer
+FROM chirps c
+JOIN users u ON u.id = c.user_id
+WHERE (sqlc.narg(author_id)::uuid IS NULL OR c.user_id = sqlc.narg(author_id))
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, sqlc.narg(viewer_id))
+AND (sqlc.narg(since)::timestamp IS NULL OR c.created_at >= sqlc.narg(since))
+AND (sqlc.narg(until)::timestamp IS NULL OR c.created_at < sqlc.narg(until))
+AND (
+    sqlc.narg(cursor_at)::timestamp IS NULL OR
+    (c.created_at, c.id) > (sqlc.narg(cursor_at)::timestamp, sqlc.narg(cursor_id)::uuid)
+)
+ORDER BY c.created_at ASC, c.id ASC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListChirpsDesc :many
+-- Lists chirps newest first, before the cursor when one is given.
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    u.handle as author_handle,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = sqlc.narg(viewer_id)
+    ) as liked_by_viewer
+FROM chirps c
+JOIN users u ON u.id = c.user_id
+WHERE (sqlc.narg(author_id)::uuid IS NULL OR c.user_id = sqlc.narg(author_id))
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, sqlc.narg(viewer_id))
+AND (sqlc.narg(since)::timestamp IS NULL OR c.created_at >= sqlc.narg(since))
+AND (sqlc.narg(until)::timestamp IS NULL OR c.created_at < sqlc.narg(until))
+AND (
+    sqlc.narg(cursor_at)::timestamp IS NULL OR
+    (c.created_at, c.id) < (sqlc.narg(cursor_at)::timestamp, sqlc.narg(cursor_id)::uuid)
+)
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateChirpBody :one
+-- Saves the current body as a revision and replaces it. Nothing is updated
+-- once the chirp is older than the edit window or has been deleted.
+WITH editable AS (
+    SELECT id, body, updated_at FROM chirps
+    WHERE id = sqlc.arg(id)
+    AND deleted_at IS NULL
+    AND created_at > NOW() - sqlc.arg(edit_window_seconds)::int * INTERVAL '1 second'
+    FOR UPDATE
+),
+revision AS (
+    INSERT INTO chirp_revisions (chirp_id, body, created_at)
+    SELECT id, body, updated_at FROM editable
+)
+UPDATE chirps c
+SET body = sqlc.arg(body),
+    edit_count = c.edit_count + 1,
+    updated_at = NOW()
+FROM editable e
+WHERE c.id = e.id
+RETURNING c.*;
+
+-- name: DeleteChirp :exec
+DELETE FROM chirps WHERE id = $1;
+
+-- name: SoftDeleteChirp :one
+UPDATE chirps
+SET deleted_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING deleted_at;
+
+-- name: RestoreChirp :one
+UPDATE chirps
+SET deleted_at = NULL
+WHERE id = sqlc.arg(id)
+AND user_id = sqlc.arg(user_id)
+AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: GetDeletedChirps :many
+SELECT * FROM chirps
+WHERE user_id = sqlc.arg(user_id)
+AND deleted_at IS NOT NULL
+AND (
+    sqlc.narg(cursor_at)::timestamp IS NULL OR
+    (deleted_at, id) < (sqlc.narg(cursor_at)::timestamp, sqlc.narg(cursor_id)::uuid)
+)
+ORDER BY deleted_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: PurgeDeletedChirps :execrows
+DELETE FROM chirps
+WHERE deleted_at < NOW() - sqlc.arg(retention_seconds)::int * INTERVAL '1 second';
+
+-- name: GetChirpAncestors :many
+WITH RECURSIVE ancestors AS (
+    SELECT p.id, p.created_at, p.updated_at, p.edit_count, p.edited, p.body, p.user_id, p.parent_id, p.root_id, p.deleted_at, 1 AS depth
+    FROM chirps p
+    WHERE p.id = (SELECT c.parent_id FROM chirps c WHERE c.id = sqlc.arg(chirp_id))
+    UNION ALL
+    SELECT p.id, p.created_at, p.updated_at, p.edit_count, p.edited, p.body, p.user_id, p.parent_id, p.root_id, p.deleted_at, a.depth + 1
+    FROM chirps p
+    INNER JOIN ancestors a ON p.id = a.parent_id
+)
+SELECT id, created_at, updated_at, edit_count, edited, body, user_id, parent_id, root_id
+FROM ancestors
+WHERE deleted_at IS NULL
+AND can_view_chirps(user_id, sqlc.narg(viewer_id))
+ORDER BY depth DESC;
+
+-- name: GetChirpDescendants :many
+WITH RECURSIVE descendants AS (
+    SELECT c.id, c.created_at, c.updated_at, c.edit_count, c.edited, c.body, c.user_id, c.parent_id, c.root_id, 1 AS depth
+    FROM chirps c
+    WHERE c.parent_id = sqlc.arg(chirp_id)::uuid
+    AND c.deleted_at IS NULL
+    UNION ALL
+    SELECT c.id, c.created_at, c.updated_at, c.edit_count, c.edited, c.body, c.user_id, c.parent_id, c.root_id, d.depth + 1
+    FROM chirps c
+    INNER JOIN descendants d ON c.parent_id = d.id
+    WHERE c.deleted_at IS NULL
+)
+SELECT 
+    d.id,
+    d.created_at,
+    d.updated_at,
+    d.edit_count,
+    d.edited,
+    d.body,
+    d.user_id,
+    d.parent_id,
+    d.root_id,
+    d.depth,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = d.id AND r.deleted_at IS NULL) as reply_count
+FROM descendants d
+WHERE can_view_chirps(d.user_id, sqlc.narg(viewer_id))
+AND (
+    sqlc.narg(cursor)::uuid IS NULL OR 
+    (d.created_at, d.id) > (
+        SELECT created_at, id FROM chirps WHERE id = sqlc.narg(cursor)
+    )
+)
+ORDER BY d.created_at ASC, d.id ASC
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,63 @@
+-- name: CreateFollowRequest :execrows
+INSERT INTO follow_requests (requester_id, target_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteFollowRequest :execrows
+DELETE FROM follow_requests
+WHERE requester_id = $1 AND target_id = $2;
+
+-- name: GetIncomingFollowRequests :many
+SELECT
+    u.id,
+    u.handle,
+    u.display_name,
+    u.avatar_key,
+    fr.created_at as requested_at
+FROM follow_requests fr
+INNER JOIN users u ON fr.requester_id = u.id
+WHERE fr.target_id = sqlc.arg(target_id)
+AND (
+    sqlc.narg(cursor_at)::timestamp IS NULL OR
+    (fr.created_at, fr.requester_id) < (sqlc.narg(cursor_at)::timestamp, sqlc.narg(cursor_id)::uuid)
+)
+ORDER BY fr.created_at DESC, fr.requester_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: AcceptFollowRequest :execrows
+-- Turns a pending request into a follow. Affects no rows when there was no
+-- request to accept.
+WITH request AS (
+    DELETE FROM follow_requests
+    WHERE requester_id = $1 AND target_id = $2
+    RETURNING requester_id, target_id
+),
+inserted AS (
+    INSERT INTO follows (follower_id, followee_id)
+    SELECT requester_id, target_id FROM request
+    ON CONFLICT DO NOTHING
+    RETURNING followee_id
+)
+UPDATE users
+SET follower_count = follower_count + (SELECT COUNT(*) FROM inserted)
+WHERE id IN (SELECT target_id FROM request);
+
+-- name: AcceptAllFollowRequests :many
+-- Used when an account goes public: every pending request becomes a follow.
+WITH requests AS (
+    DELETE FROM follow_requests
+    WHERE target_id = $1
+    RETURNING requester_id, target_id
+),
+inserted AS (
+    INSERT INTO follows (follower_id, followee_id)
+    SELECT requester_id, target_id FROM requests
+    ON CONFLICT DO NOTHING
+    RETURNING follower_id
+),
+counted AS (
+    UPDATE users
+    SET follower_count = follower_count + (SELECT COUNT(*) FROM inserted)
+    WHERE id = $1
+)
+SELECT follower_id FROM inserted;
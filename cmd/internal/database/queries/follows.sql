@@ -1,53 +1,137 @@
--- name: FollowUser :exec
-INSERT INTO follows (follower_id, followee_id)
-VALUES ($1, $2)
-ON CONFLICT DO NOTHING;
-
--- name: UnfollowUser :exec
-DELETE FROM follows
-WHERE follower_id = $1 AND followee_id = $2;
-
--- name: GetFollowers :many
-SELECT 
-    u.id, 
-    u.email, 
-    u.is_chirpy_red, 
-    u.created_at, 
-    f.created_at as followed_at,
-    COUNT(*) OVER() as total_followers
-FROM follows f
-INNER JOIN users u ON f.follower_id = u.id
-WHERE f.followee_id = $1
-ORDER BY f.created_at DESC;
-
--- name: GetFollowing :many
-SELECT 
-    u.id, 
-    u.email, 
-    u.is_chirpy_red, 
-    u.created_at, 
-    f.created_at as followed_at,
-    COUNT(*) OVER() as total_following
-FROM follows f
-INNER JOIN users u ON f.followee_id = u.id
-WHERE f.follower_id = $1
-ORDER BY f.created_at DESC;
-
--- name: IsFollowing :one
-SELECT EXISTS(
-    SELECT 1 FROM follows
-    WHERE follower_id = $1 AND followee_id = $2
-) as is_following;
-
--- name: GetFeed :many
-SELECT c.id, c.created_at, c.updated_at, c.body, c.user_id, u.email as author_email
-FROM chirps c
-INNER JOIN users u ON c.user_id = u.id
-WHERE EXISTS (
-    SELECT 1
-    FROM follows f
-    WHERE f.follower_id = $1
-      AND f.followee_id = c.user_id
-)
-ORDER BY c.created_at DESC
-LIMIT $2 OFFSET $3;
\ No newline at end of file
+-- name: FollowUser :execrows
+WITH inserted AS (
+    INSERT INTO follows (follower_id, followee_id)
+    VALUES ($1, $2)
+    ON CONFLICT DO NOTHING
+    RETURNING followee_id
+)
+UPDATE users
+SET follower_count = follower_count + 1
+WHERE id IN (SELECT followee_id FROM inserted);
+
+-- name: UnfollowUser :exec
+WITH deleted AS (
+    DELETE FROM follows
+    WHERE follower_id = $1 AND followee_id = $2
+    RETURNING followee_id
+)
+UPDATE users
+SET follower_count = follower_count - 1
+WHERE id IN (SELECT followee_id FROM deleted);
+
+-- name: GetFollowers :many
+SELECT 
+    u.id, 
+    u.handle, 
+    u.is_chirpy_red, 
+    u.created_at, 
+    f.created_at as followed_at,
+    COUNT(*) OVER() as total_followers
+FROM follows f
+INNER JOIN users u ON f.follower_id = u.id
+WHERE f.followee_id = $1
+AND u.deleted_at IS NULL
+AND NOT is_blocked(f.followee_id, f.follower_id)
+ORDER BY f.created_at DESC;
+
+-- name: GetFollowerIDs :many
+-- Followers who have silenced the account, or any other account in
+-- subject_ids, are left out; they do not want those chirps pushed to them.
+SELECT follower_id
+FROM follows f
+WHERE f.followee_id = sqlc.arg(followee_id)
+AND NOT EXISTS (
+    SELECT 1 FROM unnest(sqlc.arg(subject_ids)::uuid[]) AS s(id)
+    WHERE is_silenced(f.follower_id, s.id)
+);
+
+-- name: GetFollowing :many
+SELECT 
+    u.id, 
+    u.handle, 
+    u.is_chirpy_red, 
+    u.created_at, 
+    f.created_at as followed_at,
+    COUNT(*) OVER() as total_following
+FROM follows f
+INNER JOIN users u ON f.followee_id = u.id
+WHERE f.follower_id = $1
+AND u.deleted_at IS NULL
+AND NOT is_blocked(f.follower_id, f.followee_id)
+ORDER BY f.created_at DESC;
+
+-- name: IsFollowing :one
+SELECT EXISTS(
+    SELECT 1 FROM follows
+    WHERE follower_id = $1 AND followee_id = $2
+) as is_following;
+
+-- name: GetFeed :many
+WITH feed_items AS (
+    SELECT 
+        CASE WHEN c.kind = 'rechirp' THEN c.original_id ELSE c.id END as chirp_id,
+        CASE WHEN c.kind = 'rechirp' THEN c.user_id END as rechirped_by,
+        c.created_at as activity_at,
+        c.id as activity_id
+    FROM chirps c
+    WHERE EXISTS (
+        SELECT 1
+        FROM follows f
+        WHERE f.follower_id = sqlc.arg(follower_id)
+          AND f.followee_id = c.user_id
+    )
+    AND (c.kind <> 'rechirp' OR c.original_id IS NOT NULL)
+    AND c.deleted_at IS NULL
+),
+latest_items AS (
+    SELECT DISTINCT ON (chirp_id) chirp_id, rechirped_by, activity_at, activity_id
+    FROM feed_items
+    ORDER BY chirp_id, activity_at DESC, activity_id DESC
+)
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    u.handle as author_handle,
+    li.rechirped_by,
+    li.activity_at,
+    li.activity_id,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM chirps rc WHERE rc.original_id = c.id AND rc.kind = 'rechirp' AND rc.deleted_at IS NULL) as rechirp_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = sqlc.arg(follower_id)
+    ) as liked_by_viewer
+FROM latest_items li
+INNER JOIN chirps c ON c.id = li.chirp_id
+INNER JOIN users u ON c.user_id = u.id
+WHERE c.deleted_at IS NULL
+AND (
+    sqlc.narg(cursor)::uuid IS NULL OR 
+    (li.activity_at, li.activity_id) < (
+        SELECT created_at, id FROM chirps WHERE id = sqlc.narg(cursor)
+    )
+)
+AND (
+    sqlc.narg(newer_than)::uuid IS NULL OR 
+    (li.activity_at, li.activity_id) > (
+        SELECT created_at, id FROM chirps WHERE id = sqlc.narg(newer_than)
+    )
+)
+ORDER BY
+    CASE WHEN sqlc.narg(newer_than)::uuid IS NULL THEN li.activity_at END DESC,
+    CASE WHEN sqlc.narg(newer_than)::uuid IS NULL THEN li.activity_id END DESC,
+    li.activity_at ASC,
+    li.activity_id ASC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CanViewChirps :one
+SELECT COALESCE(can_view_chirps(sqlc.arg(author_id), sqlc.narg(viewer_id)), FALSE)::boolean AS can_view;
@@ -0,0 +1,73 @@
+-- name: SyncChirpHashtags :exec
+WITH tags AS (
+    INSERT INTO hashtags (id, tag, created_at)
+    SELECT gen_random_uuid(), t, NOW() FROM unnest(sqlc.arg(tags)::text[]) AS t
+    -- DO UPDATE (rather than DO NOTHING) so existing tags are returned too
+    ON CONFLICT (tag) DO UPDATE SET tag = EXCLUDED.tag
+    RETURNING id
+),
+removed AS (
+    DELETE FROM chirp_hashtags
+    WHERE chirp_id = sqlc.arg(chirp_id)
+    AND hashtag_id NOT IN (SELECT id FROM tags)
+)
+INSERT INTO chirp_hashtags (chirp_id, hashtag_id, created_at)
+SELECT c.id, tags.id, c.created_at
+FROM chirps c, tags
+WHERE c.id = sqlc.arg(chirp_id)
+ON CONFLICT DO NOTHING;
+
+-- name: GetHashtagChirps :many
+SELECT
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    u.handle AS author_handle,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = sqlc.narg(viewer_id)
+    ) as liked_by_viewer
+FROM hashtags h
+JOIN chirp_hashtags ch ON ch.hashtag_id = h.id
+JOIN chirps c ON c.id = ch.chirp_id
+JOIN users u ON u.id = c.user_id
+WHERE h.tag = sqlc.arg(tag)
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, sqlc.narg(viewer_id))
+AND (
+    sqlc.narg(cursor)::uuid IS NULL OR
+    (ch.created_at, ch.chirp_id) < (
+        SELECT created_at, chirp_id FROM chirp_hashtags
+        WHERE chirp_id = sqlc.narg(cursor) AND hashtag_id = h.id
+    )
+)
+ORDER BY ch.created_at DESC, ch.chirp_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetTrendingHashtags :many
+-- Only chirps anyone may read count, so private and deleted accounts do not
+-- surface their tags.
+SELECT
+    h.tag,
+    COUNT(*) AS chirp_count,
+    SUM(
+        power(0.5, EXTRACT(EPOCH FROM (NOW() - ch.created_at)) / 3600.0 / sqlc.arg(half_life_hours)::float8)
+    )::float8 AS score
+FROM chirp_hashtags ch
+JOIN hashtags h ON h.id = ch.hashtag_id
+JOIN chirps c ON c.id = ch.chirp_id
+WHERE ch.created_at >= NOW() - make_interval(hours => sqlc.arg(window_hours)::int)
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, NULL)
+GROUP BY h.tag
+ORDER BY score DESC, chirp_count DESC, h.tag ASC
+LIMIT sqlc.arg(page_limit);
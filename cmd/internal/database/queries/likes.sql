@@ -0,0 +1,50 @@
+-- name: LikeChirp :exec
+INSERT INTO likes (user_id, chirp_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: UnlikeChirp :exec
+DELETE FROM likes
+WHERE user_id = $1 AND chirp_id = $2;
+
+-- name: GetChirpLikes :many
+SELECT 
+    u.id, 
+    u.handle, 
+    u.is_chirpy_red, 
+    l.created_at as liked_at,
+    COUNT(*) OVER() as total_likes
+FROM likes l
+INNER JOIN users u ON l.user_id = u.id
+WHERE l.chirp_id = $1
+ORDER BY l.created_at DESC;
+
+-- name: GetUserLikedChirps :many
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    l.created_at as liked_at,
+    (SELECT COUNT(*) FROM likes lc WHERE lc.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = sqlc.narg(viewer_id)
+    ) as liked_by_viewer
+FROM likes l
+INNER JOIN chirps c ON l.chirp_id = c.id
+WHERE l.user_id = sqlc.arg(user_id)
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, sqlc.narg(viewer_id))
+AND (
+    sqlc.narg(cursor)::uuid IS NULL OR 
+    (l.created_at, l.chirp_id) < (
+        SELECT created_at, chirp_id FROM likes
+        WHERE user_id = sqlc.arg(user_id) AND chirp_id = sqlc.narg(cursor)
+    )
+)
+ORDER BY l.created_at DESC, l.chirp_id DESC
+LIMIT sqlc.arg(page_limit);
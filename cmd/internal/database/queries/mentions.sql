@@ -0,0 +1,58 @@
+-- name: SyncChirpMentions :many
+WITH mentioned AS (
+    SELECT u.id FROM users u
+    WHERE LOWER(u.handle) = ANY(sqlc.arg(handles)::text[])
+),
+removed AS (
+    DELETE FROM chirp_mentions
+    WHERE chirp_id = sqlc.arg(chirp_id)
+    AND user_id NOT IN (SELECT id FROM mentioned)
+)
+INSERT INTO chirp_mentions (chirp_id, user_id, created_at)
+SELECT c.id, mentioned.id, c.created_at
+FROM chirps c, mentioned
+WHERE c.id = sqlc.arg(chirp_id)
+ON CONFLICT DO NOTHING
+RETURNING user_id;
+
+-- name: GetMentionsForChirps :many
+SELECT cm.chirp_id, cm.user_id, u.handle
+FROM chirp_mentions cm
+JOIN users u ON u.id = cm.user_id
+WHERE cm.chirp_id = ANY(sqlc.arg(chirp_ids)::uuid[]);
+
+-- name: GetUserMentions :many
+SELECT
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    u.handle AS author_handle,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = sqlc.arg(user_id)
+    ) as liked_by_viewer
+FROM chirp_mentions cm
+JOIN chirps c ON c.id = cm.chirp_id
+JOIN users u ON u.id = c.user_id
+WHERE cm.user_id = sqlc.arg(user_id)
+AND c.user_id <> sqlc.arg(user_id)
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, sqlc.arg(user_id))
+AND (
+    sqlc.narg(cursor)::uuid IS NULL OR
+    (cm.created_at, cm.chirp_id) < (
+        SELECT created_at, chirp_id FROM chirp_mentions
+        WHERE chirp_id = sqlc.narg(cursor) AND user_id = sqlc.arg(user_id)
+    )
+)
+ORDER BY cm.created_at DESC, cm.chirp_id DESC
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,41 @@
+-- name: GetFilterWords :many
+SELECT * FROM filter_words
+ORDER BY word;
+
+-- name: GetFilterWordsStamp :one
+-- Changes whenever a word is added, removed or updated, so the filter only
+-- reloads the list when it has changed.
+SELECT (COUNT(*)::text || '-' || COALESCE(MAX(updated_at)::text, ''))::text AS stamp
+FROM filter_words;
+
+-- name: FlagChirpForReview :exec
+INSERT INTO chirp_flags (chirp_id, words)
+VALUES (sqlc.arg(chirp_id), sqlc.arg(words)::text[])
+ON CONFLICT (chirp_id) DO UPDATE
+SET words = EXCLUDED.words,
+    created_at = NOW();
+
+-- name: ClearChirpFlag :execrows
+DELETE FROM chirp_flags
+WHERE chirp_id = $1;
+
+-- name: GetFlaggedChirps :many
+SELECT
+    c.id,
+    c.body,
+    c.user_id,
+    c.created_at,
+    c.updated_at,
+    f.words,
+    f.created_at as flagged_at
+FROM chirp_flags f
+INNER JOIN chirps c ON f.chirp_id = c.id
+WHERE (
+    sqlc.narg(cursor)::uuid IS NULL OR
+    (f.created_at, f.chirp_id) < (
+        SELECT created_at, chirp_id FROM chirp_flags
+        WHERE chirp_id = sqlc.narg(cursor)
+    )
+)
+ORDER BY f.created_at DESC, f.chirp_id DESC
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,29 @@
+-- name: CreateMutedWord :one
+INSERT INTO muted_words (user_id, phrase, whole_word, action, expires_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(phrase), sqlc.arg(whole_word), sqlc.arg(action), sqlc.narg(expires_at))
+ON CONFLICT (user_id, LOWER(phrase)) DO UPDATE
+SET phrase = EXCLUDED.phrase,
+    whole_word = EXCLUDED.whole_word,
+    action = EXCLUDED.action,
+    expires_at = EXCLUDED.expires_at
+RETURNING *;
+
+-- name: GetMutedWords :many
+SELECT * FROM muted_words
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetActiveMutedWords :many
+SELECT * FROM muted_words
+WHERE user_id = $1
+AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: GetActiveMutedWordsForUsers :many
+-- Batched GetActiveMutedWords for filtering one event for many readers.
+SELECT * FROM muted_words
+WHERE user_id = ANY(sqlc.arg(user_ids)::uuid[])
+AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: DeleteMutedWord :execrows
+DELETE FROM muted_words
+WHERE id = $1 AND user_id = $2;
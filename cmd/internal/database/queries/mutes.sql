@@ -0,0 +1,28 @@
+-- name: MuteUser :exec
+INSERT INTO user_mutes (muter_id, muted_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: UnmuteUser :execrows
+DELETE FROM user_mutes
+WHERE muter_id = $1 AND muted_id = $2;
+
+-- name: GetMutedUsers :many
+SELECT
+    u.id,
+    u.handle,
+    u.display_name,
+    u.avatar_key,
+    m.created_at as muted_at
+FROM user_mutes m
+INNER JOIN users u ON m.muted_id = u.id
+WHERE m.muter_id = sqlc.arg(muter_id)
+AND (
+    sqlc.narg(cursor)::uuid IS NULL OR
+    (m.created_at, m.muted_id) < (
+        SELECT created_at, muted_id FROM user_mutes
+        WHERE muter_id = sqlc.arg(muter_id) AND muted_id = sqlc.narg(cursor)
+    )
+)
+ORDER BY m.created_at DESC, m.muted_id DESC
+LIMIT sqlc.arg(page_limit);
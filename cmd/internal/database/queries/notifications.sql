@@ -0,0 +1,80 @@
+-- name: CreateNotification :exec
+INSERT INTO notifications (user_id, actor_id, type, chirp_id, created_at)
+SELECT sqlc.arg(user_id)::uuid, sqlc.arg(actor_id)::uuid, sqlc.arg(type)::text, sqlc.narg(chirp_id)::uuid, NOW()
+WHERE sqlc.arg(user_id)::uuid <> sqlc.arg(actor_id)::uuid
+AND NOT EXISTS (
+    SELECT 1 FROM notification_mutes m
+    WHERE m.user_id = sqlc.arg(user_id)::uuid AND m.type = sqlc.arg(type)::text
+)
+ON CONFLICT (user_id, actor_id, type, COALESCE(chirp_id, '00000000-0000-0000-0000-000000000000'::uuid))
+DO NOTHING;
+
+-- name: GetNotificationGroups :many
+WITH groups AS (
+    SELECT
+        n.type,
+        n.chirp_id,
+        MAX(n.id)::bigint AS latest_id,
+        MAX(n.created_at)::timestamp AS latest_at,
+        COUNT(DISTINCT n.actor_id) AS actor_count,
+        COUNT(*) FILTER (WHERE n.read_at IS NULL) AS unread_count
+    FROM notifications n
+    WHERE n.user_id = sqlc.arg(user_id)
+    AND NOT EXISTS (
+        SELECT 1 FROM notification_mutes m
+        WHERE m.user_id = n.user_id AND m.type = n.type
+    )
+    AND NOT is_silenced(n.user_id, n.actor_id)
+    GROUP BY n.type, n.chirp_id
+)
+SELECT
+    g.type,
+    g.chirp_id,
+    g.latest_id,
+    g.latest_at,
+    g.actor_count,
+    g.unread_count,
+    ARRAY(
+        SELECT u.handle FROM notifications rn
+        JOIN users u ON u.id = rn.actor_id
+        WHERE rn.user_id = sqlc.arg(user_id)
+        AND rn.type = g.type
+        AND rn.chirp_id IS NOT DISTINCT FROM g.chirp_id
+        AND NOT is_silenced(rn.user_id, rn.actor_id)
+        ORDER BY rn.id DESC
+        LIMIT 3
+    )::text[] AS recent_actors
+FROM groups g
+WHERE sqlc.narg(cursor)::bigint IS NULL OR g.latest_id < sqlc.narg(cursor)
+ORDER BY g.latest_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications n
+WHERE n.user_id = $1
+AND n.read_at IS NULL
+AND NOT EXISTS (
+    SELECT 1 FROM notification_mutes m
+    WHERE m.user_id = n.user_id AND m.type = n.type
+)
+AND NOT is_silenced(n.user_id, n.actor_id);
+
+-- name: MarkNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = sqlc.arg(user_id)
+AND read_at IS NULL
+AND (sqlc.narg(up_to_id)::bigint IS NULL OR id <= sqlc.narg(up_to_id));
+
+-- name: GetNotificationMutes :many
+SELECT type FROM notification_mutes WHERE user_id = $1 ORDER BY type;
+
+-- name: SetNotificationMutes :exec
+WITH removed AS (
+    DELETE FROM notification_mutes
+    WHERE user_id = sqlc.arg(user_id)
+    AND type <> ALL(sqlc.arg(types)::text[])
+)
+INSERT INTO notification_mutes (user_id, type)
+SELECT sqlc.arg(user_id), unnest(sqlc.arg(types)::text[])
+ON CONFLICT DO NOTHING;
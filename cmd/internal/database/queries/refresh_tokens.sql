@@ -1,19 +1,57 @@
--- name: CreateRefreshToken :one
-INSERT INTO refresh_tokens (token, created_at, updated_at, user_id, expires_at, revoked_at)
-VALUES ($1, NOW(), NOW(), $2, $3, NULL)
-RETURNING *;
-
--- name: GetRefreshToken :one
-SELECT * FROM refresh_tokens WHERE token = $1;
-
--- name: GetUserFromRefreshToken :one
-SELECT users.* FROM users
-INNER JOIN refresh_tokens ON users.id = refresh_tokens.user_id
-WHERE refresh_tokens.token = $1
-  AND refresh_tokens.revoked_at IS NULL
-  AND refresh_tokens.expires_at > NOW();
-
--- name: RevokeRefreshToken :exec
-UPDATE refresh_tokens
-SET revoked_at = NOW(), updated_at = NOW()
-WHERE token = $1;
\ No newline at end of file
+-- name: GetRefreshToken :one
+SELECT * FROM refresh_tokens WHERE token_hash = $1;
+
+-- name: GetUserFromRefreshToken :one
+SELECT users.* FROM users
+INNER JOIN refresh_tokens ON users.id = refresh_tokens.user_id
+WHERE refresh_tokens.token_hash = $1
+  AND refresh_tokens.revoked_at IS NULL
+  AND refresh_tokens.expires_at > NOW()
+  AND users.deleted_at IS NULL;
+
+-- name: PurgeExpiredRefreshTokens :execrows
+DELETE FROM refresh_tokens
+WHERE expires_at < NOW();
+
+-- name: RevokeRefreshToken :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE token_hash = $1;
+
+-- name: RevokeRefreshTokenFamily :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: RotateRefreshToken :one
+-- Revokes the presented token and issues its successor in the same family,
+-- recording the device that asked on the session.
+-- Returns no rows when the presented token is already revoked or expired, so
+-- two concurrent refreshes with one token cannot both succeed.
+WITH rotated AS (
+    UPDATE refresh_tokens rt
+    SET revoked_at = NOW(), updated_at = NOW()
+    FROM users u
+    WHERE rt.token_hash = sqlc.arg(parent_hash)
+      AND rt.revoked_at IS NULL
+      AND rt.expires_at > NOW()
+      AND u.id = rt.user_id
+      AND u.deleted_at IS NULL
+    RETURNING rt.token_hash, rt.user_id, rt.family_id
+),
+touched AS (
+    UPDATE sessions
+    SET last_used_at = NOW(),
+        user_agent = sqlc.arg(user_agent),
+        ip_address = sqlc.arg(ip_address)
+    WHERE id IN (SELECT family_id FROM rotated)
+)
+INSERT INTO refresh_tokens (token_hash, created_at, updated_at, user_id, expires_at, revoked_at, family_id, parent_hash)
+SELECT sqlc.arg(token_hash), NOW(), NOW(), rotated.user_id, sqlc.arg(expires_at), NULL, rotated.family_id, rotated.token_hash
+FROM rotated
+RETURNING *;
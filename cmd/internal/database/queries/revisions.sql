@@ -0,0 +1,12 @@
+-- name: GetChirpRevisions :many
+SELECT * FROM chirp_revisions
+WHERE chirp_id = sqlc.arg(chirp_id)
+AND (
+    sqlc.narg(cursor)::uuid IS NULL OR
+    (replaced_at, id) < (
+        SELECT replaced_at, id FROM chirp_revisions
+        WHERE chirp_id = sqlc.arg(chirp_id) AND id = sqlc.narg(cursor)
+    )
+)
+ORDER BY replaced_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
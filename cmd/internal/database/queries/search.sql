@@ -0,0 +1,131 @@
+-- name: SearchChirps :many
+WITH search AS (
+    SELECT to_tsquery('english', sqlc.arg(query)) AS q
+),
+matches AS (
+    SELECT
+        c.id,
+        c.created_at,
+        c.edit_count,
+        c.edited,
+        c.body,
+        c.user_id,
+        c.parent_id,
+        c.kind,
+        c.original_id,
+        ts_rank(c.search_vector, search.q) AS rank
+    FROM chirps c, search
+    WHERE c.search_vector @@ search.q
+    AND c.deleted_at IS NULL
+    AND (sqlc.narg(author_id)::uuid IS NULL OR c.user_id = sqlc.narg(author_id))
+    AND (sqlc.narg(since)::timestamp IS NULL OR c.created_at >= sqlc.narg(since))
+    AND (sqlc.narg(until)::timestamp IS NULL OR c.created_at < sqlc.narg(until))
+    AND (
+        NOT sqlc.arg(following_only)::boolean OR EXISTS (
+            SELECT 1 FROM follows f
+            WHERE f.follower_id = sqlc.narg(viewer_id) AND f.followee_id = c.user_id
+        )
+    )
+    AND can_view_chirps(c.user_id, sqlc.narg(viewer_id))
+)
+SELECT
+    m.id,
+    m.created_at,
+    m.edit_count,
+    m.edited,
+    m.body,
+    m.user_id,
+    m.parent_id,
+    m.kind,
+    m.original_id,
+    u.handle AS author_handle,
+    m.rank,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = m.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = m.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = m.id AND lv.user_id = sqlc.narg(viewer_id)
+    ) as liked_by_viewer
+FROM matches m
+JOIN users u ON u.id = m.user_id
+WHERE sqlc.narg(cursor)::uuid IS NULL OR (m.rank, m.created_at, m.id) < (
+    SELECT cm.rank, cm.created_at, cm.id FROM matches cm WHERE cm.id = sqlc.narg(cursor)
+)
+ORDER BY m.rank DESC, m.created_at DESC, m.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: SearchUsers :many
+WITH candidates AS (
+    SELECT
+        u.id,
+        u.handle,
+        u.display_name,
+        u.avatar_key,
+        u.is_chirpy_red,
+        EXISTS(
+            SELECT 1 FROM follows f
+            WHERE f.follower_id = sqlc.narg(viewer_id) AND f.followee_id = u.id
+        ) AS is_following,
+        EXISTS(
+            SELECT 1 FROM follows f
+            WHERE f.follower_id = u.id AND f.followee_id = sqlc.narg(viewer_id)
+        ) AS follows_viewer,
+        (
+            GREATEST(
+                similarity(LOWER(u.handle), sqlc.arg(query)),
+                similarity(LOWER(u.display_name), sqlc.arg(query)),
+                similarity(LOWER(split_part(u.email, '@', 1)), sqlc.arg(query))
+            ) + CASE
+                WHEN LOWER(u.handle) LIKE sqlc.arg(prefix)
+                    OR LOWER(u.display_name) LIKE sqlc.arg(prefix)
+                    OR LOWER(split_part(u.email, '@', 1)) LIKE sqlc.arg(prefix)
+                THEN 1 ELSE 0
+            END
+        )::real AS match_score
+    FROM users u
+    WHERE (sqlc.narg(viewer_id)::uuid IS NULL OR u.id <> sqlc.narg(viewer_id))
+    AND u.deleted_at IS NULL
+    -- Blocks in either direction hide the account from search.
+    AND NOT is_blocked(u.id, sqlc.narg(viewer_id))
+    AND (
+        LOWER(u.handle) LIKE sqlc.arg(prefix)
+        OR LOWER(u.display_name) LIKE sqlc.arg(prefix)
+        OR LOWER(split_part(u.email, '@', 1)) LIKE sqlc.arg(prefix)
+        OR LOWER(u.handle) % sqlc.arg(query)
+        OR LOWER(u.display_name) % sqlc.arg(query)
+        OR LOWER(split_part(u.email, '@', 1)) % sqlc.arg(query)
+    )
+),
+ranked AS (
+    SELECT
+        c.id,
+        c.handle,
+        c.display_name,
+        c.avatar_key,
+        c.is_chirpy_red,
+        c.is_following,
+        c.follows_viewer,
+        c.match_score,
+        (CASE
+            WHEN c.is_following AND c.follows_viewer THEN 2
+            WHEN c.is_following THEN 1
+            ELSE 0
+        END)::int AS relationship_rank
+    FROM candidates c
+)
+SELECT
+    r.id,
+    r.handle,
+    r.display_name,
+    r.avatar_key,
+    r.is_chirpy_red,
+    r.is_following,
+    r.follows_viewer,
+    r.match_score,
+    r.relationship_rank
+FROM ranked r
+WHERE sqlc.narg(cursor)::uuid IS NULL OR (r.relationship_rank, r.match_score, r.id) < (
+    SELECT cr.relationship_rank, cr.match_score, cr.id FROM ranked cr WHERE cr.id = sqlc.narg(cursor)
+)
+ORDER BY r.relationship_rank DESC, r.match_score DESC, r.id DESC
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,50 @@
+-- name: CreateSession :one
+-- Starts a session and issues its first refresh token.
+WITH session AS (
+    INSERT INTO sessions (id, user_id, name, user_agent, ip_address, created_at, last_used_at)
+    VALUES (sqlc.arg(id), sqlc.arg(user_id), sqlc.narg(name), sqlc.arg(user_agent), sqlc.arg(ip_address), NOW(), NOW())
+    RETURNING id, user_id
+)
+INSERT INTO refresh_tokens (token_hash, created_at, updated_at, user_id, expires_at, revoked_at, family_id, parent_hash)
+SELECT sqlc.arg(token_hash), NOW(), NOW(), session.user_id, sqlc.arg(expires_at), NULL, session.id, NULL
+FROM session
+RETURNING *;
+
+-- name: GetUserSessions :many
+-- Sessions that still hold a usable refresh token.
+SELECT s.id, s.user_id, s.name, s.user_agent, s.ip_address, s.created_at, s.last_used_at
+FROM sessions s
+WHERE s.user_id = $1
+AND EXISTS (
+    SELECT 1 FROM refresh_tokens rt
+    WHERE rt.family_id = s.id AND rt.revoked_at IS NULL AND rt.expires_at > NOW()
+)
+ORDER BY s.last_used_at DESC, s.id DESC;
+
+-- name: PurgeExpiredSessions :execrows
+DELETE FROM sessions s
+WHERE NOT EXISTS (
+    SELECT 1 FROM refresh_tokens rt
+    WHERE rt.family_id = s.id AND rt.expires_at > NOW()
+);
+
+-- name: RevokeAllSessions :exec
+-- Revokes every refresh token of the user and bumps the token version so
+-- outstanding access tokens stop validating too.
+WITH revoked AS (
+    UPDATE refresh_tokens
+    SET revoked_at = NOW(), updated_at = NOW()
+    WHERE user_id = sqlc.arg(user_id) AND revoked_at IS NULL
+)
+UPDATE users
+SET token_version = token_version + 1, updated_at = NOW()
+WHERE id = sqlc.arg(user_id);
+
+-- name: RevokeSession :execrows
+UPDATE refresh_tokens rt
+SET revoked_at = NOW(), updated_at = NOW()
+FROM sessions s
+WHERE s.id = rt.family_id
+  AND s.id = sqlc.arg(id)
+  AND s.user_id = sqlc.arg(user_id)
+  AND rt.revoked_at IS NULL;
@@ -0,0 +1,211 @@
+-- name: FanOutChirp :exec
+-- Pushes a chirp into the home timelines of its author's followers. When the
+-- author is large enough to be read at query time instead, the chirp is
+-- recorded in pulled_chirps. A newer activity for a chirp already in a
+-- timeline (e.g. a rechirp) moves it up.
+WITH target AS (
+    SELECT c.id, c.kind, c.original_id, c.user_id, c.created_at,
+        a.follower_count < sqlc.arg(fanout_limit)::int AS fan_out
+    FROM chirps c
+    JOIN users a ON a.id = c.user_id
+    WHERE c.id = sqlc.arg(chirp_id)
+      AND c.deleted_at IS NULL
+      AND (c.kind <> 'rechirp' OR c.original_id IS NOT NULL)
+),
+pulled AS (
+    INSERT INTO pulled_chirps (chirp_id, user_id, created_at)
+    SELECT id, user_id, created_at FROM target WHERE NOT fan_out
+    ON CONFLICT DO NOTHING
+)
+INSERT INTO home_timeline (user_id, chirp_id, activity_id, rechirped_by, activity_at)
+SELECT
+    f.follower_id,
+    CASE WHEN t.kind = 'rechirp' THEN t.original_id ELSE t.id END,
+    t.id,
+    CASE WHEN t.kind = 'rechirp' THEN t.user_id END,
+    t.created_at
+FROM target t
+JOIN follows f ON f.followee_id = t.user_id
+WHERE t.fan_out
+ON CONFLICT (user_id, chirp_id) DO UPDATE
+SET activity_id = EXCLUDED.activity_id,
+    rechirped_by = EXCLUDED.rechirped_by,
+    activity_at = EXCLUDED.activity_at
+WHERE (EXCLUDED.activity_at, EXCLUDED.activity_id) > (home_timeline.activity_at, home_timeline.activity_id);
+
+-- name: BackfillHomeTimeline :exec
+-- Copies the most recent chirps of a newly followed account into the
+-- follower's timeline. Pulled chirps are left to the read path.
+INSERT INTO home_timeline (user_id, chirp_id, activity_id, rechirped_by, activity_at)
+SELECT sqlc.arg(follower_id)::uuid, recent.chirp_id, recent.activity_id, recent.rechirped_by, recent.activity_at
+FROM (
+    SELECT DISTINCT ON (chirp_id) chirp_id, activity_id, rechirped_by, activity_at
+    FROM (
+        SELECT
+            CASE WHEN c.kind = 'rechirp' THEN c.original_id ELSE c.id END as chirp_id,
+            c.id as activity_id,
+            CASE WHEN c.kind = 'rechirp' THEN c.user_id END as rechirped_by,
+            c.created_at as activity_at
+        FROM chirps c
+        WHERE c.user_id = sqlc.arg(followee_id)
+          AND c.deleted_at IS NULL
+          AND NOT EXISTS (SELECT 1 FROM pulled_chirps p WHERE p.chirp_id = c.id)
+          AND (c.kind <> 'rechirp' OR c.original_id IS NOT NULL)
+        ORDER BY c.created_at DESC, c.id DESC
+        LIMIT sqlc.arg(backfill_limit)
+    ) latest
+    ORDER BY chirp_id, activity_at DESC, activity_id DESC
+) recent
+ON CONFLICT (user_id, chirp_id) DO UPDATE
+SET activity_id = EXCLUDED.activity_id,
+    rechirped_by = EXCLUDED.rechirped_by,
+    activity_at = EXCLUDED.activity_at
+WHERE (EXCLUDED.activity_at, EXCLUDED.activity_id) > (home_timeline.activity_at, home_timeline.activity_id);
+
+-- name: PruneHomeTimeline :exec
+-- Cleans up after an unfollow. Rows the unfollowed account put into the
+-- follower's timeline fall back to the latest post or rechirp of the same
+-- chirp by an account still followed, and are only removed when no remaining
+-- follow justifies them.
+WITH stale AS (
+    SELECT ht.chirp_id
+    FROM home_timeline ht
+    JOIN chirps c ON c.id = ht.activity_id
+    WHERE ht.user_id = sqlc.arg(follower_id)
+      AND c.user_id = sqlc.arg(followee_id)
+),
+replacement AS (
+    SELECT DISTINCT ON (s.chirp_id)
+        s.chirp_id,
+        a.id as activity_id,
+        CASE WHEN a.kind = 'rechirp' THEN a.user_id END as rechirped_by,
+        a.created_at as activity_at
+    FROM stale s
+    JOIN chirps a ON a.id = s.chirp_id OR (a.kind = 'rechirp' AND a.original_id = s.chirp_id)
+    JOIN follows f ON f.followee_id = a.user_id AND f.follower_id = sqlc.arg(follower_id)
+    WHERE a.user_id <> sqlc.arg(followee_id)
+      AND a.deleted_at IS NULL
+    ORDER BY s.chirp_id, a.created_at DESC, a.id DESC
+),
+repointed AS (
+    UPDATE home_timeline ht
+    SET activity_id = r.activity_id,
+        rechirped_by = r.rechirped_by,
+        activity_at = r.activity_at
+    FROM replacement r
+    WHERE ht.user_id = sqlc.arg(follower_id)
+      AND ht.chirp_id = r.chirp_id
+)
+DELETE FROM home_timeline ht
+USING stale s
+WHERE ht.user_id = sqlc.arg(follower_id)
+  AND ht.chirp_id = s.chirp_id
+  AND NOT EXISTS (SELECT 1 FROM replacement r WHERE r.chirp_id = s.chirp_id);
+
+-- name: GetHomeTimeline :many
+-- Reads the materialized timeline and merges in the pulled chirps of
+-- followed accounts, posted while they were too large to fan out on write.
+-- Each source is cut to one page on the keyset before merging, after
+-- dropping chirps the viewer may not see or has muted so pages stay full.
+-- Cursors are the (activity_at, activity_id) position itself, so paging
+-- carries on after the activity behind a cursor is deleted.
+WITH fanned AS (
+    SELECT ht.chirp_id, ht.rechirped_by, ht.activity_at, ht.activity_id
+    FROM home_timeline ht
+    JOIN chirps fc ON fc.id = ht.chirp_id
+    WHERE ht.user_id = sqlc.arg(viewer_id)
+    AND fc.deleted_at IS NULL
+    AND can_view_chirps(fc.user_id, sqlc.arg(viewer_id))
+    AND NOT is_silenced(sqlc.arg(viewer_id), fc.user_id)
+    AND (ht.rechirped_by IS NULL OR NOT is_silenced(sqlc.arg(viewer_id), ht.rechirped_by))
+    AND (
+        sqlc.narg(cursor_at)::timestamp IS NULL OR
+        (ht.activity_at, ht.activity_id) < (sqlc.narg(cursor_at)::timestamp, sqlc.narg(cursor_id)::uuid)
+    )
+    AND (
+        sqlc.narg(newer_at)::timestamp IS NULL OR
+        (ht.activity_at, ht.activity_id) > (sqlc.narg(newer_at)::timestamp, sqlc.narg(newer_id)::uuid)
+    )
+    ORDER BY
+        CASE WHEN sqlc.narg(newer_at)::timestamp IS NULL THEN ht.activity_at END DESC,
+        CASE WHEN sqlc.narg(newer_at)::timestamp IS NULL THEN ht.activity_id END DESC,
+        ht.activity_at ASC,
+        ht.activity_id ASC
+    LIMIT sqlc.arg(page_limit)
+),
+pulled AS (
+    SELECT 
+        CASE WHEN c.kind = 'rechirp' THEN c.original_id ELSE c.id END as chirp_id,
+        CASE WHEN c.kind = 'rechirp' THEN c.user_id END as rechirped_by,
+        c.created_at as activity_at,
+        c.id as activity_id
+    FROM follows f
+    JOIN users a ON a.id = f.followee_id
+    JOIN pulled_chirps p ON p.user_id = f.followee_id
+    JOIN chirps c ON c.id = p.chirp_id
+    LEFT JOIN chirps oc ON c.kind = 'rechirp' AND oc.id = c.original_id
+    WHERE f.follower_id = sqlc.arg(viewer_id)
+      AND a.deleted_at IS NULL
+      AND c.deleted_at IS NULL
+      AND (c.kind <> 'rechirp' OR c.original_id IS NOT NULL)
+      AND NOT is_silenced(sqlc.arg(viewer_id), c.user_id)
+      AND (oc.id IS NULL OR (
+          oc.deleted_at IS NULL
+          AND can_view_chirps(oc.user_id, sqlc.arg(viewer_id))
+          AND NOT is_silenced(sqlc.arg(viewer_id), oc.user_id)
+      ))
+    AND (
+        sqlc.narg(cursor_at)::timestamp IS NULL OR
+        (p.created_at, p.chirp_id) < (sqlc.narg(cursor_at)::timestamp, sqlc.narg(cursor_id)::uuid)
+    )
+    AND (
+        sqlc.narg(newer_at)::timestamp IS NULL OR
+        (p.created_at, p.chirp_id) > (sqlc.narg(newer_at)::timestamp, sqlc.narg(newer_id)::uuid)
+    )
+    ORDER BY
+        CASE WHEN sqlc.narg(newer_at)::timestamp IS NULL THEN p.created_at END DESC,
+        CASE WHEN sqlc.narg(newer_at)::timestamp IS NULL THEN p.chirp_id END DESC,
+        p.created_at ASC,
+        p.chirp_id ASC
+    LIMIT sqlc.arg(page_limit)
+),
+latest_items AS (
+    SELECT DISTINCT ON (chirp_id) chirp_id, rechirped_by, activity_at, activity_id
+    FROM (
+        SELECT * FROM fanned
+        UNION ALL
+        SELECT * FROM pulled
+    ) merged
+    ORDER BY chirp_id, activity_at DESC, activity_id DESC
+)
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    u.handle as author_handle,
+    li.rechirped_by,
+    li.activity_at,
+    li.activity_id,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM chirps rc WHERE rc.original_id = c.id AND rc.kind = 'rechirp' AND rc.deleted_at IS NULL) as rechirp_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = sqlc.arg(viewer_id)
+    ) as liked_by_viewer
+FROM latest_items li
+INNER JOIN chirps c ON c.id = li.chirp_id
+INNER JOIN users u ON c.user_id = u.id
+ORDER BY
+    CASE WHEN sqlc.narg(newer_at)::timestamp IS NULL THEN li.activity_at END DESC,
+    CASE WHEN sqlc.narg(newer_at)::timestamp IS NULL THEN li.activity_id END DESC,
+    li.activity_at ASC,
+    li.activity_id ASC
+LIMIT sqlc.arg(page_limit);
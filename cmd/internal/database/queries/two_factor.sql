@@ -0,0 +1,94 @@
+-- name: AttemptLoginChallenge :one
+-- Counts an attempt against a pending login challenge. Returns no rows once
+-- the challenge has expired or run out of attempts.
+UPDATE login_challenges
+SET attempts = attempts + 1
+WHERE token_hash = sqlc.arg(token_hash)
+  AND expires_at > NOW()
+  AND attempts < sqlc.arg(max_attempts)
+RETURNING user_id, device_name;
+
+-- name: CreateLoginChallenge :exec
+INSERT INTO login_challenges (token_hash, user_id, device_name, attempts, created_at, expires_at)
+VALUES ($1, $2, $3, 0, NOW(), $4);
+
+-- name: DeleteLoginChallenge :execrows
+DELETE FROM login_challenges WHERE token_hash = $1;
+
+-- name: DisableTOTP :execrows
+-- Turns 2FA off and discards the recovery codes.
+WITH cleared AS (
+    DELETE FROM recovery_codes WHERE user_id = $1
+)
+UPDATE users
+SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0, updated_at = NOW()
+WHERE id = $1 AND totp_enabled_at IS NOT NULL;
+
+-- name: EnableTOTP :execrows
+-- Turns on 2FA for the pending secret once a first code has been verified
+-- and stores the hashes of the recovery codes handed out with it.
+WITH enabled AS (
+    UPDATE users
+    SET totp_enabled_at = NOW(), totp_last_step = sqlc.arg(step), updated_at = NOW()
+    WHERE id = sqlc.arg(id)
+      AND totp_secret = sqlc.arg(totp_secret)
+      AND totp_enabled_at IS NULL
+    RETURNING id
+)
+INSERT INTO recovery_codes (id, user_id, code_hash, created_at, used_at)
+SELECT gen_random_uuid(), enabled.id, code_hash, NOW(), NULL
+FROM enabled, unnest(sqlc.arg(code_hashes)::text[]) AS code_hash;
+
+-- name: GetUserTwoFactor :one
+SELECT
+    hashed_password,
+    totp_secret,
+    totp_enabled_at,
+    totp_last_step,
+    COALESCE(two_factor_locked_until > NOW(), false)::boolean as two_factor_locked
+FROM users
+WHERE id = $1;
+
+-- name: PurgeExpiredLoginChallenges :execrows
+DELETE FROM login_challenges
+WHERE expires_at < NOW();
+
+-- name: RecordTwoFactorFailure :one
+-- Counts a failed second factor. The attempt that reaches max_failures locks
+-- 2FA logins for lockout_seconds and starts the count over.
+UPDATE users
+SET two_factor_failures = CASE
+        WHEN two_factor_failures + 1 >= sqlc.arg(max_failures)::int THEN 0
+        ELSE two_factor_failures + 1
+    END,
+    two_factor_locked_until = CASE
+        WHEN two_factor_failures + 1 >= sqlc.arg(max_failures)::int
+        THEN NOW() + sqlc.arg(lockout_seconds)::int * INTERVAL '1 second'
+        ELSE two_factor_locked_until
+    END
+WHERE id = sqlc.arg(id)
+RETURNING COALESCE(two_factor_locked_until > NOW(), false)::boolean as two_factor_locked;
+
+-- name: ResetTwoFactorFailures :exec
+UPDATE users
+SET two_factor_failures = 0
+WHERE id = $1;
+
+-- name: SetPendingTOTPSecret :execrows
+-- Starts or restarts enrollment. Fails once 2FA is enabled, so an active
+-- secret can only be replaced by disabling 2FA first.
+UPDATE users
+SET totp_secret = $2, updated_at = NOW()
+WHERE id = $1 AND totp_enabled_at IS NULL;
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: UseTOTPStep :execrows
+-- Records the time step of an accepted code. Returns no rows if that step,
+-- or a later one, was already used.
+UPDATE users
+SET totp_last_step = $2
+WHERE id = $1 AND totp_enabled_at IS NOT NULL AND totp_last_step < $2;
@@ -1,73 +1,166 @@
--- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email, hashed_password)
-VALUES (
-    gen_random_uuid(),
-    NOW(),
-    NOW(),
-    $1,
-    $2
-)
-RETURNING id, created_at, updated_at, email, is_chirpy_red;
-
--- name: GetUserByID :one
-SELECT id, created_at, updated_at, email, is_chirpy_red FROM users WHERE id = $1;
-
--- name: GetUserByEmail :one
-SELECT id, created_at, updated_at, email, is_chirpy_red FROM users WHERE email = $1;
-
--- name: GetUserPassByEmail :one
-SELECT hashed_password FROM users WHERE email = $1;
-
--- name: UpdateUserCred :one
-UPDATE users
-SET updated_at = NOW(),
-    email = $1,
-    hashed_password = $2
-WHERE id = $3
-RETURNING id, created_at, updated_at, email, is_chirpy_red;
-
--- name: UpdateUserToChirpyRed :exec
-UPDATE users
-SET is_chirpy_red = TRUE,
-    updated_at = NOW()
-WHERE id = $1;
-
--- name: DeleteUser :exec
-DELETE FROM users;
-
--- name: DeleteUserByID :exec
-DELETE FROM users WHERE id = $1;
-
--- name: GetUserProfile :one
-WITH user_stats AS (
-    SELECT 
-        u.id,
-        u.email,
-        u.created_at,
-        u.updated_at,
-        u.is_chirpy_red,
-        (SELECT COUNT(*) FROM follows WHERE followee_id = u.id) as followers_count,
-        (SELECT COUNT(*) FROM follows WHERE follower_id = u.id) as following_count,
-        (SELECT COUNT(*) FROM chirps WHERE user_id = u.id) as chirps_count
-    FROM users u
-    WHERE u.id = $1
-)
-SELECT * FROM user_stats;
-
--- name: GetUserChirpsPaginated :many
-SELECT 
-    c.id,
-    c.created_at,
-    c.updated_at,
-    c.body,
-    c.user_id
-FROM chirps c
-WHERE c.user_id = sqlc.arg(user_id)
-AND (
-    sqlc.narg(cursor)::uuid IS NULL OR 
-    (c.created_at, c.id) < (
-        SELECT created_at, id FROM chirps WHERE id = sqlc.narg(cursor)
-    )
-)
-ORDER BY c.created_at DESC, c.id DESC
+-- name: CreateUser :one
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
+VALUES (
+    gen_random_uuid(),
+    NOW(),
+    NOW(),
+    $1,
+    $2,
+    $3
+)
+RETURNING id, created_at, updated_at, email, is_chirpy_red, handle;
+
+-- name: GetUserByID :one
+SELECT id, created_at, updated_at, email, is_chirpy_red, handle FROM users WHERE id = $1;
+
+-- name: GetUserByEmail :one
+SELECT id, created_at, updated_at, email, is_chirpy_red, handle FROM users WHERE email = $1;
+
+-- name: GetUserByHandle :one
+SELECT id, created_at, updated_at, email, is_chirpy_red, handle FROM users WHERE LOWER(handle) = LOWER(sqlc.arg(handle)) AND deleted_at IS NULL;
+
+-- name: GetUserPassByEmail :one
+SELECT hashed_password FROM users WHERE email = $1;
+
+-- name: UpdateUserCred :one
+UPDATE users
+SET updated_at = NOW(),
+    email = sqlc.arg(email),
+    hashed_password = sqlc.arg(hashed_password),
+    handle = COALESCE(sqlc.narg(handle), handle)
+WHERE id = sqlc.arg(id)
+RETURNING id, created_at, updated_at, email, is_chirpy_red, handle;
+
+-- name: UpdateUserProfile :exec
+UPDATE users
+SET updated_at = NOW(),
+    display_name = COALESCE(sqlc.narg(display_name), display_name),
+    bio = COALESCE(sqlc.narg(bio), bio),
+    location = COALESCE(sqlc.narg(location), location),
+    website = COALESCE(sqlc.narg(website), website)
+WHERE id = sqlc.arg(id);
+
+-- name: SetUserAvatar :one
+UPDATE users u
+SET updated_at = NOW(),
+    avatar_key = sqlc.arg(avatar_key)
+FROM (SELECT id, avatar_key FROM users WHERE id = sqlc.arg(id)) prev
+WHERE u.id = prev.id
+RETURNING prev.avatar_key AS previous_avatar_key;
+
+-- name: UpdateUserToChirpyRed :exec
+UPDATE users
+SET is_chirpy_red = TRUE,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetUserPrivacy :one
+SELECT is_private FROM users WHERE id = $1;
+
+-- name: GetUserTokenVersion :one
+SELECT token_version FROM users WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetUserIsAdmin :one
+SELECT is_admin FROM users WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SetUserPrivacy :exec
+UPDATE users
+SET is_private = sqlc.arg(is_private),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteUser :exec
+DELETE FROM users;
+
+-- name: DeleteUserByID :exec
+DELETE FROM users WHERE id = $1;
+
+-- name: ScheduleUserDeletion :one
+UPDATE users
+SET deleted_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING deleted_at;
+
+-- name: CancelUserDeletion :execrows
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: PurgeDeletedUsers :execrows
+-- Hard-deletes accounts past their grace period. Their follows go with them
+-- through ON DELETE CASCADE, so the cached follower counts of the accounts
+-- they followed are decremented in the same statement.
+WITH purged AS (
+    SELECT id FROM users
+    WHERE deleted_at < NOW() - sqlc.arg(retention_seconds)::int * INTERVAL '1 second'
+    FOR UPDATE
+),
+unfollowed AS (
+    UPDATE users u
+    SET follower_count = u.follower_count - f.total
+    FROM (
+        SELECT followee_id, COUNT(*) as total
+        FROM follows
+        WHERE follower_id IN (SELECT id FROM purged)
+        GROUP BY followee_id
+    ) f
+    WHERE u.id = f.followee_id
+      AND u.id NOT IN (SELECT id FROM purged)
+)
+DELETE FROM users
+WHERE id IN (SELECT id FROM purged);
+
+-- name: GetUserProfile :one
+WITH user_stats AS (
+    SELECT 
+        u.id,
+        u.email,
+        u.handle,
+        u.display_name,
+        u.bio,
+        u.location,
+        u.website,
+        u.avatar_key,
+        u.created_at,
+        u.updated_at,
+        u.is_chirpy_red,
+        u.is_private,
+        (SELECT COUNT(*) FROM follows WHERE followee_id = u.id) as followers_count,
+        (SELECT COUNT(*) FROM follows WHERE follower_id = u.id) as following_count,
+        (SELECT COUNT(*) FROM chirps WHERE user_id = u.id AND deleted_at IS NULL) as chirps_count
+    FROM users u
+    WHERE u.id = $1
+    AND u.deleted_at IS NULL
+)
+SELECT * FROM user_stats;
+
+-- name: GetUserChirpsPaginated :many
+SELECT 
+    c.id,
+    c.created_at,
+    c.updated_at,
+    c.edit_count,
+    c.edited,
+    c.body,
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = sqlc.narg(viewer_id)
+    ) as liked_by_viewer
+FROM chirps c
+WHERE c.user_id = sqlc.arg(user_id)
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, sqlc.narg(viewer_id))
+AND (
+    sqlc.narg(cursor)::uuid IS NULL OR 
+    (c.created_at, c.id) < (
+        SELECT created_at, id FROM chirps WHERE id = sqlc.narg(cursor)
+    )
+)
+ORDER BY c.created_at DESC, c.id DESC
 LIMIT sqlc.arg(page_limit);
\ No newline at end of file
@@ -7,26 +7,43 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const cancelUserDeletion = `-- name: CancelUserDeletion :execrows
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) CancelUserDeletion(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cancelUserDeletion, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email, hashed_password)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
 VALUES (
     gen_random_uuid(),
     NOW(),
     NOW(),
     $1,
-    $2
+    $2,
+    $3
 )
-RETURNING id, created_at, updated_at, email, is_chirpy_red
+RETURNING id, created_at, updated_at, email, is_chirpy_red, handle
 `
 
 type CreateUserParams struct {
 	Email          string `json:"email"`
 	HashedPassword string `json:"hashed_password"`
+	Handle         string `json:"handle"`
 }
 
 type CreateUserRow struct {
@@ -35,10 +52,11 @@ type CreateUserRow struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	Email       string    `json:"email"`
 	IsChirpyRed bool      `json:"is_chirpy_red"`
+	Handle      string    `json:"handle"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword)
+	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword, arg.Handle)
 	var i CreateUserRow
 	err := row.Scan(
 		&i.ID,
@@ -46,6 +64,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 		&i.UpdatedAt,
 		&i.Email,
 		&i.IsChirpyRed,
+		&i.Handle,
 	)
 	return i, err
 }
@@ -69,7 +88,7 @@ func (q *Queries) DeleteUserByID(ctx context.Context, id uuid.UUID) error {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, created_at, updated_at, email, is_chirpy_red FROM users WHERE email = $1
+SELECT id, created_at, updated_at, email, is_chirpy_red, handle FROM users WHERE email = $1
 `
 
 type GetUserByEmailRow struct {
@@ -78,6 +97,7 @@ type GetUserByEmailRow struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	Email       string    `json:"email"`
 	IsChirpyRed bool      `json:"is_chirpy_red"`
+	Handle      string    `json:"handle"`
 }
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
@@ -89,12 +109,40 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEm
 		&i.UpdatedAt,
 		&i.Email,
 		&i.IsChirpyRed,
+		&i.Handle,
+	)
+	return i, err
+}
+
+const getUserByHandle = `-- name: GetUserByHandle :one
+SELECT id, created_at, updated_at, email, is_chirpy_red, handle FROM users WHERE LOWER(handle) = LOWER($1) AND deleted_at IS NULL
+`
+
+type GetUserByHandleRow struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Email       string    `json:"email"`
+	IsChirpyRed bool      `json:"is_chirpy_red"`
+	Handle      string    `json:"handle"`
+}
+
+func (q *Queries) GetUserByHandle(ctx context.Context, handle string) (GetUserByHandleRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserByHandle, handle)
+	var i GetUserByHandleRow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.IsChirpyRed,
+		&i.Handle,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, created_at, updated_at, email, is_chirpy_red FROM users WHERE id = $1
+SELECT id, created_at, updated_at, email, is_chirpy_red, handle FROM users WHERE id = $1
 `
 
 type GetUserByIDRow struct {
@@ -103,6 +151,7 @@ type GetUserByIDRow struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	Email       string    `json:"email"`
 	IsChirpyRed bool      `json:"is_chirpy_red"`
+	Handle      string    `json:"handle"`
 }
 
 func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (GetUserByIDRow, error) {
@@ -114,6 +163,7 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (GetUserByIDRow
 		&i.UpdatedAt,
 		&i.Email,
 		&i.IsChirpyRed,
+		&i.Handle,
 	)
 	return i, err
 }
@@ -123,41 +173,84 @@ SELECT
     c.id,
     c.created_at,
     c.updated_at,
+    c.edit_count,
+    c.edited,
     c.body,
-    c.user_id
+    c.user_id,
+    c.parent_id,
+    c.kind,
+    c.original_id,
+    (SELECT COUNT(*) FROM chirps r WHERE r.parent_id = c.id AND r.deleted_at IS NULL) as reply_count,
+    (SELECT COUNT(*) FROM likes l WHERE l.chirp_id = c.id) as like_count,
+    EXISTS(
+        SELECT 1 FROM likes lv
+        WHERE lv.chirp_id = c.id AND lv.user_id = $1
+    ) as liked_by_viewer
 FROM chirps c
-WHERE c.user_id = $1
+WHERE c.user_id = $2
+AND c.deleted_at IS NULL
+AND can_view_chirps(c.user_id, $1)
 AND (
-    $2::uuid IS NULL OR 
+    $3::uuid IS NULL OR 
     (c.created_at, c.id) < (
-        SELECT created_at, id FROM chirps WHERE id = $2
+        SELECT created_at, id FROM chirps WHERE id = $3
     )
 )
 ORDER BY c.created_at DESC, c.id DESC
-LIMIT $3
+LIMIT $4
 `
 
 type GetUserChirpsPaginatedParams struct {
+	ViewerID  uuid.NullUUID `json:"viewer_id"`
 	UserID    uuid.UUID     `json:"user_id"`
 	Cursor    uuid.NullUUID `json:"cursor"`
 	PageLimit int32         `json:"page_limit"`
 }
 
-func (q *Queries) GetUserChirpsPaginated(ctx context.Context, arg GetUserChirpsPaginatedParams) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getUserChirpsPaginated, arg.UserID, arg.Cursor, arg.PageLimit)
+type GetUserChirpsPaginatedRow struct {
+	ID            uuid.UUID     `json:"id"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	EditCount     int32         `json:"edit_count"`
+	Edited        bool          `json:"edited"`
+	Body          string        `json:"body"`
+	UserID        uuid.UUID     `json:"user_id"`
+	ParentID      uuid.NullUUID `json:"parent_id"`
+	Kind          string        `json:"kind"`
+	OriginalID    uuid.NullUUID `json:"original_id"`
+	ReplyCount    int64         `json:"reply_count"`
+	LikeCount     int64         `json:"like_count"`
+	LikedByViewer bool          `json:"liked_by_viewer"`
+}
+
+func (q *Queries) GetUserChirpsPaginated(ctx context.Context, arg GetUserChirpsPaginatedParams) ([]GetUserChirpsPaginatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserChirpsPaginated,
+		arg.ViewerID,
+		arg.UserID,
+		arg.Cursor,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Chirp
+	var items []GetUserChirpsPaginatedRow
 	for rows.Next() {
-		var i Chirp
+		var i GetUserChirpsPaginatedRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EditCount,
+			&i.Edited,
 			&i.Body,
 			&i.UserID,
+			&i.ParentID,
+			&i.Kind,
+			&i.OriginalID,
+			&i.ReplyCount,
+			&i.LikeCount,
+			&i.LikedByViewer,
 		); err != nil {
 			return nil, err
 		}
@@ -172,6 +265,17 @@ func (q *Queries) GetUserChirpsPaginated(ctx context.Context, arg GetUserChirpsP
 	return items, nil
 }
 
+const getUserIsAdmin = `-- name: GetUserIsAdmin :one
+SELECT is_admin FROM users WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserIsAdmin(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, getUserIsAdmin, id)
+	var is_admin bool
+	err := row.Scan(&is_admin)
+	return is_admin, err
+}
+
 const getUserPassByEmail = `-- name: GetUserPassByEmail :one
 SELECT hashed_password FROM users WHERE email = $1
 `
@@ -183,29 +287,55 @@ func (q *Queries) GetUserPassByEmail(ctx context.Context, email string) (string,
 	return hashed_password, err
 }
 
+const getUserPrivacy = `-- name: GetUserPrivacy :one
+SELECT is_private FROM users WHERE id = $1
+`
+
+func (q *Queries) GetUserPrivacy(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, getUserPrivacy, id)
+	var is_private bool
+	err := row.Scan(&is_private)
+	return is_private, err
+}
+
 const getUserProfile = `-- name: GetUserProfile :one
 WITH user_stats AS (
     SELECT 
         u.id,
         u.email,
+        u.handle,
+        u.display_name,
+        u.bio,
+        u.location,
+        u.website,
+        u.avatar_key,
         u.created_at,
         u.updated_at,
         u.is_chirpy_red,
+        u.is_private,
         (SELECT COUNT(*) FROM follows WHERE followee_id = u.id) as followers_count,
         (SELECT COUNT(*) FROM follows WHERE follower_id = u.id) as following_count,
-        (SELECT COUNT(*) FROM chirps WHERE user_id = u.id) as chirps_count
+        (SELECT COUNT(*) FROM chirps WHERE user_id = u.id AND deleted_at IS NULL) as chirps_count
     FROM users u
     WHERE u.id = $1
+    AND u.deleted_at IS NULL
 )
-SELECT id, email, created_at, updated_at, is_chirpy_red, followers_count, following_count, chirps_count FROM user_stats
+SELECT * FROM user_stats
 `
 
 type GetUserProfileRow struct {
 	ID             uuid.UUID `json:"id"`
 	Email          string    `json:"email"`
+	Handle         string    `json:"handle"`
+	DisplayName    string    `json:"display_name"`
+	Bio            string    `json:"bio"`
+	Location       string    `json:"location"`
+	Website        string    `json:"website"`
+	AvatarKey      string    `json:"avatar_key"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 	IsChirpyRed    bool      `json:"is_chirpy_red"`
+	IsPrivate      bool      `json:"is_private"`
 	FollowersCount int64     `json:"followers_count"`
 	FollowingCount int64     `json:"following_count"`
 	ChirpsCount    int64     `json:"chirps_count"`
@@ -217,9 +347,16 @@ func (q *Queries) GetUserProfile(ctx context.Context, id uuid.UUID) (GetUserProf
 	err := row.Scan(
 		&i.ID,
 		&i.Email,
+		&i.Handle,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.Website,
+		&i.AvatarKey,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.IsChirpyRed,
+		&i.IsPrivate,
 		&i.FollowersCount,
 		&i.FollowingCount,
 		&i.ChirpsCount,
@@ -227,19 +364,117 @@ func (q *Queries) GetUserProfile(ctx context.Context, id uuid.UUID) (GetUserProf
 	return i, err
 }
 
+const getUserTokenVersion = `-- name: GetUserTokenVersion :one
+SELECT token_version FROM users WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserTokenVersion(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getUserTokenVersion, id)
+	var token_version int32
+	err := row.Scan(&token_version)
+	return token_version, err
+}
+
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+WITH purged AS (
+    SELECT id FROM users
+    WHERE deleted_at < NOW() - $1::int * INTERVAL '1 second'
+    FOR UPDATE
+),
+unfollowed AS (
+    UPDATE users u
+    SET follower_count = u.follower_count - f.total
+    FROM (
+        SELECT followee_id, COUNT(*) as total
+        FROM follows
+        WHERE follower_id IN (SELECT id FROM purged)
+        GROUP BY followee_id
+    ) f
+    WHERE u.id = f.followee_id
+      AND u.id NOT IN (SELECT id FROM purged)
+)
+DELETE FROM users
+WHERE id IN (SELECT id FROM purged)
+`
+
+// Hard-deletes accounts past their grace period. Their follows go with them
+// through ON DELETE CASCADE, so the cached follower counts of the accounts
+// they followed are decremented in the same statement.
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, retentionSeconds int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedUsers, retentionSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const scheduleUserDeletion = `-- name: ScheduleUserDeletion :one
+UPDATE users
+SET deleted_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING deleted_at
+`
+
+func (q *Queries) ScheduleUserDeletion(ctx context.Context, id uuid.UUID) (sql.NullTime, error) {
+	row := q.db.QueryRowContext(ctx, scheduleUserDeletion, id)
+	var deleted_at sql.NullTime
+	err := row.Scan(&deleted_at)
+	return deleted_at, err
+}
+
+const setUserAvatar = `-- name: SetUserAvatar :one
+UPDATE users u
+SET updated_at = NOW(),
+    avatar_key = $1
+FROM (SELECT id, avatar_key FROM users WHERE id = $2) prev
+WHERE u.id = prev.id
+RETURNING prev.avatar_key AS previous_avatar_key
+`
+
+type SetUserAvatarParams struct {
+	AvatarKey string    `json:"avatar_key"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, setUserAvatar, arg.AvatarKey, arg.ID)
+	var previous_avatar_key string
+	err := row.Scan(&previous_avatar_key)
+	return previous_avatar_key, err
+}
+
+const setUserPrivacy = `-- name: SetUserPrivacy :exec
+UPDATE users
+SET is_private = $1,
+    updated_at = NOW()
+WHERE id = $2
+`
+
+type SetUserPrivacyParams struct {
+	IsPrivate bool      `json:"is_private"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetUserPrivacy(ctx context.Context, arg SetUserPrivacyParams) error {
+	_, err := q.db.ExecContext(ctx, setUserPrivacy, arg.IsPrivate, arg.ID)
+	return err
+}
+
 const updateUserCred = `-- name: UpdateUserCred :one
 UPDATE users
 SET updated_at = NOW(),
     email = $1,
-    hashed_password = $2
-WHERE id = $3
-RETURNING id, created_at, updated_at, email, is_chirpy_red
+    hashed_password = $2,
+    handle = COALESCE($3, handle)
+WHERE id = $4
+RETURNING id, created_at, updated_at, email, is_chirpy_red, handle
 `
 
 type UpdateUserCredParams struct {
-	Email          string    `json:"email"`
-	HashedPassword string    `json:"hashed_password"`
-	ID             uuid.UUID `json:"id"`
+	Email          string         `json:"email"`
+	HashedPassword string         `json:"hashed_password"`
+	Handle         sql.NullString `json:"handle"`
+	ID             uuid.UUID      `json:"id"`
 }
 
 type UpdateUserCredRow struct {
@@ -248,10 +483,16 @@ type UpdateUserCredRow struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	Email       string    `json:"email"`
 	IsChirpyRed bool      `json:"is_chirpy_red"`
+	Handle      string    `json:"handle"`
 }
 
 func (q *Queries) UpdateUserCred(ctx context.Context, arg UpdateUserCredParams) (UpdateUserCredRow, error) {
-	row := q.db.QueryRowContext(ctx, updateUserCred, arg.Email, arg.HashedPassword, arg.ID)
+	row := q.db.QueryRowContext(ctx, updateUserCred,
+		arg.Email,
+		arg.HashedPassword,
+		arg.Handle,
+		arg.ID,
+	)
 	var i UpdateUserCredRow
 	err := row.Scan(
 		&i.ID,
@@ -259,10 +500,40 @@ func (q *Queries) UpdateUserCred(ctx context.Context, arg UpdateUserCredParams)
 		&i.UpdatedAt,
 		&i.Email,
 		&i.IsChirpyRed,
+		&i.Handle,
 	)
 	return i, err
 }
 
+const updateUserProfile = `-- name: UpdateUserProfile :exec
+UPDATE users
+SET updated_at = NOW(),
+    display_name = COALESCE($1, display_name),
+    bio = COALESCE($2, bio),
+    location = COALESCE($3, location),
+    website = COALESCE($4, website)
+WHERE id = $5
+`
+
+type UpdateUserProfileParams struct {
+	DisplayName sql.NullString `json:"display_name"`
+	Bio         sql.NullString `json:"bio"`
+	Location    sql.NullString `json:"location"`
+	Website     sql.NullString `json:"website"`
+	ID          uuid.UUID      `json:"id"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserProfile,
+		arg.DisplayName,
+		arg.Bio,
+		arg.Location,
+		arg.Website,
+		arg.ID,
+	)
+	return err
+}
+
 const updateUserToChirpyRed = `-- name: UpdateUserToChirpyRed :exec
 UPDATE users
 SET is_chirpy_red = TRUE,
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
 )
 
 const metricBody = `
@@ -89,3 +92,64 @@ func (h *AdminHandler) Reset(w http.ResponseWriter, r *http.Request) {
 		log.Printf("[%s] Could not write to reset endpoint: %s", h.apiCfg.Platform, err)
 	}
 }
+
+const defaultFlagsLimit = 20
+const maxFlagsLimit = 100
+
+// GetFlaggedChirps lists chirps the content filter queued for review,
+// newest first. The route is restricted to admins.
+func (h *AdminHandler) GetFlaggedChirps(w http.ResponseWriter, r *http.Request) {
+	limit, cursor := parsePageParams(r, defaultFlagsLimit, maxFlagsLimit)
+
+	flags, err := h.apiCfg.DB.GetFlaggedChirps(r.Context(), database.GetFlaggedChirpsParams{
+		Cursor:    cursor,
+		PageLimit: limit,
+	})
+	if err != nil {
+		log.Printf("Error fetching flagged chirps: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch flagged chirps"})
+		return
+	}
+
+	var nextCursor *string
+	if len(flags) == int(limit) {
+		lastID := flags[len(flags)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	if flags == nil {
+		flags = []database.GetFlaggedChirpsRow{}
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		Chirps     []database.GetFlaggedChirpsRow `json:"chirps"`
+		NextCursor *string                        `json:"next_cursor,omitempty"`
+	}{
+		Chirps:     flags,
+		NextCursor: nextCursor,
+	})
+}
+
+// ClearChirpFlag removes a chirp from the review queue once it has been
+// looked at.
+func (h *AdminHandler) ClearChirpFlag(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
+		return
+	}
+
+	cleared, err := h.apiCfg.DB.ClearChirpFlag(r.Context(), chirpID)
+	if err != nil {
+		log.Printf("Error clearing chirp flag: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to clear flag"})
+		return
+	}
+
+	if cleared == 0 {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp is not flagged"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
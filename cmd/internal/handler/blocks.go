@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const defaultBlockListLimit = 20
+const maxBlockListLimit = 100
+
+// ListedUser is an entry in the caller's block or mute list.
+type ListedUser struct {
+	ID          uuid.UUID `json:"id"`
+	Handle      string    `json:"handle"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
+	Since       time.Time `json:"since"`
+}
+
+// decodeTargetUser reads the {"user_id": ...} body shared by the block and
+// mute endpoints and rejects targeting oneself.
+func decodeTargetUser(w http.ResponseWriter, r *http.Request, callerID uuid.UUID) (uuid.UUID, bool) {
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid Request"})
+		return uuid.UUID{}, false
+	}
+
+	targetID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID format"})
+		return uuid.UUID{}, false
+	}
+
+	if targetID == callerID {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Cannot target self"})
+		return uuid.UUID{}, false
+	}
+
+	return targetID, true
+}
+
+func respondUserList(w http.ResponseWriter, users []ListedUser, limit int32) {
+	var nextCursor *string
+	if len(users) == int(limit) {
+		lastID := users[len(users)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		Users      []ListedUser `json:"users"`
+		NextCursor *string      `json:"next_cursor,omitempty"`
+	}{
+		Users:      users,
+		NextCursor: nextCursor,
+	})
+}
+
+func (h *APIHandler) listedUser(id uuid.UUID, handle, displayName, avatarKey string, since time.Time) ListedUser {
+	user := ListedUser{
+		ID:          id,
+		Handle:      handle,
+		DisplayName: displayName,
+		Since:       since,
+	}
+	if avatarKey != "" {
+		user.AvatarURL = h.cfg.Blobs.URL(avatarKey)
+	}
+	return user
+}
+
+// BlockUser blocks another account. Follows in both directions and pending
+// follow requests are removed, and neither side sees the other's chirps.
+func (h *APIHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	targetID, ok := decodeTargetUser(w, r, userID)
+	if !ok {
+		return
+	}
+
+	if _, err := h.cfg.DB.GetUserPrivacy(r.Context(), targetID); err != nil {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "User not found"})
+		return
+	}
+
+	err := h.cfg.DB.BlockUser(r.Context(), database.BlockUserParams{
+		BlockerID: userID,
+		BlockedID: targetID,
+	})
+	if err != nil {
+		log.Printf("Error blocking user: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to block user"})
+		return
+	}
+
+	h.cfg.Timeline.Prune(userID, targetID)
+	h.cfg.Timeline.Prune(targetID, userID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	targetID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID format"})
+		return
+	}
+
+	removed, err := h.cfg.DB.UnblockUser(r.Context(), database.UnblockUserParams{
+		BlockerID: userID,
+		BlockedID: targetID,
+	})
+	if err != nil {
+		log.Printf("Error unblocking user: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to unblock user"})
+		return
+	}
+
+	if removed == 0 {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "User is not blocked"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, cursor := parsePageParams(r, defaultBlockListLimit, maxBlockListLimit)
+
+	blocked, err := h.cfg.DB.GetBlockedUsers(r.Context(), database.GetBlockedUsersParams{
+		BlockerID: userID,
+		Cursor:    cursor,
+		PageLimit: limit,
+	})
+	if err != nil {
+		log.Printf("Error fetching blocked users: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch blocked users"})
+		return
+	}
+
+	users := make([]ListedUser, len(blocked))
+	for i, b := range blocked {
+		users[i] = h.listedUser(b.ID, b.Handle, b.DisplayName, b.AvatarKey, b.BlockedAt)
+	}
+
+	respondUserList(w, users, limit)
+}
+
+// MuteUser hides another account's chirps from the caller's feed and its
+// activity from their notifications. Unlike a block it is one-sided and
+// invisible to the muted account.
+func (h *APIHandler) MuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	targetID, ok := decodeTargetUser(w, r, userID)
+	if !ok {
+		return
+	}
+
+	if _, err := h.cfg.DB.GetUserPrivacy(r.Context(), targetID); err != nil {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "User not found"})
+		return
+	}
+
+	err := h.cfg.DB.MuteUser(r.Context(), database.MuteUserParams{
+		MuterID: userID,
+		MutedID: targetID,
+	})
+	if err != nil {
+		log.Printf("Error muting user: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to mute user"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) UnmuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	targetID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID format"})
+		return
+	}
+
+	removed, err := h.cfg.DB.UnmuteUser(r.Context(), database.UnmuteUserParams{
+		MuterID: userID,
+		MutedID: targetID,
+	})
+	if err != nil {
+		log.Printf("Error unmuting user: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to unmute user"})
+		return
+	}
+
+	if removed == 0 {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "User is not muted"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) GetMutedUsers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, cursor := parsePageParams(r, defaultBlockListLimit, maxBlockListLimit)
+
+	muted, err := h.cfg.DB.GetMutedUsers(r.Context(), database.GetMutedUsersParams{
+		MuterID:   userID,
+		Cursor:    cursor,
+		PageLimit: limit,
+	})
+	if err != nil {
+		log.Printf("Error fetching muted users: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch muted users"})
+		return
+	}
+
+	users := make([]ListedUser, len(muted))
+	for i, m := range muted {
+		users[i] = h.listedUser(m.ID, m.Handle, m.DisplayName, m.AvatarKey, m.MutedAt)
+	}
+
+	respondUserList(w, users, limit)
+}
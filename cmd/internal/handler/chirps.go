@@ -1,355 +1,599 @@
-package handler
-
-import (
-	"encoding/json"
-	"log"
-	"net/http"
-	"slices"
-	"sort"
-	"strings"
-	"unicode/utf8"
-
-	"github.com/google/uuid"
-	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
-	"github.com/shubh-man007/Chirpy/cmd/internal/database"
-)
-
-const (
-	maxChirpLength = 140
-	asterisk       = "****"
-)
-
-var profane = []string{"kerfuffle", "sharbert", "fornax"}
-
-func (h *APIHandler) CreateChirp(w http.ResponseWriter, r *http.Request) {
-	chirp := ChirpBody{}
-	err := json.NewDecoder(r.Body).Decode(&chirp)
-	if err != nil {
-		log.Printf("Error decoding requested JSON: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-
-	sentJWTToken, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		log.Printf("Invalid Token: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	userID, err := auth.ValidateJWT(sentJWTToken, h.cfg.JWTSecret)
-	if err != nil {
-		log.Printf("Invalid Token: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Invalid Token",
-		})
-		return
-	}
-
-	if utf8.RuneCountInString(chirp.Body) > maxChirpLength {
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Chirp too long",
-		})
-		return
-	}
-
-	cleanChirpBody := cleanProfanity(chirp.Body)
-
-	valChirp, err := h.cfg.DB.CreateChirp(r.Context(), database.CreateChirpParams{
-		Body:   cleanChirpBody,
-		UserID: userID,
-	})
-
-	if err != nil {
-		log.Printf("Error creating chirp: %v", err)
-		http.Error(w, "Couldn't chirp", http.StatusInternalServerError)
-		return
-	}
-
-	respondJSON(w, http.StatusCreated, valChirp)
-}
-
-func (h *APIHandler) GetAllChirps(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-	sortOrder := query.Get("sort")
-
-	if sortOrder == "" {
-		sortOrder = "asc"
-	}
-
-	chirps, err := h.cfg.DB.GetAllChirps(r.Context())
-	if err != nil {
-		log.Printf("Error fetching chirps: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	sort.Slice(chirps, func(i, j int) bool {
-		if sortOrder == "desc" {
-			return chirps[i].CreatedAt.After(chirps[j].CreatedAt)
-		}
-
-		// default: asc
-		return chirps[i].CreatedAt.Before(chirps[j].CreatedAt)
-	})
-
-	respondJSON(w, http.StatusOK, chirps)
-}
-
-func (h *APIHandler) GetMyChirps(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-	sortOrder := query.Get("sort")
-
-	if sortOrder == "" {
-		sortOrder = "asc"
-	}
-
-	token, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		log.Printf("Access token absent: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	userID, err := auth.ValidateJWT(token, h.cfg.JWTSecret)
-	if err != nil {
-		log.Printf("Invalid access token: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	chirps, err := h.cfg.DB.GetChirpsByUser(r.Context(), userID)
-	if err != nil {
-		log.Printf("Error fetching chirps: %v", err)
-		http.Error(w, "Something went wrong", http.StatusNotFound)
-		return
-	}
-
-	sort.Slice(chirps, func(i, j int) bool {
-		if sortOrder == "desc" {
-			return chirps[i].CreatedAt.After(chirps[j].CreatedAt)
-		}
-		// default: asc
-		return chirps[i].CreatedAt.Before(chirps[j].CreatedAt)
-	})
-
-	respondJSON(w, http.StatusOK, chirps)
-}
-
-func (h *APIHandler) GetChirpsByUser(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
-	sortOrder := query.Get("sort")
-
-	if sortOrder == "" {
-		sortOrder = "asc"
-	}
-
-	userID, err := uuid.Parse(r.PathValue("userID"))
-	if err != nil {
-		log.Printf("Could not parse user ID: %v", err)
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-
-	chirps, err := h.cfg.DB.GetChirpsByUser(r.Context(), userID)
-	if err != nil {
-		log.Printf("Error fetching chirps: %v", err)
-		http.Error(w, "Something went wrong", http.StatusNotFound)
-		return
-	}
-
-	sort.Slice(chirps, func(i, j int) bool {
-		if sortOrder == "desc" {
-			return chirps[i].CreatedAt.After(chirps[j].CreatedAt)
-		}
-		// default: asc
-		return chirps[i].CreatedAt.Before(chirps[j].CreatedAt)
-	})
-
-	respondJSON(w, http.StatusOK, chirps)
-}
-
-func (h *APIHandler) GetChirpByChirpID(w http.ResponseWriter, r *http.Request) {
-	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
-	if err != nil {
-		log.Printf("Could not parse chirp ID: %v", err)
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-	chirp, err := h.cfg.DB.GetChirp(r.Context(), chirpID)
-	if err != nil {
-		log.Printf("Error fetching chirps: %v", err)
-		http.Error(w, "Something went wrong", http.StatusNotFound)
-		return
-	}
-
-	respondJSON(w, http.StatusOK, chirp)
-}
-
-func (h *APIHandler) UpdateChirp(w http.ResponseWriter, r *http.Request) {
-	chirpIDStr := r.PathValue("chirpID")
-	chirpID, err := uuid.Parse(chirpIDStr)
-	if err != nil {
-		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
-		return
-	}
-
-	accessToken, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		log.Printf("Access token absent: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	userID, err := auth.ValidateJWT(accessToken, h.cfg.JWTSecret)
-	if err != nil {
-		log.Printf("Invalid access token: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	user, err := h.cfg.DB.GetUserByID(r.Context(), userID)
-	if err != nil {
-		log.Printf("Error fetching user: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	if !user.IsChirpyRed {
-		log.Print("User not allowed to edit chirp")
-		errJSON(w, http.StatusForbidden, ErrMessage{
-			Message: "Only Chirpy Red members can edit chirps",
-		})
-		return
-	}
-
-	diff := ChirpBody{}
-	err = json.NewDecoder(r.Body).Decode(&diff)
-	if err != nil {
-		log.Printf("Error decoding requested JSON: %v", err)
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-
-	existing, err := h.cfg.DB.GetChirp(r.Context(), chirpID)
-	if err != nil {
-		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp not found"})
-		return
-	}
-
-	if existing.UserID != userID {
-		errJSON(w, http.StatusForbidden, ErrMessage{
-			Message: "Not allowed to edit this chirp",
-		})
-		return
-	}
-
-	updatedChirp, err := h.cfg.DB.UpdateChirpBody(r.Context(), database.UpdateChirpBodyParams{
-		Body: diff.Body,
-		ID:   chirpID,
-	})
-	if err != nil {
-		log.Printf("Error updating chirp: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	respondJSON(w, http.StatusOK, updatedChirp)
-}
-
-func (h *APIHandler) DeleteChirp(w http.ResponseWriter, r *http.Request) {
-	accessToken, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		log.Printf("Access token absent: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	userID, err := auth.ValidateJWT(accessToken, h.cfg.JWTSecret)
-	if err != nil {
-		log.Printf("Invalid access token: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
-	if err != nil {
-		log.Printf("Could not parse chirp ID: %v", err)
-		errJSON(w, http.StatusNotFound, ErrMessage{
-			Message: "Chirp not found",
-		})
-		return
-	}
-
-	chirp, err := h.cfg.DB.GetChirp(r.Context(), chirpID)
-	if err != nil {
-		log.Printf("Error fetching chirp: %v", err)
-		http.Error(w, "Something went wrong", http.StatusNotFound)
-		return
-	}
-
-	if chirp.UserID != userID {
-		log.Print("Unauthorized user cannot delete chirp")
-		http.Error(w, "Unauthorized User", http.StatusForbidden)
-		return
-	}
-
-	err = h.cfg.DB.DeleteChirp(r.Context(), chirpID)
-	if err != nil {
-		log.Printf("Error deleting chirp: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-	w.WriteHeader(http.StatusNoContent)
-}
-
-// utility:
-func cleanProfanity(text string) string {
-	words := strings.Split(text, " ")
-	for i, word := range words {
-		var leading strings.Builder
-		trailing := ""
-		core := word
-
-		for len(core) > 0 && isPunctuation(rune(core[0])) {
-			leading.WriteString(string(core[0]))
-			core = core[1:]
-		}
-
-		for len(core) > 0 && isPunctuation(rune(core[len(core)-1])) {
-			trailing = string(core[len(core)-1]) + trailing
-			core = core[:len(core)-1]
-		}
-
-		if slices.Contains(profane, strings.ToLower(core)) {
-			words[i] = leading.String() + asterisk + trailing
-		}
-	}
-	return strings.Join(words, " ")
-}
-
-func isPunctuation(r rune) bool {
-	return strings.ContainsRune(".,!?;:'\"", r)
-}
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+	"github.com/shubh-man007/Chirpy/cmd/internal/moderation"
+)
+
+const maxChirpLength = 140
+
+const (
+	chirpKindChirp   = "chirp"
+	chirpKindRechirp = "rechirp"
+	chirpKindQuote   = "quote"
+)
+
+const defaultChirpsLimit = 20
+const maxChirpsLimit = 100
+
+const defaultThreadRepliesLimit = 20
+const maxThreadRepliesLimit = 100
+
+func (h *APIHandler) CreateChirp(w http.ResponseWriter, r *http.Request) {
+	chirp := ChirpBody{}
+	err := json.NewDecoder(r.Body).Decode(&chirp)
+	if err != nil {
+		log.Printf("Error decoding requested JSON: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	filtered, ok := h.moderateChirpBody(w, chirp.Body)
+	if !ok {
+		return
+	}
+
+	var parentID, rootID, parentAuthor uuid.NullUUID
+	if chirp.InReplyTo != "" {
+		inReplyTo, err := uuid.Parse(chirp.InReplyTo)
+		if err != nil {
+			errJSON(w, http.StatusBadRequest, ErrMessage{
+				Message: "Invalid in_reply_to ID",
+			})
+			return
+		}
+
+		parent, err := h.getOriginalChirp(r.Context(), inReplyTo, userID)
+		if err != nil {
+			log.Printf("Error fetching parent chirp: %v", err)
+			errJSON(w, http.StatusNotFound, ErrMessage{
+				Message: "Chirp being replied to not found",
+			})
+			return
+		}
+
+		parentID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+		parentAuthor = uuid.NullUUID{UUID: parent.UserID, Valid: true}
+		rootID = parent.RootID
+		if !rootID.Valid {
+			rootID = parentID
+		}
+	}
+
+	kind := chirpKindChirp
+	var originalID, originalAuthor uuid.NullUUID
+	if chirp.QuoteOf != "" {
+		quoteOf, err := uuid.Parse(chirp.QuoteOf)
+		if err != nil {
+			errJSON(w, http.StatusBadRequest, ErrMessage{
+				Message: "Invalid quote_of ID",
+			})
+			return
+		}
+
+		if strings.TrimSpace(chirp.Body) == "" {
+			errJSON(w, http.StatusBadRequest, ErrMessage{
+				Message: "Quote chirps need a comment",
+			})
+			return
+		}
+
+		original, err := h.getOriginalChirp(r.Context(), quoteOf, userID)
+		if err != nil {
+			log.Printf("Error fetching quoted chirp: %v", err)
+			errJSON(w, http.StatusNotFound, ErrMessage{
+				Message: "Chirp being quoted not found",
+			})
+			return
+		}
+
+		kind = chirpKindQuote
+		originalID = uuid.NullUUID{UUID: original.ID, Valid: true}
+		originalAuthor = uuid.NullUUID{UUID: original.UserID, Valid: true}
+	}
+
+	valChirp, err := h.cfg.DB.CreateChirp(r.Context(), database.CreateChirpParams{
+		Body:       filtered.Text,
+		UserID:     userID,
+		ParentID:   parentID,
+		RootID:     rootID,
+		Kind:       kind,
+		OriginalID: originalID,
+	})
+
+	if err != nil {
+		log.Printf("Error creating chirp: %v", err)
+		http.Error(w, "Couldn't chirp", http.StatusInternalServerError)
+		return
+	}
+
+	h.flagForReview(r.Context(), valChirp.ID, filtered)
+	h.syncHashtags(r.Context(), valChirp.ID, valChirp.Body)
+	h.syncMentions(r.Context(), valChirp.ID, valChirp.UserID, valChirp.Body)
+	h.cfg.Timeline.FanOut(valChirp.ID)
+
+	if parentAuthor.Valid {
+		h.notify(r.Context(), parentAuthor.UUID, userID, notificationReply, parentID)
+	}
+	if originalAuthor.Valid {
+		h.notify(r.Context(), originalAuthor.UUID, userID, notificationQuote, originalID)
+	}
+
+	resp := ChirpResponse{
+		Chirp:    valChirp,
+		Entities: h.chirpEntities(r.Context(), valChirp.ID, valChirp.Body),
+	}
+	content := streamContent{authorID: userID, body: valChirp.Body}
+	if originalAuthor.Valid {
+		content.related = []uuid.UUID{originalAuthor.UUID}
+	}
+	h.publishChirpEvent(r.Context(), streamChirpCreated, userID, resp, content)
+
+	respondJSON(w, http.StatusCreated, resp)
+}
+
+// GetAllChirps is the public timeline. It can be narrowed to one author with
+// the author parameter (a user ID or handle).
+func (h *APIHandler) GetAllChirps(w http.ResponseWriter, r *http.Request) {
+	var authorID uuid.NullUUID
+	if author := r.URL.Query().Get("author"); author != "" {
+		id, err := h.resolveAuthor(r.Context(), author)
+		if err != nil {
+			errJSON(w, http.StatusNotFound, ErrMessage{Message: "Author not found"})
+			return
+		}
+		authorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	h.listChirps(w, r, authorID)
+}
+
+func (h *APIHandler) GetMyChirps(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	h.listChirps(w, r, uuid.NullUUID{UUID: userID, Valid: true})
+}
+
+func (h *APIHandler) GetChirpsByUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		log.Printf("Could not parse user ID: %v", err)
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	h.listChirps(w, r, uuid.NullUUID{UUID: userID, Valid: true})
+}
+
+// listChirps serves the chirp list endpoints. Sorting, the since/until
+// window and the cursor are all applied in SQL; sort is "asc" (default) or
+// "desc" by creation time, each with its own query so the keyset can walk
+// idx_chirps_created_id.
+func (h *APIHandler) listChirps(w http.ResponseWriter, r *http.Request, authorID uuid.NullUUID) {
+	query := r.URL.Query()
+
+	sortOrder := query.Get("sort")
+	if sortOrder == "" {
+		sortOrder = "asc"
+	}
+	if sortOrder != "asc" && sortOrder != "desc" {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid sort order"})
+		return
+	}
+
+	limit := parsePageLimit(r, defaultChirpsLimit, maxChirpsLimit)
+	cursor, err := parseCursorParam(r, "cursor")
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid cursor"})
+		return
+	}
+	viewerID := h.optionalViewerID(r)
+
+	params := database.ListChirpsAscParams{
+		ViewerID:  nullViewerID(viewerID),
+		AuthorID:  authorID,
+		PageLimit: limit,
+	}
+	params.CursorAt, params.CursorID = cursorArgs(cursor)
+
+	if since := query.Get("since"); since != "" {
+		t, err := parseSearchTime(since)
+		if err != nil {
+			errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid since date"})
+			return
+		}
+		params.Since.Time, params.Since.Valid = t, true
+	}
+
+	if until := query.Get("until"); until != "" {
+		t, err := parseSearchTime(until)
+		if err != nil {
+			errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid until date"})
+			return
+		}
+		params.Until.Time, params.Until.Valid = t, true
+	}
+
+	var chirps []database.ListChirpsAscRow
+	if sortOrder == "asc" {
+		chirps, err = h.cfg.DB.ListChirpsAsc(r.Context(), params)
+	} else {
+		var desc []database.ListChirpsDescRow
+		desc, err = h.cfg.DB.ListChirpsDesc(r.Context(), database.ListChirpsDescParams(params))
+		for _, c := range desc {
+			chirps = append(chirps, database.ListChirpsAscRow(c))
+		}
+	}
+	if err != nil {
+		log.Printf("Error fetching chirps: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch chirps"})
+		return
+	}
+
+	ids := make([]uuid.UUID, len(chirps))
+	for i, c := range chirps {
+		ids[i] = c.ID
+	}
+	resolved := h.resolvedMentions(r.Context(), ids)
+
+	type ListedChirp struct {
+		database.ListChirpsAscRow
+		FilterMatch
+		Entities ChirpEntities `json:"entities"`
+	}
+
+	filters := h.wordFiltersFor(r.Context(), viewerID)
+	items := make([]ListedChirp, 0, len(chirps))
+	for _, c := range chirps {
+		match, keep := filters.apply(c.UserID, c.Body)
+		if !keep {
+			continue
+		}
+		items = append(items, ListedChirp{
+			ListChirpsAscRow: c,
+			FilterMatch:      match,
+			Entities:         buildChirpEntities(c.Body, resolved[c.ID]),
+		})
+	}
+
+	var nextCursor *string
+	if len(chirps) == int(limit) {
+		last := chirps[len(chirps)-1]
+		next := pageCursor{At: last.CreatedAt, ID: last.ID}.String()
+		nextCursor = &next
+	}
+
+	type ChirpsResponse struct {
+		Chirps     []ListedChirp `json:"chirps"`
+		NextCursor *string       `json:"next_cursor,omitempty"`
+	}
+
+	respondJSON(w, http.StatusOK, ChirpsResponse{
+		Chirps:     items,
+		NextCursor: nextCursor,
+	})
+}
+
+func (h *APIHandler) GetChirpByChirpID(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("Could not parse chirp ID: %v", err)
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+	chirp, err := h.cfg.DB.GetChirpWithStats(r.Context(), database.GetChirpWithStatsParams{
+		ViewerID: nullViewerID(h.optionalViewerID(r)),
+		ID:       chirpID,
+	})
+	if err != nil {
+		log.Printf("Error fetching chirps: %v", err)
+		http.Error(w, "Something went wrong", http.StatusNotFound)
+		return
+	}
+
+	type ChirpWithStatsResponse struct {
+		database.GetChirpWithStatsRow
+		Entities ChirpEntities `json:"entities"`
+	}
+
+	respondJSON(w, http.StatusOK, ChirpWithStatsResponse{
+		GetChirpWithStatsRow: chirp,
+		Entities:             h.chirpEntities(r.Context(), chirp.ID, chirp.Body),
+	})
+}
+
+func (h *APIHandler) GetChirpThread(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
+		return
+	}
+
+	limit, cursor := parsePageParams(r, defaultThreadRepliesLimit, maxThreadRepliesLimit)
+	viewer := h.optionalViewerID(r)
+	viewerID := nullViewerID(viewer)
+
+	chirp, err := h.getVisibleChirp(r.Context(), chirpID, viewer)
+	if err != nil {
+		log.Printf("Error fetching chirp: %v", err)
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp not found"})
+		return
+	}
+
+	ancestors, err := h.cfg.DB.GetChirpAncestors(r.Context(), database.GetChirpAncestorsParams{
+		ChirpID:  chirpID,
+		ViewerID: viewerID,
+	})
+	if err != nil {
+		log.Printf("Error fetching ancestors: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch thread"})
+		return
+	}
+
+	replies, err := h.cfg.DB.GetChirpDescendants(r.Context(), database.GetChirpDescendantsParams{
+		ChirpID:   chirpID,
+		ViewerID:  viewerID,
+		Cursor:    cursor,
+		PageLimit: limit,
+	})
+	if err != nil {
+		log.Printf("Error fetching replies: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch thread"})
+		return
+	}
+
+	if ancestors == nil {
+		ancestors = []database.GetChirpAncestorsRow{}
+	}
+	if replies == nil {
+		replies = []database.GetChirpDescendantsRow{}
+	}
+
+	var nextCursor *string
+	if len(replies) == int(limit) {
+		lastID := replies[len(replies)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	type ThreadResponse struct {
+		Chirp      database.Chirp                    `json:"chirp"`
+		Ancestors  []database.GetChirpAncestorsRow   `json:"ancestors"`
+		Replies    []database.GetChirpDescendantsRow `json:"replies"`
+		NextCursor *string                           `json:"next_cursor,omitempty"`
+	}
+
+	respondJSON(w, http.StatusOK, ThreadResponse{
+		Chirp:      chirp,
+		Ancestors:  ancestors,
+		Replies:    replies,
+		NextCursor: nextCursor,
+	})
+}
+
+// UpdateChirp replaces a chirp's body. The previous body is kept as a
+// revision, and edits are refused once the edit window has passed.
+func (h *APIHandler) UpdateChirp(w http.ResponseWriter, r *http.Request) {
+	chirpIDStr := r.PathValue("chirpID")
+	chirpID, err := uuid.Parse(chirpIDStr)
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.cfg.DB.GetUserByID(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching user: %v", err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if !user.IsChirpyRed {
+		log.Print("User not allowed to edit chirp")
+		errJSON(w, http.StatusForbidden, ErrMessage{
+			Message: "Only Chirpy Red members can edit chirps",
+		})
+		return
+	}
+
+	diff := ChirpBody{}
+	err = json.NewDecoder(r.Body).Decode(&diff)
+	if err != nil {
+		log.Printf("Error decoding requested JSON: %v", err)
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+
+	existing, err := h.cfg.DB.GetChirp(r.Context(), chirpID)
+	if err != nil {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp not found"})
+		return
+	}
+
+	if existing.UserID != userID {
+		errJSON(w, http.StatusForbidden, ErrMessage{
+			Message: "Not allowed to edit this chirp",
+		})
+		return
+	}
+
+	if existing.Kind == chirpKindRechirp {
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Rechirps cannot be edited",
+		})
+		return
+	}
+
+	filtered, ok := h.moderateChirpBody(w, diff.Body)
+	if !ok {
+		return
+	}
+
+	updatedChirp, err := h.cfg.DB.UpdateChirpBody(r.Context(), database.UpdateChirpBodyParams{
+		ID:                chirpID,
+		EditWindowSeconds: editWindowSeconds(h.cfg.EditWindow),
+		Body:              filtered.Text,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		errJSON(w, http.StatusForbidden, ErrMessage{
+			Message: "Chirps can only be edited for " + h.cfg.EditWindow.String() + " after posting",
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("Error updating chirp: %v", err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	h.flagForReview(r.Context(), updatedChirp.ID, filtered)
+	h.syncHashtags(r.Context(), updatedChirp.ID, updatedChirp.Body)
+	h.syncMentions(r.Context(), updatedChirp.ID, updatedChirp.UserID, updatedChirp.Body)
+
+	resp := ChirpResponse{
+		Chirp:    updatedChirp,
+		Entities: h.chirpEntities(r.Context(), updatedChirp.ID, updatedChirp.Body),
+	}
+	h.publishChirpEvent(r.Context(), streamChirpUpdated, updatedChirp.UserID, resp, streamContent{
+		authorID: updatedChirp.UserID,
+		body:     updatedChirp.Body,
+	})
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// DeleteChirp moves a chirp to its author's trash, from where it can be
+// restored until it is purged.
+// editWindowSeconds converts the edit window for UpdateChirpBody, capping it
+// at what the int32 parameter can hold.
+func editWindowSeconds(window time.Duration) int32 {
+	return int32(min(window/time.Second, math.MaxInt32))
+}
+
+func (h *APIHandler) DeleteChirp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		log.Printf("Could not parse chirp ID: %v", err)
+		errJSON(w, http.StatusNotFound, ErrMessage{
+			Message: "Chirp not found",
+		})
+		return
+	}
+
+	chirp, err := h.cfg.DB.GetChirp(r.Context(), chirpID)
+	if err != nil {
+		log.Printf("Error fetching chirp: %v", err)
+		http.Error(w, "Something went wrong", http.StatusNotFound)
+		return
+	}
+
+	if chirp.UserID != userID {
+		log.Print("Unauthorized user cannot delete chirp")
+		http.Error(w, "Unauthorized User", http.StatusForbidden)
+		return
+	}
+
+	// Rechirps have nothing worth restoring, so only they are removed for
+	// good; everything else goes to the author's trash.
+	if chirp.Kind == chirpKindRechirp {
+		err = h.cfg.DB.DeleteChirp(r.Context(), chirpID)
+	} else {
+		_, err = h.cfg.DB.SoftDeleteChirp(r.Context(), chirpID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Error deleting chirp: %v", err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	h.publishChirpEvent(r.Context(), streamChirpDeleted, chirp.UserID, struct {
+		ID     uuid.UUID `json:"id"`
+		UserID uuid.UUID `json:"user_id"`
+	}{
+		ID:     chirp.ID,
+		UserID: chirp.UserID,
+	}, streamContent{})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// utility:
+
+// moderateChirpBody checks a new or edited chirp body against the length
+// limit and the content filter. It writes the error response and returns
+// false when the body is not allowed.
+func (h *APIHandler) moderateChirpBody(w http.ResponseWriter, body string) (moderation.Result, bool) {
+	if utf8.RuneCountInString(body) > maxChirpLength {
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Chirp too long",
+		})
+		return moderation.Result{}, false
+	}
+
+	result := h.cfg.Filter.Check(body)
+	if result.Rejected {
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Chirp contains a blocked word",
+		})
+		return moderation.Result{}, false
+	}
+
+	return result, true
+}
+
+// flagForReview queues a saved chirp for moderator review when it matched
+// a flagged word. Failures are logged; the chirp itself is already saved.
+func (h *APIHandler) flagForReview(ctx context.Context, chirpID uuid.UUID, result moderation.Result) {
+	if len(result.Flagged) == 0 {
+		return
+	}
+
+	err := h.cfg.DB.FlagChirpForReview(ctx, database.FlagChirpForReviewParams{
+		ChirpID: chirpID,
+		Words:   result.Flagged,
+	})
+	if err != nil {
+		log.Printf("Error flagging chirp for review: %v", err)
+	}
+}
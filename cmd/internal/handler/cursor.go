@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor is a keyset position: the sort time and ID of the item a page
+// ended on. Carrying the position itself, instead of an ID to look up,
+// keeps paging working after that item is deleted.
+type pageCursor struct {
+	At time.Time
+	ID uuid.UUID
+}
+
+// String encodes the cursor as the opaque token handed to clients.
+func (c pageCursor) String() string {
+	raw := c.At.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func parsePageCursor(token string) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return pageCursor{}, errInvalidCursor
+	}
+
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return pageCursor{}, errInvalidCursor
+	}
+
+	var c pageCursor
+	if c.At, err = time.Parse(time.RFC3339Nano, at); err != nil {
+		return pageCursor{}, errInvalidCursor
+	}
+	if c.ID, err = uuid.Parse(id); err != nil {
+		return pageCursor{}, errInvalidCursor
+	}
+	return c, nil
+}
+
+// parseCursorParam reads an optional position cursor from the named query
+// parameter. Unlike ID cursors, a malformed one is an error rather than
+// silently restarting from the first page.
+func parseCursorParam(r *http.Request, name string) (*pageCursor, error) {
+	token := r.URL.Query().Get(name)
+	if token == "" {
+		return nil, nil
+	}
+
+	c, err := parsePageCursor(token)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// cursorArgs splits an optional cursor into the nullable query parameters
+// the keyset queries take.
+func cursorArgs(c *pageCursor) (sql.NullTime, uuid.NullUUID) {
+	if c == nil {
+		return sql.NullTime{}, uuid.NullUUID{}
+	}
+	return sql.NullTime{Time: c.At, Valid: true}, uuid.NullUUID{UUID: c.ID, Valid: true}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPageCursorRoundTrip(t *testing.T) {
+	want := pageCursor{
+		At: time.Date(2025, 3, 1, 12, 30, 15, 123456000, time.UTC),
+		ID: uuid.New(),
+	}
+
+	got, err := parsePageCursor(want.String())
+	if err != nil {
+		t.Fatalf("parsePageCursor failed: %v", err)
+	}
+	if !got.At.Equal(want.At) || got.ID != want.ID {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseCursorParam(t *testing.T) {
+	valid := pageCursor{At: time.Now().UTC(), ID: uuid.New()}.String()
+
+	tests := []struct {
+		name    string
+		url     string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "absent", url: "/api/chirps", wantNil: true},
+		{name: "valid", url: "/api/chirps?cursor=" + valid},
+		{name: "bare id", url: "/api/chirps?cursor=" + uuid.NewString(), wantNil: true, wantErr: true},
+		{name: "not base64", url: "/api/chirps?cursor=%25%25", wantNil: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCursorParam(httptest.NewRequest("GET", tt.url, nil), "cursor")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCursorParam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (got == nil) != tt.wantNil {
+				t.Errorf("parseCursorParam() = %v, wantNil %v", got, tt.wantNil)
+			}
+		})
+	}
+}
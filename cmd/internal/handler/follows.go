@@ -1,231 +1,352 @@
-// DB Model:
-// Follower: The user who follows; Followee: The user who is followed
-
-package handler
-
-import (
-	"encoding/json"
-	"log"
-	"net/http"
-	"strconv"
-
-	"github.com/google/uuid"
-	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
-	"github.com/shubh-man007/Chirpy/cmd/internal/database"
-)
-
-func (h *APIHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
-	token, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	followerID, err := auth.ValidateJWT(token, h.cfg.JWTSecret)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	var req struct {
-		FolloweeID string `json:"followee_id"`
-	}
-
-	err = json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid Request"})
-		return
-	}
-
-	followeeID, err := uuid.Parse(req.FolloweeID)
-	if err != nil {
-		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID format"})
-		return
-	}
-
-	if followerID == followeeID {
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Cannot follow self",
-		})
-		return
-	}
-
-	err = h.cfg.DB.FollowUser(r.Context(), database.FollowUserParams{
-		FollowerID: followerID,
-		FolloweeID: followeeID,
-	})
-	if err != nil {
-		log.Printf("Error following user: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to follow user"})
-		return
-	}
-
-	w.WriteHeader(http.StatusNoContent)
-}
-
-func (h *APIHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
-	token, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	followerID, err := auth.ValidateJWT(token, h.cfg.JWTSecret)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	followeeIDStr := r.PathValue("userID")
-	followeeID, err := uuid.Parse(followeeIDStr)
-	if err != nil {
-		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID format"})
-		return
-	}
-
-	if followerID == followeeID {
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Cannot unfollow self",
-		})
-		return
-	}
-
-	err = h.cfg.DB.UnfollowUser(r.Context(), database.UnfollowUserParams{
-		FollowerID: followerID,
-		FolloweeID: followeeID,
-	})
-	if err != nil {
-		log.Printf("Error unfollowing user: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to unfollow user"})
-		return
-	}
-
-	w.WriteHeader(http.StatusNoContent)
-}
-
-func (h *APIHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
-	token, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	userID, err := auth.ValidateJWT(token, h.cfg.JWTSecret)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	followers, err := h.cfg.DB.GetFollowers(r.Context(), userID)
-	if err != nil {
-		log.Printf("Error fetching followers: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch followers"})
-		return
-	}
-
-	var followerCount int64
-	if len(followers) > 0 {
-		followerCount = followers[0].TotalFollowers
-	}
-
-	type FollowerInfo struct {
-		Followers     []database.GetFollowersRow
-		FollowerCount int64
-	}
-
-	respondJSON(w, http.StatusOK, FollowerInfo{
-		Followers:     followers,
-		FollowerCount: followerCount,
-	})
-}
-
-func (h *APIHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
-	token, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	userID, err := auth.ValidateJWT(token, h.cfg.JWTSecret)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	following, err := h.cfg.DB.GetFollowing(r.Context(), userID)
-	if err != nil {
-		log.Printf("Error fetching following users: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch following users"})
-		return
-	}
-
-	var followingCount int64
-	if len(following) > 0 {
-		followingCount = following[0].TotalFollowing
-	}
-
-	type FollowingInfo struct {
-		Following      []database.GetFollowingRow
-		FollowingCount int64
-	}
-
-	respondJSON(w, http.StatusOK, FollowingInfo{
-		Following:      following,
-		FollowingCount: followingCount,
-	})
-}
-
-func (h *APIHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
-	token, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	userID, err := auth.ValidateJWT(token, h.cfg.JWTSecret)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	query := r.URL.Query()
-
-	var limit int32 = 20
-	limitStr := query.Get("limit")
-	if limitStr != "" {
-		val, err := strconv.Atoi(limitStr)
-		if err != nil || val < 0 {
-			errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid limit"})
-			return
-		}
-		limit = int32(val)
-	}
-
-	var offset int32 = 0
-	offsetStr := query.Get("offset")
-	if offsetStr != "" {
-		val, err := strconv.Atoi(offsetStr)
-		if err != nil || val < 0 {
-			errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid offset"})
-			return
-		}
-		offset = int32(val)
-	}
-
-	chirps, err := h.cfg.DB.GetFeed(r.Context(), database.GetFeedParams{
-		FollowerID: userID,
-		Limit:      limit,
-		Offset:     offset,
-	})
-	if err != nil {
-		log.Printf("Error fetching feed: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch feed"})
-		return
-	}
-
-	if chirps == nil {
-		chirps = []database.GetFeedRow{}
-	}
-
-	respondJSON(w, http.StatusOK, chirps)
-}
+// DB Model:
+// Follower: The user who follows; Followee: The user who is followed
+
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const defaultFeedLimit = 20
+const maxFeedLimit = 100
+
+func (h *APIHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	followerID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		FolloweeID string `json:"followee_id"`
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid Request"})
+		return
+	}
+
+	followeeID, err := uuid.Parse(req.FolloweeID)
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID format"})
+		return
+	}
+
+	if followerID == followeeID {
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Cannot follow self",
+		})
+		return
+	}
+
+	blocked, err := h.cfg.DB.IsBlocked(r.Context(), database.IsBlockedParams{
+		UserA: followerID,
+		UserB: followeeID,
+	})
+	if err != nil {
+		log.Printf("Error checking block: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to follow user"})
+		return
+	}
+	if blocked {
+		errJSON(w, http.StatusForbidden, ErrMessage{Message: "Cannot follow this user"})
+		return
+	}
+
+	private, err := h.cfg.DB.GetUserPrivacy(r.Context(), followeeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errJSON(w, http.StatusNotFound, ErrMessage{Message: "User not found"})
+			return
+		}
+		log.Printf("Error fetching user privacy: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to follow user"})
+		return
+	}
+
+	// Private accounts approve their followers: park a request instead,
+	// unless the caller already follows them.
+	if private {
+		following, err := h.cfg.DB.IsFollowing(r.Context(), database.IsFollowingParams{
+			FollowerID: followerID,
+			FolloweeID: followeeID,
+		})
+		if err != nil {
+			log.Printf("Error checking follow: %v", err)
+			errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to follow user"})
+			return
+		}
+
+		if !following {
+			created, err := h.cfg.DB.CreateFollowRequest(r.Context(), database.CreateFollowRequestParams{
+				RequesterID: followerID,
+				TargetID:    followeeID,
+			})
+			if err != nil {
+				log.Printf("Error creating follow request: %v", err)
+				errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to follow user"})
+				return
+			}
+
+			if created > 0 {
+				h.notify(r.Context(), followeeID, followerID, notificationFollowRequest, uuid.NullUUID{})
+			}
+
+			respondJSON(w, http.StatusAccepted, struct {
+				Status string `json:"status"`
+			}{
+				Status: "pending",
+			})
+			return
+		}
+	}
+
+	followed, err := h.cfg.DB.FollowUser(r.Context(), database.FollowUserParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	})
+	if err != nil {
+		log.Printf("Error following user: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to follow user"})
+		return
+	}
+
+	// Repeating an existing follow is a no-op.
+	if followed > 0 {
+		h.cfg.Timeline.Backfill(followerID, followeeID)
+		h.notify(r.Context(), followeeID, followerID, notificationFollow, uuid.NullUUID{})
+		h.publishFollowerAdded(followerID, followeeID)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	followerID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	followeeIDStr := r.PathValue("userID")
+	followeeID, err := uuid.Parse(followeeIDStr)
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID format"})
+		return
+	}
+
+	if followerID == followeeID {
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Cannot unfollow self",
+		})
+		return
+	}
+
+	err = h.cfg.DB.UnfollowUser(r.Context(), database.UnfollowUserParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	})
+	if err != nil {
+		log.Printf("Error unfollowing user: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to unfollow user"})
+		return
+	}
+
+	// Unfollowing also withdraws a request that is still pending.
+	_, err = h.cfg.DB.DeleteFollowRequest(r.Context(), database.DeleteFollowRequestParams{
+		RequesterID: followerID,
+		TargetID:    followeeID,
+	})
+	if err != nil {
+		log.Printf("Error withdrawing follow request: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to unfollow user"})
+		return
+	}
+
+	h.cfg.Timeline.Prune(followerID, followeeID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	followers, err := h.cfg.DB.GetFollowers(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching followers: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch followers"})
+		return
+	}
+
+	var followerCount int64
+	if len(followers) > 0 {
+		followerCount = followers[0].TotalFollowers
+	}
+
+	type FollowerInfo struct {
+		Followers     []database.GetFollowersRow
+		FollowerCount int64
+	}
+
+	respondJSON(w, http.StatusOK, FollowerInfo{
+		Followers:     followers,
+		FollowerCount: followerCount,
+	})
+}
+
+func (h *APIHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	following, err := h.cfg.DB.GetFollowing(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching following users: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch following users"})
+		return
+	}
+
+	var followingCount int64
+	if len(following) > 0 {
+		followingCount = following[0].TotalFollowing
+	}
+
+	type FollowingInfo struct {
+		Following      []database.GetFollowingRow
+		FollowingCount int64
+	}
+
+	respondJSON(w, http.StatusOK, FollowingInfo{
+		Following:      following,
+		FollowingCount: followingCount,
+	})
+}
+
+func (h *APIHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	// Pages are keyed on the (created_at, id) of the activity that put each
+	// chirp in the feed. cursor walks towards older items; newer_than polls
+	// for items above the top of what the client already has.
+	limit := parsePageLimit(r, defaultFeedLimit, maxFeedLimit)
+
+	cursor, err := parseCursorParam(r, "cursor")
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid cursor"})
+		return
+	}
+	newerThan, err := parseCursorParam(r, "newer_than")
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid newer_than cursor"})
+		return
+	}
+
+	if cursor != nil && newerThan != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Use either cursor or newer_than, not both"})
+		return
+	}
+
+	params := database.GetHomeTimelineParams{
+		ViewerID:  userID,
+		PageLimit: limit,
+	}
+	params.CursorAt, params.CursorID = cursorArgs(cursor)
+	params.NewerAt, params.NewerID = cursorArgs(newerThan)
+
+	chirps, err := h.cfg.DB.GetHomeTimeline(r.Context(), params)
+	if err != nil {
+		log.Printf("Error fetching feed: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch feed"})
+		return
+	}
+
+	// Newer pages come back oldest first so the limit keeps the items
+	// closest to the client's top; flip them to match the feed order.
+	if newerThan != nil {
+		slices.Reverse(chirps)
+	}
+
+	ids := make([]uuid.UUID, len(chirps))
+	for i, c := range chirps {
+		ids[i] = c.ID
+	}
+	resolved := h.resolvedMentions(r.Context(), ids)
+
+	type FeedChirp struct {
+		database.GetHomeTimelineRow
+		FilterMatch
+		Entities ChirpEntities `json:"entities"`
+	}
+
+	// Muted words are applied after paging, so cursors come from the
+	// unfiltered page and a filtered page may be shorter than the limit.
+	filters := h.wordFiltersFor(r.Context(), &userID)
+	items := make([]FeedChirp, 0, len(chirps))
+	for _, c := range chirps {
+		match, keep := filters.apply(c.UserID, c.Body)
+		if !keep {
+			continue
+		}
+		items = append(items, FeedChirp{
+			GetHomeTimelineRow: c,
+			FilterMatch:        match,
+			Entities:           buildChirpEntities(c.Body, resolved[c.ID]),
+		})
+	}
+
+	next, prev := feedCursors(chirps, limit, newerThan)
+
+	type FeedResponse struct {
+		Chirps     []FeedChirp `json:"chirps"`
+		NextCursor *string     `json:"next_cursor,omitempty"`
+		PrevCursor *string     `json:"prev_cursor,omitempty"`
+	}
+
+	respondJSON(w, http.StatusOK, FeedResponse{
+		Chirps:     items,
+		NextCursor: next,
+		PrevCursor: prev,
+	})
+}
+
+// feedCursors works out the cursors for a feed page in newest-first order.
+// prev_cursor always points at the top of what the client has seen, so it
+// can keep polling with newer_than even when a poll comes back empty.
+// next_cursor is only set while older items may remain.
+func feedCursors(chirps []database.GetHomeTimelineRow, limit int32, newerThan *pageCursor) (next, prev *string) {
+	if len(chirps) == 0 {
+		if newerThan != nil {
+			top := newerThan.String()
+			prev = &top
+		}
+		return nil, prev
+	}
+
+	top := activityCursor(chirps[0]).String()
+	prev = &top
+
+	if newerThan == nil && len(chirps) == int(limit) {
+		bottom := activityCursor(chirps[len(chirps)-1]).String()
+		next = &bottom
+	}
+
+	return next, prev
+}
+
+func activityCursor(c database.GetHomeTimelineRow) pageCursor {
+	return pageCursor{At: c.ActivityAt, ID: c.ActivityID}
+}
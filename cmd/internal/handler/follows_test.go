@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+func TestFeedCursors(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	row := func(age time.Duration) database.GetHomeTimelineRow {
+		return database.GetHomeTimelineRow{ActivityAt: now.Add(-age), ActivityID: uuid.New()}
+	}
+	page := []database.GetHomeTimelineRow{row(0), row(time.Minute), row(2 * time.Minute)}
+	top, bottom := activityCursor(page[0]).String(), activityCursor(page[2]).String()
+	seen := pageCursor{At: now.Add(-time.Hour), ID: uuid.New()}
+	seenToken := seen.String()
+
+	tests := []struct {
+		name      string
+		chirps    []database.GetHomeTimelineRow
+		limit     int32
+		newerThan *pageCursor
+		wantNext  *string
+		wantPrev  *string
+	}{
+		{name: "full first page", chirps: page, limit: 3, wantNext: &bottom, wantPrev: &top},
+		{name: "last page", chirps: page, limit: 10, wantPrev: &top},
+		{name: "newer page", chirps: page, limit: 3, newerThan: &seen, wantPrev: &top},
+		{name: "empty feed", limit: 3},
+		{name: "empty poll keeps position", limit: 3, newerThan: &seen, wantPrev: &seenToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, prev := feedCursors(tt.chirps, tt.limit, tt.newerThan)
+			if !sameCursor(next, tt.wantNext) {
+				t.Errorf("next = %v, want %v", next, tt.wantNext)
+			}
+			if !sameCursor(prev, tt.wantPrev) {
+				t.Errorf("prev = %v, want %v", prev, tt.wantPrev)
+			}
+		})
+	}
+}
+
+func sameCursor(got, want *string) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return *got == *want
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	minHandleLength = 3
+	maxHandleLength = 20
+)
+
+var reservedHandles = map[string]struct{}{
+	"about":     {},
+	"admin":     {},
+	"api":       {},
+	"app":       {},
+	"assets":    {},
+	"chirpy":    {},
+	"help":      {},
+	"login":     {},
+	"logout":    {},
+	"me":        {},
+	"moderator": {},
+	"null":      {},
+	"root":      {},
+	"settings":  {},
+	"signup":    {},
+	"static":    {},
+	"support":   {},
+	"system":    {},
+	"undefined": {},
+}
+
+// validateHandle checks that a handle is 3-20 characters of ASCII letters,
+// digits or underscores, is not purely numeric and is not reserved.
+func validateHandle(handle string) error {
+	if len(handle) < minHandleLength || len(handle) > maxHandleLength {
+		return fmt.Errorf("handle must be between %d and %d characters", minHandleLength, maxHandleLength)
+	}
+
+	allDigits := true
+	for _, r := range handle {
+		switch {
+		case r >= '0' && r <= '9':
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			allDigits = false
+		default:
+			return errors.New("handle may only contain letters, digits and underscores")
+		}
+	}
+
+	if allDigits {
+		return errors.New("handle cannot be only digits")
+	}
+
+	if _, ok := reservedHandles[strings.ToLower(handle)]; ok {
+		return errors.New("handle is reserved")
+	}
+
+	return nil
+}
+
+// defaultHandle generates a placeholder handle for accounts created without one.
+func defaultHandle() string {
+	return "user_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+}
+
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"testing"
+)
+
+func TestValidateHandle(t *testing.T) {
+	tests := []struct {
+		name    string
+		handle  string
+		wantErr bool
+	}{
+		{name: "simple handle", handle: "chirper", wantErr: false},
+		{name: "underscores and digits", handle: "bird_42", wantErr: false},
+		{name: "mixed case", handle: "ChirpyBird", wantErr: false},
+		{name: "minimum length", handle: "abc", wantErr: false},
+		{name: "maximum length", handle: "abcdefghijklmnopqrst", wantErr: false},
+		{name: "too short", handle: "ab", wantErr: true},
+		{name: "too long", handle: "abcdefghijklmnopqrstu", wantErr: true},
+		{name: "empty", handle: "", wantErr: true},
+		{name: "contains space", handle: "big bird", wantErr: true},
+		{name: "contains dash", handle: "big-bird", wantErr: true},
+		{name: "contains at sign", handle: "@bird", wantErr: true},
+		{name: "non ascii letters", handle: "vögel", wantErr: true},
+		{name: "only digits", handle: "12345", wantErr: true},
+		{name: "reserved word", handle: "admin", wantErr: true},
+		{name: "reserved word mixed case", handle: "Chirpy", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHandle(tt.handle)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateHandle(%q) error = %v, wantErr %v", tt.handle, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultHandleIsValid(t *testing.T) {
+	handle := defaultHandle()
+	if err := validateHandle(handle); err != nil {
+		t.Errorf("defaultHandle() = %q is not a valid handle: %v", handle, err)
+	}
+}
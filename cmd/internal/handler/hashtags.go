@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const (
+	maxHashtagLength    = 50
+	maxHashtagsPerChirp = 10
+
+	defaultHashtagChirpsLimit = 20
+	maxHashtagChirpsLimit     = 100
+
+	defaultTrendingWindowHours = 24
+	maxTrendingWindowHours     = 7 * 24
+	defaultTrendingLimit       = 10
+	maxTrendingLimit           = 50
+)
+
+func isHashtagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// normalizeHashtag lowercases a tag and strips a leading '#'. It returns
+// false for anything that could not have been extracted from a chirp.
+func normalizeHashtag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+	if tag == "" || len([]rune(tag)) > maxHashtagLength {
+		return "", false
+	}
+
+	allDigits := true
+	for _, r := range tag {
+		if !isHashtagRune(r) {
+			return "", false
+		}
+		if !unicode.IsDigit(r) {
+			allDigits = false
+		}
+	}
+	if allDigits {
+		return "", false
+	}
+
+	return tag, true
+}
+
+// scanHashtags finds every hashtag in body with its position. A '#' only
+// starts a tag at the beginning of the text or after a non-word character,
+// so "a#b" and URL fragments are not tags. Tags that are all digits or
+// longer than maxHashtagLength are skipped.
+func scanHashtags(body string) []HashtagEntity {
+	runes := []rune(body)
+	entities := []HashtagEntity{}
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '#' || (i > 0 && isHashtagRune(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isHashtagRune(runes[end]) {
+			end++
+		}
+
+		tag, ok := normalizeHashtag(string(runes[i+1 : end]))
+		if ok {
+			entities = append(entities, HashtagEntity{Tag: tag, Start: i, End: end})
+		}
+		i = end - 1
+	}
+
+	return entities
+}
+
+// extractHashtags returns the distinct, normalized hashtags in body in the
+// order they first appear, capped at maxHashtagsPerChirp.
+func extractHashtags(body string) []string {
+	seen := make(map[string]struct{})
+	tags := []string{}
+
+	for _, entity := range scanHashtags(body) {
+		if _, dup := seen[entity.Tag]; dup {
+			continue
+		}
+
+		seen[entity.Tag] = struct{}{}
+		tags = append(tags, entity.Tag)
+		if len(tags) == maxHashtagsPerChirp {
+			break
+		}
+	}
+
+	return tags
+}
+
+// syncHashtags replaces the stored hashtags of a chirp with those found in
+// its body. Failures are logged rather than surfaced: the chirp itself has
+// already been saved.
+func (h *APIHandler) syncHashtags(ctx context.Context, chirpID uuid.UUID, body string) {
+	err := h.cfg.DB.SyncChirpHashtags(ctx, database.SyncChirpHashtagsParams{
+		Tags:    extractHashtags(body),
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		log.Printf("Error syncing hashtags for chirp %s: %v", chirpID, err)
+	}
+}
+
+func (h *APIHandler) GetHashtagChirps(w http.ResponseWriter, r *http.Request) {
+	tag, ok := normalizeHashtag(r.PathValue("tag"))
+	if !ok {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid hashtag"})
+		return
+	}
+
+	limit, cursor := parsePageParams(r, defaultHashtagChirpsLimit, maxHashtagChirpsLimit)
+
+	chirps, err := h.cfg.DB.GetHashtagChirps(r.Context(), database.GetHashtagChirpsParams{
+		ViewerID:  nullViewerID(h.optionalViewerID(r)),
+		Tag:       tag,
+		Cursor:    cursor,
+		PageLimit: limit,
+	})
+	if err != nil {
+		log.Printf("Error fetching hashtag chirps: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch chirps"})
+		return
+	}
+
+	if chirps == nil {
+		chirps = []database.GetHashtagChirpsRow{}
+	}
+
+	var nextCursor *string
+	if len(chirps) == int(limit) {
+		lastID := chirps[len(chirps)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	type HashtagChirpsResponse struct {
+		Tag        string                         `json:"tag"`
+		Chirps     []database.GetHashtagChirpsRow `json:"chirps"`
+		NextCursor *string                        `json:"next_cursor,omitempty"`
+	}
+
+	respondJSON(w, http.StatusOK, HashtagChirpsResponse{
+		Tag:        tag,
+		Chirps:     chirps,
+		NextCursor: nextCursor,
+	})
+}
+
+// GetTrending ranks tags used within the last "window" hours. Every use
+// counts for less the older it is, halving every quarter of the window, so
+// a burst of recent chirps outranks a steady trickle spread over the day.
+func (h *APIHandler) GetTrending(w http.ResponseWriter, r *http.Request) {
+	window := defaultTrendingWindowHours
+	if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+		val, err := strconv.Atoi(windowStr)
+		if err != nil || val <= 0 || val > maxTrendingWindowHours {
+			errJSON(w, http.StatusBadRequest, ErrMessage{
+				Message: "window must be between 1 and " + strconv.Itoa(maxTrendingWindowHours) + " hours",
+			})
+			return
+		}
+		window = val
+	}
+
+	limit, _ := parsePageParams(r, defaultTrendingLimit, maxTrendingLimit)
+
+	tags, err := h.cfg.DB.GetTrendingHashtags(r.Context(), database.GetTrendingHashtagsParams{
+		HalfLifeHours: float64(window) / 4,
+		WindowHours:   int32(window),
+		PageLimit:     limit,
+	})
+	if err != nil {
+		log.Printf("Error fetching trending hashtags: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch trending tags"})
+		return
+	}
+
+	if tags == nil {
+		tags = []database.GetTrendingHashtagsRow{}
+	}
+
+	type TrendingResponse struct {
+		WindowHours int                               `json:"window_hours"`
+		Tags        []database.GetTrendingHashtagsRow `json:"tags"`
+	}
+
+	respondJSON(w, http.StatusOK, TrendingResponse{
+		WindowHours: window,
+		Tags:        tags,
+	})
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "no tags", body: "just chirping", want: []string{}},
+		{name: "single tag", body: "hello #golang", want: []string{"golang"}},
+		{name: "start of body", body: "#first post", want: []string{"first"}},
+		{name: "lowercased", body: "#GoLang rocks", want: []string{"golang"}},
+		{name: "duplicates removed", body: "#go #Go #GO", want: []string{"go"}},
+		{name: "keeps order", body: "#b then #a", want: []string{"b", "a"}},
+		{name: "trailing punctuation", body: "love #chirpy!", want: []string{"chirpy"}},
+		{name: "underscores and digits", body: "#web_3 #go2", want: []string{"web_3", "go2"}},
+		{name: "unicode letters", body: "#café", want: []string{"café"}},
+		{name: "all digits ignored", body: "issue #123", want: []string{}},
+		{name: "mid word ignored", body: "a#b c#d", want: []string{}},
+		{name: "url fragment ignored", body: "see example.com/page#section", want: []string{}},
+		{name: "bare hash", body: "# heading", want: []string{}},
+		{name: "double hash", body: "##tag", want: []string{"tag"}},
+		{name: "too long ignored", body: "#" + strings.Repeat("a", maxHashtagLength+1), want: []string{}},
+		{name: "max length kept", body: "#" + strings.Repeat("a", maxHashtagLength), want: []string{strings.Repeat("a", maxHashtagLength)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractHashtags(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractHashtags(%q) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractHashtagsLimit(t *testing.T) {
+	var body []string
+	for i := 0; i < maxHashtagsPerChirp+5; i++ {
+		body = append(body, "#tag"+strings.Repeat("x", i))
+	}
+
+	if got := extractHashtags(strings.Join(body, " ")); len(got) != maxHashtagsPerChirp {
+		t.Errorf("extractHashtags() returned %d tags, want %d", len(got), maxHashtagsPerChirp)
+	}
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{input: "golang", want: "golang", wantOK: true},
+		{input: "#GoLang", want: "golang", wantOK: true},
+		{input: "", wantOK: false},
+		{input: "#", wantOK: false},
+		{input: "2024", wantOK: false},
+		{input: "go-lang", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := normalizeHashtag(tt.input)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("normalizeHashtag(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const defaultLikedChirpsLimit = 20
+const maxLikedChirpsLimit = 100
+
+func (h *APIHandler) LikeChirp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
+		return
+	}
+
+	chirp, err := h.getVisibleChirp(r.Context(), chirpID, &userID)
+	if err != nil {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp not found"})
+		return
+	}
+
+	err = h.cfg.DB.LikeChirp(r.Context(), database.LikeChirpParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		log.Printf("Error liking chirp: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to like chirp"})
+		return
+	}
+
+	h.notify(r.Context(), chirp.UserID, userID, notificationLike, uuid.NullUUID{UUID: chirp.ID, Valid: true})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) UnlikeChirp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
+		return
+	}
+
+	err = h.cfg.DB.UnlikeChirp(r.Context(), database.UnlikeChirpParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		log.Printf("Error unliking chirp: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to unlike chirp"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) GetChirpLikes(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
+		return
+	}
+
+	if _, err := h.getVisibleChirp(r.Context(), chirpID, h.optionalViewerID(r)); err != nil {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp not found"})
+		return
+	}
+
+	likes, err := h.cfg.DB.GetChirpLikes(r.Context(), chirpID)
+	if err != nil {
+		log.Printf("Error fetching likes: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch likes"})
+		return
+	}
+
+	var likeCount int64
+	if len(likes) > 0 {
+		likeCount = likes[0].TotalLikes
+	}
+
+	if likes == nil {
+		likes = []database.GetChirpLikesRow{}
+	}
+
+	type LikesInfo struct {
+		Likes     []database.GetChirpLikesRow `json:"likes"`
+		LikeCount int64                       `json:"like_count"`
+	}
+
+	respondJSON(w, http.StatusOK, LikesInfo{
+		Likes:     likes,
+		LikeCount: likeCount,
+	})
+}
+
+func (h *APIHandler) GetUserLikes(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID"})
+		return
+	}
+
+	limit, cursor := parsePageParams(r, defaultLikedChirpsLimit, maxLikedChirpsLimit)
+
+	chirps, err := h.cfg.DB.GetUserLikedChirps(r.Context(), database.GetUserLikedChirpsParams{
+		ViewerID:  nullViewerID(h.optionalViewerID(r)),
+		UserID:    userID,
+		Cursor:    cursor,
+		PageLimit: limit,
+	})
+	if err != nil {
+		log.Printf("Error fetching liked chirps: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch liked chirps"})
+		return
+	}
+
+	if chirps == nil {
+		chirps = []database.GetUserLikedChirpsRow{}
+	}
+
+	var nextCursor *string
+	if len(chirps) == int(limit) {
+		lastID := chirps[len(chirps)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	type LikedChirpsResponse struct {
+		Chirps     []database.GetUserLikedChirpsRow `json:"chirps"`
+		NextCursor *string                          `json:"next_cursor,omitempty"`
+	}
+
+	respondJSON(w, http.StatusOK, LikedChirpsResponse{
+		Chirps:     chirps,
+		NextCursor: nextCursor,
+	})
+}
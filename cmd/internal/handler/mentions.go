@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const maxMentionsPerChirp = 10
+
+const defaultMentionsLimit = 20
+const maxMentionsLimit = 100
+
+func isHandleRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_'
+}
+
+// mentionMatch is an @handle found in a chirp body before it has been
+// resolved to a user.
+type mentionMatch struct {
+	Handle string
+	Start  int
+	End    int
+}
+
+// scanMentions finds every @handle in body with its position. Like
+// hashtags, an '@' must start the text or follow a non-word character, so
+// email addresses are not mentions. The handle must be a syntactically
+// valid handle and end at a non-word character.
+func scanMentions(body string) []mentionMatch {
+	runes := []rune(body)
+	matches := []mentionMatch{}
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' || (i > 0 && isHashtagRune(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isHandleRune(runes[end]) {
+			end++
+		}
+
+		handle := string(runes[i+1 : end])
+		followedByWord := end < len(runes) && isHashtagRune(runes[end])
+		if !followedByWord && validateHandle(handle) == nil {
+			matches = append(matches, mentionMatch{Handle: handle, Start: i, End: end})
+		}
+		i = end - 1
+	}
+
+	return matches
+}
+
+// extractMentions returns the distinct lowercased handles mentioned in body,
+// capped at maxMentionsPerChirp.
+func extractMentions(body string) []string {
+	seen := make(map[string]struct{})
+	handles := []string{}
+
+	for _, match := range scanMentions(body) {
+		handle := strings.ToLower(match.Handle)
+		if _, dup := seen[handle]; dup {
+			continue
+		}
+
+		seen[handle] = struct{}{}
+		handles = append(handles, handle)
+		if len(handles) == maxMentionsPerChirp {
+			break
+		}
+	}
+
+	return handles
+}
+
+// buildChirpEntities locates mentions and hashtags in body. resolved maps
+// lowercased handles to user IDs; mentions of unknown handles are dropped.
+func buildChirpEntities(body string, resolved map[string]uuid.UUID) ChirpEntities {
+	entities := ChirpEntities{
+		Mentions: []MentionEntity{},
+		Hashtags: scanHashtags(body),
+	}
+
+	for _, match := range scanMentions(body) {
+		userID, ok := resolved[strings.ToLower(match.Handle)]
+		if !ok {
+			continue
+		}
+		entities.Mentions = append(entities.Mentions, MentionEntity{
+			Handle: match.Handle,
+			UserID: userID,
+			Start:  match.Start,
+			End:    match.End,
+		})
+	}
+
+	return entities
+}
+
+// syncMentions replaces the stored mentions of a chirp with the users its
+// body mentions and notifies users who are mentioned for the first time.
+// Like syncHashtags, failures are only logged.
+func (h *APIHandler) syncMentions(ctx context.Context, chirpID, authorID uuid.UUID, body string) {
+	added, err := h.cfg.DB.SyncChirpMentions(ctx, database.SyncChirpMentionsParams{
+		Handles: extractMentions(body),
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		log.Printf("Error syncing mentions for chirp %s: %v", chirpID, err)
+		return
+	}
+
+	for _, userID := range added {
+		h.notify(ctx, userID, authorID, notificationMention, uuid.NullUUID{UUID: chirpID, Valid: true})
+	}
+}
+
+// resolvedMentions loads the stored mentions for a batch of chirps, keyed by
+// chirp ID and then by lowercased handle.
+func (h *APIHandler) resolvedMentions(ctx context.Context, chirpIDs []uuid.UUID) map[uuid.UUID]map[string]uuid.UUID {
+	resolved := make(map[uuid.UUID]map[string]uuid.UUID)
+	if len(chirpIDs) == 0 {
+		return resolved
+	}
+
+	rows, err := h.cfg.DB.GetMentionsForChirps(ctx, chirpIDs)
+	if err != nil {
+		log.Printf("Error fetching mentions: %v", err)
+		return resolved
+	}
+
+	for _, row := range rows {
+		if resolved[row.ChirpID] == nil {
+			resolved[row.ChirpID] = make(map[string]uuid.UUID)
+		}
+		resolved[row.ChirpID][strings.ToLower(row.Handle)] = row.UserID
+	}
+
+	return resolved
+}
+
+func (h *APIHandler) chirpEntities(ctx context.Context, chirpID uuid.UUID, body string) ChirpEntities {
+	resolved := h.resolvedMentions(ctx, []uuid.UUID{chirpID})
+	return buildChirpEntities(body, resolved[chirpID])
+}
+
+func (h *APIHandler) GetMyMentions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit, cursor := parsePageParams(r, defaultMentionsLimit, maxMentionsLimit)
+
+	chirps, err := h.cfg.DB.GetUserMentions(r.Context(), database.GetUserMentionsParams{
+		UserID:    userID,
+		Cursor:    cursor,
+		PageLimit: limit,
+	})
+	if err != nil {
+		log.Printf("Error fetching mentions: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch mentions"})
+		return
+	}
+
+	ids := make([]uuid.UUID, len(chirps))
+	for i, c := range chirps {
+		ids[i] = c.ID
+	}
+	resolved := h.resolvedMentions(r.Context(), ids)
+
+	type MentionChirp struct {
+		database.GetUserMentionsRow
+		Entities ChirpEntities `json:"entities"`
+	}
+
+	items := make([]MentionChirp, len(chirps))
+	for i, c := range chirps {
+		items[i] = MentionChirp{
+			GetUserMentionsRow: c,
+			Entities:           buildChirpEntities(c.Body, resolved[c.ID]),
+		}
+	}
+
+	var nextCursor *string
+	if len(chirps) == int(limit) {
+		lastID := chirps[len(chirps)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	type MentionsResponse struct {
+		Chirps     []MentionChirp `json:"chirps"`
+		NextCursor *string        `json:"next_cursor,omitempty"`
+	}
+
+	respondJSON(w, http.StatusOK, MentionsResponse{
+		Chirps:     items,
+		NextCursor: nextCursor,
+	})
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{name: "no mentions", body: "hello world", want: []string{}},
+		{name: "single mention", body: "hi @bigbird", want: []string{"bigbird"}},
+		{name: "start of body", body: "@bigbird hi", want: []string{"bigbird"}},
+		{name: "lowercased and deduplicated", body: "@BigBird @bigbird", want: []string{"bigbird"}},
+		{name: "keeps order", body: "@zed and @amy", want: []string{"zed", "amy"}},
+		{name: "trailing punctuation", body: "thanks @amy_1!", want: []string{"amy_1"}},
+		{name: "email ignored", body: "mail me at amy@example.com", want: []string{}},
+		{name: "too short ignored", body: "@ab", want: []string{}},
+		{name: "too long ignored", body: "@abcdefghijklmnopqrstu", want: []string{}},
+		{name: "digits only ignored", body: "@12345", want: []string{}},
+		{name: "non ascii continuation ignored", body: "@josé", want: []string{}},
+		{name: "bare at sign", body: "meet @ noon", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractMentions(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractMentions(%q) = %v, want %v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildChirpEntities(t *testing.T) {
+	amy := uuid.New()
+	body := "héllo @Amy and @ghost #Go"
+
+	got := buildChirpEntities(body, map[string]uuid.UUID{"amy": amy})
+
+	wantMentions := []MentionEntity{{Handle: "Amy", UserID: amy, Start: 6, End: 10}}
+	if !reflect.DeepEqual(got.Mentions, wantMentions) {
+		t.Errorf("Mentions = %+v, want %+v", got.Mentions, wantMentions)
+	}
+
+	wantHashtags := []HashtagEntity{{Tag: "go", Start: 22, End: 25}}
+	if !reflect.DeepEqual(got.Hashtags, wantHashtags) {
+		t.Errorf("Hashtags = %+v, want %+v", got.Hashtags, wantHashtags)
+	}
+
+	runes := []rune(body)
+	if span := string(runes[got.Mentions[0].Start:got.Mentions[0].End]); span != "@Amy" {
+		t.Errorf("mention span = %q, want %q", span, "@Amy")
+	}
+}
+
+func TestBuildChirpEntitiesEmpty(t *testing.T) {
+	got := buildChirpEntities("nothing here", nil)
+	if got.Mentions == nil || got.Hashtags == nil {
+		t.Errorf("entities should be empty slices, not nil: %+v", got)
+	}
+}
@@ -1,91 +1,162 @@
-package handler
-
-import (
-	"encoding/json"
-	"log"
-	"net/http"
-
-	"github.com/google/uuid"
-	"github.com/shubh-man007/Chirpy/cmd/internal/config"
-)
-
-type AdminHandler struct {
-	apiCfg *config.ApiConfig
-}
-
-func NewAdminHandler(cfg *config.ApiConfig) *AdminHandler {
-	return &AdminHandler{apiCfg: cfg}
-}
-
-type APIHandler struct {
-	cfg *config.ApiConfig
-}
-
-func NewAPIHandler(cfg *config.ApiConfig) *APIHandler {
-	return &APIHandler{
-		cfg: cfg,
-	}
-}
-
-type UserLogin struct {
-	Password string `json:"password"`
-	Email    string `json:"email"`
-}
-
-type ProfileResponse struct {
-	ID             uuid.UUID   `json:"id"`
-	Email          string      `json:"email"`
-	CreatedAt      string      `json:"created_at"`
-	UpdatedAt      string      `json:"updated_at"`
-	IsChirpyRed    bool        `json:"is_chirpy_red"`
-	FollowersCount int64       `json:"followers_count"`
-	FollowingCount int64       `json:"following_count"`
-	ChirpsCount    int64       `json:"chirps_count"`
-	Chirps         []ChirpItem `json:"chirps"`
-	NextCursor     *string     `json:"next_cursor,omitempty"`
-	IsFollowing    *bool       `json:"is_following,omitempty"`
-}
-
-type ChirpItem struct {
-	ID        uuid.UUID `json:"id"`
-	Body      string    `json:"body"`
-	CreatedAt string    `json:"created_at"`
-}
-
-type ChirpBody struct {
-	Body string `json:"body"`
-}
-
-type ChirpLenValid struct {
-	Body    string `json:"cleaned_body"`
-	Message bool   `json:"valid"`
-}
-
-type MembershipWebhookData struct {
-	UserID string `json:"user_id"`
-}
-
-type MembershipWebhookEvent struct {
-	Event string                `json:"event"`
-	Data  MembershipWebhookData `json:"data"`
-}
-
-type ErrMessage struct {
-	Message string `json:"error"`
-}
-
-func errJSON(w http.ResponseWriter, code int, payload any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	if err := json.NewEncoder(w).Encode(payload); err != nil {
-		log.Printf("Error encoding response: %s", err)
-	}
-}
-
-func respondJSON(w http.ResponseWriter, code int, payload any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	if err := json.NewEncoder(w).Encode(payload); err != nil {
-		log.Printf("Error encoding response: %s", err)
-	}
-}
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/config"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+type AdminHandler struct {
+	apiCfg *config.ApiConfig
+}
+
+func NewAdminHandler(cfg *config.ApiConfig) *AdminHandler {
+	return &AdminHandler{apiCfg: cfg}
+}
+
+type APIHandler struct {
+	cfg *config.ApiConfig
+}
+
+func NewAPIHandler(cfg *config.ApiConfig) *APIHandler {
+	return &APIHandler{
+		cfg: cfg,
+	}
+}
+
+type UserLogin struct {
+	Password   string `json:"password"`
+	Email      string `json:"email"`
+	Handle     string `json:"handle,omitempty"`
+	DeviceName string `json:"device_name,omitempty"`
+}
+
+type ProfileResponse struct {
+	ID             uuid.UUID   `json:"id"`
+	Handle         string      `json:"handle"`
+	Email          string      `json:"email,omitempty"`
+	DisplayName    string      `json:"display_name"`
+	Bio            string      `json:"bio"`
+	Location       string      `json:"location"`
+	Website        string      `json:"website"`
+	AvatarURL      string      `json:"avatar_url,omitempty"`
+	CreatedAt      string      `json:"created_at"`
+	UpdatedAt      string      `json:"updated_at"`
+	IsChirpyRed    bool        `json:"is_chirpy_red"`
+	IsPrivate      bool        `json:"is_private"`
+	FollowersCount int64       `json:"followers_count"`
+	FollowingCount int64       `json:"following_count"`
+	ChirpsCount    int64       `json:"chirps_count"`
+	Chirps         []ChirpItem `json:"chirps"`
+	NextCursor     *string     `json:"next_cursor,omitempty"`
+	IsFollowing    *bool       `json:"is_following,omitempty"`
+	// ChirpsHidden is set when the account is private and the viewer does
+	// not follow it, or when either side blocks the other; Chirps is then
+	// always empty.
+	ChirpsHidden bool `json:"chirps_hidden,omitempty"`
+}
+
+// PublicUser is the view of an account that is safe to show to other users.
+type PublicUser struct {
+	ID          uuid.UUID `json:"id"`
+	Handle      string    `json:"handle"`
+	CreatedAt   time.Time `json:"created_at"`
+	IsChirpyRed bool      `json:"is_chirpy_red"`
+}
+
+type ChirpItem struct {
+	ID            uuid.UUID     `json:"id"`
+	Body          string        `json:"body"`
+	CreatedAt     string        `json:"created_at"`
+	EditCount     int32         `json:"edit_count"`
+	Edited        bool          `json:"edited"`
+	ParentID      *uuid.UUID    `json:"parent_id,omitempty"`
+	Kind          string        `json:"kind"`
+	OriginalID    *uuid.UUID    `json:"original_id,omitempty"`
+	ReplyCount    int64         `json:"reply_count"`
+	LikeCount     int64         `json:"like_count"`
+	LikedByViewer bool          `json:"liked_by_viewer"`
+	Entities      ChirpEntities `json:"entities"`
+	FilterMatch
+}
+
+// ProfileUpdate is the body of PATCH /api/me/profile. Omitted fields are
+// left unchanged; an empty string clears the field.
+type ProfileUpdate struct {
+	DisplayName *string `json:"display_name"`
+	Bio         *string `json:"bio"`
+	Location    *string `json:"location"`
+	Website     *string `json:"website"`
+}
+
+// ChirpEntities lists the spans of a chirp body clients may want to
+// highlight. Start and End are offsets in Unicode code points into the body,
+// End exclusive, and include the leading '@' or '#'.
+type ChirpEntities struct {
+	Mentions []MentionEntity `json:"mentions"`
+	Hashtags []HashtagEntity `json:"hashtags"`
+}
+
+type MentionEntity struct {
+	Handle string    `json:"handle"`
+	UserID uuid.UUID `json:"user_id"`
+	Start  int       `json:"start"`
+	End    int       `json:"end"`
+}
+
+type HashtagEntity struct {
+	Tag   string `json:"tag"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+type ChirpBody struct {
+	Body      string `json:"body"`
+	InReplyTo string `json:"in_reply_to,omitempty"`
+	QuoteOf   string `json:"quote_of,omitempty"`
+}
+
+// ChirpResponse is a stored chirp together with its highlightable entities.
+type ChirpResponse struct {
+	database.Chirp
+	Entities ChirpEntities `json:"entities"`
+}
+
+type ChirpLenValid struct {
+	Body    string `json:"cleaned_body"`
+	Message bool   `json:"valid"`
+}
+
+type MembershipWebhookData struct {
+	UserID string `json:"user_id"`
+}
+
+type MembershipWebhookEvent struct {
+	Event string                `json:"event"`
+	Data  MembershipWebhookData `json:"data"`
+}
+
+type ErrMessage struct {
+	Message string `json:"error"`
+}
+
+func errJSON(w http.ResponseWriter, code int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.Printf("Error encoding response: %s", err)
+	}
+}
+
+func respondJSON(w http.ResponseWriter, code int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.Printf("Error encoding response: %s", err)
+	}
+}
@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+	"github.com/shubh-man007/Chirpy/cmd/internal/moderation"
+)
+
+const (
+	mutedWordHide = "hide"
+	mutedWordFlag = "flag"
+)
+
+const maxMutedWordLength = 100
+const maxMutedWords = 200
+
+// FilterMatch is embedded in listed chirps. It is set when a chirp matches
+// one of the viewer's muted words whose action is "flag", so clients can
+// collapse it instead of showing it.
+type FilterMatch struct {
+	Filtered   bool   `json:"filtered,omitempty"`
+	FilteredBy string `json:"filtered_by,omitempty"`
+}
+
+// wordFilter is a muted word prepared for matching. Phrases are tokenized
+// the same way as chirp bodies so "go," in a chirp matches a filter on "go".
+type wordFilter struct {
+	phrase    string
+	tokens    []string
+	wholeWord bool
+	hide      bool
+}
+
+// tokenize splits text into words the way the content filter does, with
+// surrounding punctuation removed and each word folded, but keeping '#' so
+// hashtags stay distinct.
+func tokenize(text string) []string {
+	var tokens []string
+	for _, word := range strings.Fields(text) {
+		_, core, _ := moderation.SplitHashtag(word)
+		if core != "" {
+			tokens = append(tokens, moderation.Fold(core))
+		}
+	}
+	return tokens
+}
+
+func newWordFilter(phrase string, wholeWord bool, action string) wordFilter {
+	return wordFilter{
+		phrase:    phrase,
+		tokens:    tokenize(phrase),
+		wholeWord: wholeWord,
+		hide:      action != mutedWordFlag,
+	}
+}
+
+// tokenMatches compares a chirp token with a filter token. A plain word also
+// matches the hashtag of the same name; a #hashtag only matches hashtags.
+func tokenMatches(token, filter string) bool {
+	if token == filter {
+		return true
+	}
+	return !strings.HasPrefix(filter, "#") && strings.TrimPrefix(token, "#") == filter
+}
+
+func (f wordFilter) matches(body string, tokens []string) bool {
+	if !f.wholeWord {
+		return strings.Contains(strings.ToLower(body), strings.ToLower(f.phrase))
+	}
+
+	if len(f.tokens) == 0 || len(f.tokens) > len(tokens) {
+		return false
+	}
+
+	for start := 0; start+len(f.tokens) <= len(tokens); start++ {
+		matched := true
+		for i, ft := range f.tokens {
+			if !tokenMatches(tokens[start+i], ft) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// wordFilters holds a viewer's active muted words. The zero value filters
+// nothing, which is what anonymous requests get.
+type wordFilters struct {
+	viewerID uuid.UUID
+	filters  []wordFilter
+}
+
+// check returns the filter a chirp matches, preferring one that hides it.
+// Viewers never filter their own chirps.
+func (fs wordFilters) check(authorID uuid.UUID, body string) (wordFilter, bool) {
+	if len(fs.filters) == 0 || authorID == fs.viewerID || body == "" {
+		return wordFilter{}, false
+	}
+
+	tokens := tokenize(body)
+	var match wordFilter
+	found := false
+	for _, f := range fs.filters {
+		if !f.matches(body, tokens) {
+			continue
+		}
+		if f.hide {
+			return f, true
+		}
+		if !found {
+			match, found = f, true
+		}
+	}
+	return match, found
+}
+
+// apply decides what to do with a listed chirp: keep reports whether it
+// should be returned at all, and match flags it when it is kept.
+func (fs wordFilters) apply(authorID uuid.UUID, body string) (match FilterMatch, keep bool) {
+	f, ok := fs.check(authorID, body)
+	if !ok {
+		return FilterMatch{}, true
+	}
+	if f.hide {
+		return FilterMatch{}, false
+	}
+	return FilterMatch{Filtered: true, FilteredBy: f.phrase}, true
+}
+
+// wordFiltersFor loads the viewer's active muted words. Failures are logged
+// and the chirps are returned unfiltered rather than failing the read.
+func (h *APIHandler) wordFiltersFor(ctx context.Context, viewerID *uuid.UUID) wordFilters {
+	if viewerID == nil {
+		return wordFilters{}
+	}
+
+	rows, err := h.cfg.DB.GetActiveMutedWords(ctx, *viewerID)
+	if err != nil {
+		log.Printf("Error fetching muted words: %v", err)
+		return wordFilters{}
+	}
+
+	fs := wordFilters{viewerID: *viewerID, filters: make([]wordFilter, len(rows))}
+	for i, row := range rows {
+		fs.filters[i] = newWordFilter(row.Phrase, row.WholeWord, row.Action)
+	}
+	return fs
+}
+
+// wordFiltersForAll is wordFiltersFor for many viewers in one query.
+// Viewers without muted words are missing from the map, and the zero
+// wordFilters matches nothing.
+func (h *APIHandler) wordFiltersForAll(ctx context.Context, viewerIDs []uuid.UUID) map[uuid.UUID]wordFilters {
+	if len(viewerIDs) == 0 {
+		return nil
+	}
+
+	rows, err := h.cfg.DB.GetActiveMutedWordsForUsers(ctx, viewerIDs)
+	if err != nil {
+		log.Printf("Error fetching muted words: %v", err)
+		return nil
+	}
+
+	all := make(map[uuid.UUID]wordFilters)
+	for _, row := range rows {
+		fs := all[row.UserID]
+		fs.viewerID = row.UserID
+		fs.filters = append(fs.filters, newWordFilter(row.Phrase, row.WholeWord, row.Action))
+		all[row.UserID] = fs
+	}
+	return all
+}
+
+type mutedWordResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Phrase    string     `json:"phrase"`
+	WholeWord bool       `json:"whole_word"`
+	Action    string     `json:"action"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newMutedWordResponse(word database.MutedWord) mutedWordResponse {
+	resp := mutedWordResponse{
+		ID:        word.ID,
+		Phrase:    word.Phrase,
+		WholeWord: word.WholeWord,
+		Action:    word.Action,
+		CreatedAt: word.CreatedAt,
+	}
+	if word.ExpiresAt.Valid {
+		expiresAt := word.ExpiresAt.Time
+		resp.ExpiresAt = &expiresAt
+	}
+	return resp
+}
+
+// GetMutedWords lists the caller's muted words, including expired ones so
+// they can be renewed or removed.
+func (h *APIHandler) GetMutedWords(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	words, err := h.cfg.DB.GetMutedWords(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching muted words: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch muted words"})
+		return
+	}
+
+	items := make([]mutedWordResponse, len(words))
+	for i, word := range words {
+		items[i] = newMutedWordResponse(word)
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		MutedWords []mutedWordResponse `json:"muted_words"`
+	}{
+		MutedWords: items,
+	})
+}
+
+// CreateMutedWord adds a muted word, or replaces the settings of an existing
+// one with the same phrase.
+func (h *APIHandler) CreateMutedWord(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Phrase    string     `json:"phrase"`
+		WholeWord *bool      `json:"whole_word"`
+		Action    string     `json:"action"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid request body"})
+		return
+	}
+
+	phrase := strings.TrimSpace(req.Phrase)
+	if phrase == "" || len(tokenize(phrase)) == 0 {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "phrase is required"})
+		return
+	}
+	if len(phrase) > maxMutedWordLength {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "phrase is too long"})
+		return
+	}
+
+	action := req.Action
+	if action == "" {
+		action = mutedWordHide
+	}
+	if action != mutedWordHide && action != mutedWordFlag {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "action must be hide or flag"})
+		return
+	}
+
+	wholeWord := true
+	if req.WholeWord != nil {
+		wholeWord = *req.WholeWord
+	}
+
+	var expiresAt sql.NullTime
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			errJSON(w, http.StatusBadRequest, ErrMessage{Message: "expires_at must be in the future"})
+			return
+		}
+		expiresAt = sql.NullTime{Time: req.ExpiresAt.UTC(), Valid: true}
+	}
+
+	existing, err := h.cfg.DB.GetMutedWords(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching muted words: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to mute word"})
+		return
+	}
+	if len(existing) >= maxMutedWords {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Too many muted words"})
+		return
+	}
+
+	word, err := h.cfg.DB.CreateMutedWord(r.Context(), database.CreateMutedWordParams{
+		UserID:    userID,
+		Phrase:    phrase,
+		WholeWord: wholeWord,
+		Action:    action,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		log.Printf("Error creating muted word: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to mute word"})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, newMutedWordResponse(word))
+}
+
+func (h *APIHandler) DeleteMutedWord(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	wordID, err := uuid.Parse(r.PathValue("wordID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid muted word ID"})
+		return
+	}
+
+	deleted, err := h.cfg.DB.DeleteMutedWord(r.Context(), database.DeleteMutedWordParams{
+		ID:     wordID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Printf("Error deleting muted word: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to delete muted word"})
+		return
+	}
+
+	if deleted == 0 {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Muted word not found"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWordFilterMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		phrase    string
+		wholeWord bool
+		body      string
+		want      bool
+	}{
+		{name: "whole word", phrase: "golang", wholeWord: true, body: "I love golang", want: true},
+		{name: "case and punctuation", phrase: "Golang", wholeWord: true, body: "Is it \"GOLANG!\"?", want: true},
+		{name: "unicode punctuation", phrase: "golang", wholeWord: true, body: "«golang»…", want: true},
+		{name: "folded letters", phrase: "golang", wholeWord: true, body: "Gölang again", want: true},
+		{name: "whole word skips partial", phrase: "go", wholeWord: true, body: "going home", want: false},
+		{name: "substring", phrase: "go", wholeWord: false, body: "going home", want: true},
+		{name: "phrase", phrase: "world cup", wholeWord: true, body: "the World Cup, finally", want: true},
+		{name: "phrase out of order", phrase: "world cup", wholeWord: true, body: "cup of the world", want: false},
+		{name: "word matches hashtag", phrase: "spoilers", wholeWord: true, body: "no #spoilers please", want: true},
+		{name: "hashtag only matches hashtag", phrase: "#spoilers", wholeWord: true, body: "no spoilers please", want: false},
+		{name: "hashtag", phrase: "#spoilers", wholeWord: true, body: "#Spoilers ahead", want: true},
+		{name: "hashtag in punctuation", phrase: "#spoilers", wholeWord: true, body: "(#spoilers)", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newWordFilter(tt.phrase, tt.wholeWord, mutedWordHide)
+			if got := f.matches(tt.body, tokenize(tt.body)); got != tt.want {
+				t.Errorf("matches(%q) with filter %q = %v, want %v", tt.body, tt.phrase, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordFiltersApply(t *testing.T) {
+	viewer := uuid.New()
+	author := uuid.New()
+	fs := wordFilters{
+		viewerID: viewer,
+		filters: []wordFilter{
+			newWordFilter("spoilers", true, mutedWordFlag),
+			newWordFilter("finale", true, mutedWordHide),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		authorID uuid.UUID
+		body     string
+		wantKeep bool
+		wantFlag bool
+	}{
+		{name: "no match", authorID: author, body: "hello", wantKeep: true},
+		{name: "flagged", authorID: author, body: "spoilers below", wantKeep: true, wantFlag: true},
+		{name: "hidden", authorID: author, body: "what a finale", wantKeep: false},
+		{name: "hide wins over flag", authorID: author, body: "finale spoilers", wantKeep: false},
+		{name: "own chirps untouched", authorID: viewer, body: "finale spoilers", wantKeep: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, keep := fs.apply(tt.authorID, tt.body)
+			if keep != tt.wantKeep || match.Filtered != tt.wantFlag {
+				t.Errorf("apply(%q) = (%+v, %v), want filtered=%v keep=%v", tt.body, match, keep, tt.wantFlag, tt.wantKeep)
+			}
+		})
+	}
+}
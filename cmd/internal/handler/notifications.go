@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const (
+	notificationFollow        = "follow"
+	notificationFollowRequest = "follow_request"
+	notificationLike          = "like"
+	notificationReply         = "reply"
+	notificationRechirp       = "rechirp"
+	notificationQuote         = "quote"
+	notificationMention       = "mention"
+)
+
+var notificationTypes = []string{
+	notificationFollow,
+	notificationFollowRequest,
+	notificationLike,
+	notificationReply,
+	notificationRechirp,
+	notificationQuote,
+	notificationMention,
+}
+
+const defaultNotificationsLimit = 20
+const maxNotificationsLimit = 50
+
+// notify records that actor did something of the given kind to recipient.
+// It is the single write path for notifications: self-notifications,
+// duplicates and muted types are dropped by the query. Failures are logged
+// because the action that triggered the notification has already happened.
+func (h *APIHandler) notify(ctx context.Context, recipient, actor uuid.UUID, kind string, chirpID uuid.NullUUID) {
+	if recipient == actor {
+		return
+	}
+
+	err := h.cfg.DB.CreateNotification(ctx, database.CreateNotificationParams{
+		UserID:  recipient,
+		ActorID: actor,
+		Type:    kind,
+		ChirpID: chirpID,
+	})
+	if err != nil {
+		log.Printf("Error creating %s notification for %s: %v", kind, recipient, err)
+	}
+}
+
+func notificationVerb(kind string) string {
+	switch kind {
+	case notificationFollow:
+		return "followed you"
+	case notificationFollowRequest:
+		return "requested to follow you"
+	case notificationLike:
+		return "liked your chirp"
+	case notificationReply:
+		return "replied to your chirp"
+	case notificationRechirp:
+		return "rechirped your chirp"
+	case notificationQuote:
+		return "quoted your chirp"
+	case notificationMention:
+		return "mentioned you"
+	}
+	return kind
+}
+
+// summarizeNotification renders a grouped notification, e.g.
+// "@amy and 3 others followed you". recent holds the handles of the most
+// recent actors, newest first; total is the number of distinct actors.
+func summarizeNotification(kind string, recent []string, total int64) string {
+	verb := notificationVerb(kind)
+	if len(recent) == 0 {
+		return "Someone " + verb
+	}
+
+	switch {
+	case total <= 1:
+		return fmt.Sprintf("@%s %s", recent[0], verb)
+	case total == 2 && len(recent) >= 2:
+		return fmt.Sprintf("@%s and @%s %s", recent[0], recent[1], verb)
+	case total == 2:
+		return fmt.Sprintf("@%s and 1 other %s", recent[0], verb)
+	default:
+		return fmt.Sprintf("@%s and %d others %s", recent[0], total-1, verb)
+	}
+}
+
+func (h *APIHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	// Groups are paged by the ID of their newest notification, so the
+	// cursor is numeric rather than the usual UUID.
+	limit, _ := parsePageParams(r, defaultNotificationsLimit, maxNotificationsLimit)
+	var cursor sql.NullInt64
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		val, err := strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil {
+			errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid cursor"})
+			return
+		}
+		cursor = sql.NullInt64{Int64: val, Valid: true}
+	}
+
+	groups, err := h.cfg.DB.GetNotificationGroups(r.Context(), database.GetNotificationGroupsParams{
+		UserID:    userID,
+		Cursor:    cursor,
+		PageLimit: limit,
+	})
+	if err != nil {
+		log.Printf("Error fetching notifications: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch notifications"})
+		return
+	}
+
+	unread, err := h.cfg.DB.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error counting notifications: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch notifications"})
+		return
+	}
+
+	type NotificationGroup struct {
+		ID           int64      `json:"id"`
+		Type         string     `json:"type"`
+		ChirpID      *uuid.UUID `json:"chirp_id,omitempty"`
+		Summary      string     `json:"summary"`
+		ActorCount   int64      `json:"actor_count"`
+		RecentActors []string   `json:"recent_actors"`
+		Unread       bool       `json:"unread"`
+		CreatedAt    time.Time  `json:"created_at"`
+	}
+
+	items := make([]NotificationGroup, len(groups))
+	for i, g := range groups {
+		items[i] = NotificationGroup{
+			ID:           g.LatestID,
+			Type:         g.Type,
+			Summary:      summarizeNotification(g.Type, g.RecentActors, g.ActorCount),
+			ActorCount:   g.ActorCount,
+			RecentActors: g.RecentActors,
+			Unread:       g.UnreadCount > 0,
+			CreatedAt:    g.LatestAt,
+		}
+		if items[i].RecentActors == nil {
+			items[i].RecentActors = []string{}
+		}
+		if g.ChirpID.Valid {
+			chirpID := g.ChirpID.UUID
+			items[i].ChirpID = &chirpID
+		}
+	}
+
+	var nextCursor *string
+	if len(groups) == int(limit) {
+		last := strconv.FormatInt(groups[len(groups)-1].LatestID, 10)
+		nextCursor = &last
+	}
+
+	type NotificationsResponse struct {
+		Notifications []NotificationGroup `json:"notifications"`
+		UnreadCount   int64               `json:"unread_count"`
+		NextCursor    *string             `json:"next_cursor,omitempty"`
+	}
+
+	respondJSON(w, http.StatusOK, NotificationsResponse{
+		Notifications: items,
+		UnreadCount:   unread,
+		NextCursor:    nextCursor,
+	})
+}
+
+func (h *APIHandler) GetUnreadNotificationCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	unread, err := h.cfg.DB.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error counting notifications: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to count notifications"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		UnreadCount int64 `json:"unread_count"`
+	}{
+		UnreadCount: unread,
+	})
+}
+
+// MarkNotificationsRead marks notifications up to and including up_to_id as
+// read, or all of them when no ID is given.
+func (h *APIHandler) MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		UpToID *int64 `json:"up_to_id"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid request body"})
+			return
+		}
+	}
+
+	params := database.MarkNotificationsReadParams{UserID: userID}
+	if req.UpToID != nil {
+		params.UpToID = sql.NullInt64{Int64: *req.UpToID, Valid: true}
+	}
+
+	marked, err := h.cfg.DB.MarkNotificationsRead(r.Context(), params)
+	if err != nil {
+		log.Printf("Error marking notifications read: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to mark notifications read"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		Marked int64 `json:"marked"`
+	}{
+		Marked: marked,
+	})
+}
+
+type NotificationPreferences struct {
+	Muted []string `json:"muted"`
+}
+
+func (h *APIHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	muted, err := h.cfg.DB.GetNotificationMutes(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching notification mutes: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch preferences"})
+		return
+	}
+
+	if muted == nil {
+		muted = []string{}
+	}
+
+	respondJSON(w, http.StatusOK, NotificationPreferences{Muted: muted})
+}
+
+// UpdateNotificationPreferences replaces the set of muted notification types.
+func (h *APIHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid request body"})
+		return
+	}
+
+	muted := []string{}
+	for _, kind := range req.Muted {
+		if !slices.Contains(notificationTypes, kind) {
+			errJSON(w, http.StatusBadRequest, ErrMessage{
+				Message: "Unknown notification type " + kind + "; expected one of " + strings.Join(notificationTypes, ", "),
+			})
+			return
+		}
+		if !slices.Contains(muted, kind) {
+			muted = append(muted, kind)
+		}
+	}
+
+	err := h.cfg.DB.SetNotificationMutes(r.Context(), database.SetNotificationMutesParams{
+		UserID: userID,
+		Types:  muted,
+	})
+	if err != nil {
+		log.Printf("Error saving notification mutes: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to save preferences"})
+		return
+	}
+
+	slices.Sort(muted)
+	respondJSON(w, http.StatusOK, NotificationPreferences{Muted: muted})
+}
@@ -0,0 +1,30 @@
+package handler
+
+import "testing"
+
+func TestSummarizeNotification(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   string
+		recent []string
+		total  int64
+		want   string
+	}{
+		{name: "single follower", kind: notificationFollow, recent: []string{"amy"}, total: 1, want: "@amy followed you"},
+		{name: "two likes", kind: notificationLike, recent: []string{"amy", "bob"}, total: 2, want: "@amy and @bob liked your chirp"},
+		{name: "grouped followers", kind: notificationFollow, recent: []string{"amy", "bob", "cat"}, total: 4, want: "@amy and 3 others followed you"},
+		{name: "two with one actor known", kind: notificationReply, recent: []string{"amy"}, total: 2, want: "@amy and 1 other replied to your chirp"},
+		{name: "follow requests", kind: notificationFollowRequest, recent: []string{"amy", "bob"}, total: 3, want: "@amy and 2 others requested to follow you"},
+		{name: "mention", kind: notificationMention, recent: []string{"amy"}, total: 1, want: "@amy mentioned you"},
+		{name: "no actors", kind: notificationRechirp, recent: nil, total: 0, want: "Someone rechirped your chirp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarizeNotification(tt.kind, tt.recent, tt.total)
+			if got != tt.want {
+				t.Errorf("summarizeNotification() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const defaultFollowRequestsLimit = 20
+const maxFollowRequestsLimit = 100
+
+// canViewChirps reports whether viewerID (nil for anonymous requests) may
+// read chirps written by authorID. Chirps from private accounts are only
+// visible to the author and their followers.
+func (h *APIHandler) canViewChirps(ctx context.Context, authorID uuid.UUID, viewerID *uuid.UUID) (bool, error) {
+	return h.cfg.DB.CanViewChirps(ctx, database.CanViewChirpsParams{
+		AuthorID: authorID,
+		ViewerID: nullViewerID(viewerID),
+	})
+}
+
+// getVisibleChirp fetches a chirp the viewer is allowed to see. Hidden chirps
+// are reported as sql.ErrNoRows so callers answer 404 and do not reveal that
+// they exist.
+func (h *APIHandler) getVisibleChirp(ctx context.Context, chirpID uuid.UUID, viewerID *uuid.UUID) (database.Chirp, error) {
+	chirp, err := h.cfg.DB.GetChirp(ctx, chirpID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	visible, err := h.canViewChirps(ctx, chirp.UserID, viewerID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	if !visible {
+		return database.Chirp{}, sql.ErrNoRows
+	}
+
+	return chirp, nil
+}
+
+// UpdateMyPrivacy switches the caller's account between public and private.
+// Going public accepts every pending follow request.
+func (h *APIHandler) UpdateMyPrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		IsPrivate *bool `json:"is_private"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IsPrivate == nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "is_private is required"})
+		return
+	}
+
+	err := h.cfg.DB.SetUserPrivacy(r.Context(), database.SetUserPrivacyParams{
+		IsPrivate: *req.IsPrivate,
+		ID:        userID,
+	})
+	if err != nil {
+		log.Printf("Error updating privacy: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to update privacy"})
+		return
+	}
+
+	var accepted int
+	if !*req.IsPrivate {
+		followerIDs, err := h.cfg.DB.AcceptAllFollowRequests(r.Context(), userID)
+		if err != nil {
+			log.Printf("Error accepting follow requests: %v", err)
+			errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to accept pending follow requests"})
+			return
+		}
+
+		for _, followerID := range followerIDs {
+			h.cfg.Timeline.Backfill(followerID, userID)
+			h.publishFollowerAdded(followerID, userID)
+		}
+		accepted = len(followerIDs)
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		IsPrivate        bool `json:"is_private"`
+		AcceptedRequests int  `json:"accepted_requests"`
+	}{
+		IsPrivate:        *req.IsPrivate,
+		AcceptedRequests: accepted,
+	})
+}
+
+// GetFollowRequests lists pending requests to follow the caller, newest
+// first.
+func (h *APIHandler) GetFollowRequests(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := parsePageLimit(r, defaultFollowRequestsLimit, maxFollowRequestsLimit)
+	cursor, err := parseCursorParam(r, "cursor")
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid cursor"})
+		return
+	}
+
+	params := database.GetIncomingFollowRequestsParams{
+		TargetID:  userID,
+		PageLimit: limit,
+	}
+	params.CursorAt, params.CursorID = cursorArgs(cursor)
+
+	requests, err := h.cfg.DB.GetIncomingFollowRequests(r.Context(), params)
+	if err != nil {
+		log.Printf("Error fetching follow requests: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch follow requests"})
+		return
+	}
+
+	type FollowRequest struct {
+		ID          uuid.UUID `json:"id"`
+		Handle      string    `json:"handle"`
+		DisplayName string    `json:"display_name"`
+		AvatarURL   string    `json:"avatar_url,omitempty"`
+		RequestedAt time.Time `json:"requested_at"`
+	}
+
+	items := make([]FollowRequest, len(requests))
+	for i, req := range requests {
+		items[i] = FollowRequest{
+			ID:          req.ID,
+			Handle:      req.Handle,
+			DisplayName: req.DisplayName,
+			RequestedAt: req.RequestedAt,
+		}
+		if req.AvatarKey != "" {
+			items[i].AvatarURL = h.cfg.Blobs.URL(req.AvatarKey)
+		}
+	}
+
+	var nextCursor *string
+	if len(requests) == int(limit) {
+		last := requests[len(requests)-1]
+		next := pageCursor{At: last.RequestedAt, ID: last.ID}.String()
+		nextCursor = &next
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		Requests   []FollowRequest `json:"requests"`
+		NextCursor *string         `json:"next_cursor,omitempty"`
+	}{
+		Requests:   items,
+		NextCursor: nextCursor,
+	})
+}
+
+func (h *APIHandler) AcceptFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	requesterID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID format"})
+		return
+	}
+
+	accepted, err := h.cfg.DB.AcceptFollowRequest(r.Context(), database.AcceptFollowRequestParams{
+		RequesterID: requesterID,
+		TargetID:    userID,
+	})
+	if err != nil {
+		log.Printf("Error accepting follow request: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to accept follow request"})
+		return
+	}
+
+	if accepted == 0 {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Follow request not found"})
+		return
+	}
+
+	h.cfg.Timeline.Backfill(requesterID, userID)
+	h.publishFollowerAdded(requesterID, userID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) RejectFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	requesterID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID format"})
+		return
+	}
+
+	deleted, err := h.cfg.DB.DeleteFollowRequest(r.Context(), database.DeleteFollowRequestParams{
+		RequesterID: requesterID,
+		TargetID:    userID,
+	})
+	if err != nil {
+		log.Printf("Error rejecting follow request: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to reject follow request"})
+		return
+	}
+
+	if deleted == 0 {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Follow request not found"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,124 +1,207 @@
-package handler
-
-import (
-	"log"
-	"net/http"
-	"strconv"
-
-	"github.com/google/uuid"
-	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
-	"github.com/shubh-man007/Chirpy/cmd/internal/database"
-)
-
-const defaultProfileChirpsLimit = 20
-const maxProfileChirpsLimit = 100
-
-func (h *APIHandler) buildProfileResponse(w http.ResponseWriter, r *http.Request, userID uuid.UUID, viewerID *uuid.UUID) {
-	limit := int32(defaultProfileChirpsLimit)
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
-			limit = int32(val)
-			if limit > maxProfileChirpsLimit {
-				limit = maxProfileChirpsLimit
-			}
-		}
-	}
-
-	var cursor uuid.NullUUID
-	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
-		if cursorID, err := uuid.Parse(cursorStr); err == nil {
-			cursor = uuid.NullUUID{UUID: cursorID, Valid: true}
-		}
-	}
-
-	userStats, err := h.cfg.DB.GetUserProfile(r.Context(), userID)
-	if err != nil {
-		log.Printf("Error fetching profile: %v", err)
-		errJSON(w, http.StatusNotFound, ErrMessage{Message: "User not found"})
-		return
-	}
-
-	chirps, err := h.cfg.DB.GetUserChirpsPaginated(r.Context(), database.GetUserChirpsPaginatedParams{
-		UserID:    userID,
-		Cursor:    cursor,
-		PageLimit: limit,
-	})
-	if err != nil {
-		log.Printf("Error fetching chirps: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch chirps"})
-		return
-	}
-
-	chirpItems := make([]ChirpItem, len(chirps))
-	for i, c := range chirps {
-		chirpItems[i] = ChirpItem{
-			ID:        c.ID,
-			Body:      c.Body,
-			CreatedAt: c.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
-	}
-
-	var nextCursor *string
-	if len(chirps) == int(limit) {
-		lastID := chirps[len(chirps)-1].ID.String()
-		nextCursor = &lastID
-	}
-
-	profile := ProfileResponse{
-		ID:             userStats.ID,
-		Email:          userStats.Email,
-		CreatedAt:      userStats.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:      userStats.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		IsChirpyRed:    userStats.IsChirpyRed,
-		FollowersCount: userStats.FollowersCount,
-		FollowingCount: userStats.FollowingCount,
-		ChirpsCount:    userStats.ChirpsCount,
-		Chirps:         chirpItems,
-		NextCursor:     nextCursor,
-	}
-
-	if viewerID != nil && *viewerID != userID {
-		following, err := h.cfg.DB.IsFollowing(r.Context(), database.IsFollowingParams{
-			FollowerID: *viewerID,
-			FolloweeID: userID,
-		})
-		if err == nil {
-			profile.IsFollowing = &following
-		}
-	}
-
-	respondJSON(w, http.StatusOK, profile)
-}
-
-func (h *APIHandler) GetMyProfile(w http.ResponseWriter, r *http.Request) {
-	token, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	userID, err := auth.ValidateJWT(token, h.cfg.JWTSecret)
-	if err != nil {
-		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
-		return
-	}
-
-	h.buildProfileResponse(w, r, userID, nil)
-}
-
-func (h *APIHandler) GetProfileByUserID(w http.ResponseWriter, r *http.Request) {
-	userID, err := uuid.Parse(r.PathValue("userID"))
-	if err != nil {
-		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID"})
-		return
-	}
-
-	var viewerID *uuid.UUID
-	if token, err := auth.GetBearerToken(r.Header); err == nil {
-		if id, err := auth.ValidateJWT(token, h.cfg.JWTSecret); err == nil {
-			viewerID = &id
-		}
-	}
-
-	h.buildProfileResponse(w, r, userID, viewerID)
-}
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+	"github.com/shubh-man007/Chirpy/cmd/internal/middleware"
+)
+
+const defaultProfileChirpsLimit = 20
+const maxProfileChirpsLimit = 100
+
+// parsePageParams reads the "limit" and "cursor" query parameters used by
+// keyset-paginated endpoints. Invalid values fall back to the defaults.
+func parsePageParams(r *http.Request, defaultLimit, maxLimit int32) (int32, uuid.NullUUID) {
+	limit := parsePageLimit(r, defaultLimit, maxLimit)
+
+	var cursor uuid.NullUUID
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		if cursorID, err := uuid.Parse(cursorStr); err == nil {
+			cursor = uuid.NullUUID{UUID: cursorID, Valid: true}
+		}
+	}
+
+	return limit, cursor
+}
+
+// parsePageLimit reads the "limit" query parameter, capped at maxLimit.
+func parsePageLimit(r *http.Request, defaultLimit, maxLimit int32) int32 {
+	limit := defaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if val, err := strconv.Atoi(limitStr); err == nil && val > 0 {
+			limit = int32(val)
+			if limit > maxLimit {
+				limit = maxLimit
+			}
+		}
+	}
+	return limit
+}
+
+// optionalViewerID returns the caller's ID on routes wrapped in
+// Authenticator.Optional, and nil for anonymous requests.
+func (h *APIHandler) optionalViewerID(r *http.Request) *uuid.UUID {
+	id, ok := middleware.IdentityFrom(r.Context())
+	if !ok {
+		return nil
+	}
+
+	return &id.UserID
+}
+
+func nullViewerID(viewerID *uuid.UUID) uuid.NullUUID {
+	if viewerID == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *viewerID, Valid: true}
+}
+
+func (h *APIHandler) buildProfileResponse(w http.ResponseWriter, r *http.Request, userID uuid.UUID, viewerID *uuid.UUID) {
+	limit, cursor := parsePageParams(r, defaultProfileChirpsLimit, maxProfileChirpsLimit)
+
+	userStats, err := h.cfg.DB.GetUserProfile(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching profile: %v", err)
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "User not found"})
+		return
+	}
+
+	visible, err := h.canViewChirps(r.Context(), userID, viewerID)
+	if err != nil {
+		log.Printf("Error checking chirp visibility: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch chirps"})
+		return
+	}
+
+	var chirps []database.GetUserChirpsPaginatedRow
+	if visible {
+		chirps, err = h.cfg.DB.GetUserChirpsPaginated(r.Context(), database.GetUserChirpsPaginatedParams{
+			ViewerID:  nullViewerID(viewerID),
+			UserID:    userID,
+			Cursor:    cursor,
+			PageLimit: limit,
+		})
+		if err != nil {
+			log.Printf("Error fetching chirps: %v", err)
+			errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch chirps"})
+			return
+		}
+	}
+
+	ids := make([]uuid.UUID, len(chirps))
+	for i, c := range chirps {
+		ids[i] = c.ID
+	}
+	resolved := h.resolvedMentions(r.Context(), ids)
+
+	filters := h.wordFiltersFor(r.Context(), viewerID)
+	chirpItems := make([]ChirpItem, 0, len(chirps))
+	for _, c := range chirps {
+		match, keep := filters.apply(c.UserID, c.Body)
+		if !keep {
+			continue
+		}
+
+		item := ChirpItem{
+			ID:            c.ID,
+			Body:          c.Body,
+			CreatedAt:     c.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			EditCount:     c.EditCount,
+			Edited:        c.Edited,
+			Kind:          c.Kind,
+			ReplyCount:    c.ReplyCount,
+			LikeCount:     c.LikeCount,
+			LikedByViewer: c.LikedByViewer,
+			FilterMatch:   match,
+			Entities:      buildChirpEntities(c.Body, resolved[c.ID]),
+		}
+		if c.ParentID.Valid {
+			parentID := c.ParentID.UUID
+			item.ParentID = &parentID
+		}
+		if c.OriginalID.Valid {
+			originalID := c.OriginalID.UUID
+			item.OriginalID = &originalID
+		}
+		chirpItems = append(chirpItems, item)
+	}
+
+	var nextCursor *string
+	if len(chirps) == int(limit) {
+		lastID := chirps[len(chirps)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	profile := ProfileResponse{
+		ID:             userStats.ID,
+		Handle:         userStats.Handle,
+		DisplayName:    userStats.DisplayName,
+		Bio:            userStats.Bio,
+		Location:       userStats.Location,
+		Website:        userStats.Website,
+		CreatedAt:      userStats.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:      userStats.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		IsChirpyRed:    userStats.IsChirpyRed,
+		IsPrivate:      userStats.IsPrivate,
+		FollowersCount: userStats.FollowersCount,
+		FollowingCount: userStats.FollowingCount,
+		ChirpsCount:    userStats.ChirpsCount,
+		Chirps:         chirpItems,
+		NextCursor:     nextCursor,
+		ChirpsHidden:   !visible,
+	}
+
+	if userStats.AvatarKey != "" {
+		profile.AvatarURL = h.cfg.Blobs.URL(userStats.AvatarKey)
+	}
+
+	// Email addresses are only ever shown to the account owner.
+	if viewerID != nil && *viewerID == userID {
+		profile.Email = userStats.Email
+	}
+
+	if viewerID != nil && *viewerID != userID {
+		following, err := h.cfg.DB.IsFollowing(r.Context(), database.IsFollowingParams{
+			FollowerID: *viewerID,
+			FolloweeID: userID,
+		})
+		if err == nil {
+			profile.IsFollowing = &following
+		}
+	}
+
+	respondJSON(w, http.StatusOK, profile)
+}
+
+func (h *APIHandler) GetMyProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	h.buildProfileResponse(w, r, userID, &userID)
+}
+
+func (h *APIHandler) GetProfileByUserID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid user ID"})
+		return
+	}
+
+	h.buildProfileResponse(w, r, userID, h.optionalViewerID(r))
+}
+
+func (h *APIHandler) GetProfileByHandle(w http.ResponseWriter, r *http.Request) {
+	user, err := h.cfg.DB.GetUserByHandle(r.Context(), r.PathValue("handle"))
+	if err != nil {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "User not found"})
+		return
+	}
+
+	h.buildProfileResponse(w, r, user.ID, h.optionalViewerID(r))
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+	"github.com/shubh-man007/Chirpy/cmd/internal/middleware"
+)
+
+const (
+	maxDisplayNameLength = 50
+	maxBioLength         = 160
+	maxLocationLength    = 30
+	maxWebsiteLength     = 100
+
+	maxAvatarSize = 2 << 20 // 2 MiB
+)
+
+var avatarExtensions = map[string]string{
+	"image/gif":  ".gif",
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// normalizeProfileField trims surrounding whitespace and enforces a maximum
+// length in characters.
+func normalizeProfileField(name, value string, maxLength int) (string, error) {
+	value = strings.TrimSpace(value)
+	if utf8.RuneCountInString(value) > maxLength {
+		return "", fmt.Errorf("%s must be at most %d characters", name, maxLength)
+	}
+	return value, nil
+}
+
+// validateWebsite accepts an empty string (clearing the field) or an
+// absolute http(s) URL with a host.
+func validateWebsite(website string) error {
+	if website == "" {
+		return nil
+	}
+
+	u, err := url.Parse(website)
+	if err != nil {
+		return errors.New("website must be a valid URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("website must start with http:// or https://")
+	}
+	if u.Host == "" || u.User != nil {
+		return errors.New("website must be a valid URL")
+	}
+	return nil
+}
+
+// validateProfileUpdate normalizes every provided field of req in place.
+func validateProfileUpdate(req *ProfileUpdate) error {
+	fields := []struct {
+		name      string
+		value     *string
+		maxLength int
+	}{
+		{"display_name", req.DisplayName, maxDisplayNameLength},
+		{"bio", req.Bio, maxBioLength},
+		{"location", req.Location, maxLocationLength},
+		{"website", req.Website, maxWebsiteLength},
+	}
+
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		value, err := normalizeProfileField(f.name, *f.value, f.maxLength)
+		if err != nil {
+			return err
+		}
+		*f.value = value
+	}
+
+	if req.Website != nil {
+		return validateWebsite(*req.Website)
+	}
+	return nil
+}
+
+func toNullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+// authenticatedUserID returns the caller stored by Authenticator.Required.
+func (h *APIHandler) authenticatedUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, ok := middleware.IdentityFrom(r.Context())
+	if !ok {
+		// The route is missing Authenticator.Required.
+		middleware.WriteAuthError(w, middleware.ErrMissingToken)
+		return uuid.Nil, false
+	}
+
+	return id.UserID, true
+}
+
+func (h *APIHandler) UpdateMyProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req ProfileUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid request body"})
+		return
+	}
+
+	if err := validateProfileUpdate(&req); err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: err.Error()})
+		return
+	}
+
+	err := h.cfg.DB.UpdateUserProfile(r.Context(), database.UpdateUserProfileParams{
+		DisplayName: toNullString(req.DisplayName),
+		Bio:         toNullString(req.Bio),
+		Location:    toNullString(req.Location),
+		Website:     toNullString(req.Website),
+		ID:          userID,
+	})
+	if err != nil {
+		log.Printf("Error updating profile: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to update profile"})
+		return
+	}
+
+	h.buildProfileResponse(w, r, userID, &userID)
+}
+
+// UploadAvatar accepts a multipart form with the image in the "avatar" field.
+func (h *APIHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAvatarSize+1<<10)
+	file, _, err := r.FormFile("avatar")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			errJSON(w, http.StatusRequestEntityTooLarge, ErrMessage{Message: "Avatar is too large"})
+			return
+		}
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Missing avatar file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxAvatarSize+1))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Could not read avatar"})
+		return
+	}
+	if len(data) > maxAvatarSize {
+		errJSON(w, http.StatusRequestEntityTooLarge, ErrMessage{Message: "Avatar is too large"})
+		return
+	}
+
+	// Trust the file contents, not the client supplied Content-Type.
+	ext, ok := avatarExtensions[http.DetectContentType(data)]
+	if !ok {
+		errJSON(w, http.StatusUnsupportedMediaType, ErrMessage{Message: "Avatar must be a PNG, JPEG, GIF or WebP image"})
+		return
+	}
+
+	// A fresh key per upload keeps cached copies of the old avatar from
+	// being served after a change.
+	key := "avatars/" + userID.String() + "-" + strings.ReplaceAll(uuid.NewString(), "-", "")[:8] + ext
+	if err := h.cfg.Blobs.Put(r.Context(), key, bytes.NewReader(data)); err != nil {
+		log.Printf("Error storing avatar: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to store avatar"})
+		return
+	}
+
+	previous, err := h.cfg.DB.SetUserAvatar(r.Context(), database.SetUserAvatarParams{
+		AvatarKey: key,
+		ID:        userID,
+	})
+	if err != nil {
+		log.Printf("Error saving avatar: %v", err)
+		if delErr := h.cfg.Blobs.Delete(r.Context(), key); delErr != nil {
+			log.Printf("Error removing orphaned avatar: %v", delErr)
+		}
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to update avatar"})
+		return
+	}
+
+	h.removeAvatar(r, previous)
+
+	respondJSON(w, http.StatusOK, struct {
+		AvatarURL string `json:"avatar_url"`
+	}{
+		AvatarURL: h.cfg.Blobs.URL(key),
+	})
+}
+
+func (h *APIHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	previous, err := h.cfg.DB.SetUserAvatar(r.Context(), database.SetUserAvatarParams{
+		AvatarKey: "",
+		ID:        userID,
+	})
+	if err != nil {
+		log.Printf("Error clearing avatar: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to remove avatar"})
+		return
+	}
+
+	h.removeAvatar(r, previous)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) removeAvatar(r *http.Request, key string) {
+	if key == "" {
+		return
+	}
+	if err := h.cfg.Blobs.Delete(r.Context(), key); err != nil {
+		log.Printf("Error removing old avatar %s: %v", key, err)
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+// getOriginalChirp fetches a chirp, following a rechirp through to the chirp
+// it reposts so that replies, quotes and rechirps always target the original.
+// Chirps the viewer may not see are reported as sql.ErrNoRows.
+func (h *APIHandler) getOriginalChirp(ctx context.Context, chirpID, viewerID uuid.UUID) (database.Chirp, error) {
+	chirp, err := h.getVisibleChirp(ctx, chirpID, &viewerID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	if chirp.Kind != chirpKindRechirp {
+		return chirp, nil
+	}
+
+	if !chirp.OriginalID.Valid {
+		return database.Chirp{}, sql.ErrNoRows
+	}
+
+	return h.getVisibleChirp(ctx, chirp.OriginalID.UUID, &viewerID)
+}
+
+func (h *APIHandler) Rechirp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
+		return
+	}
+
+	original, err := h.getOriginalChirp(r.Context(), chirpID, userID)
+	if err != nil {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp not found"})
+		return
+	}
+
+	// Rechirps would carry a private chirp to the rechirper's followers.
+	private, err := h.cfg.DB.GetUserPrivacy(r.Context(), original.UserID)
+	if err != nil {
+		log.Printf("Error fetching author privacy: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to rechirp"})
+		return
+	}
+	if private && original.UserID != userID {
+		errJSON(w, http.StatusForbidden, ErrMessage{Message: "Chirps from private accounts cannot be rechirped"})
+		return
+	}
+
+	rechirp, err := h.cfg.DB.CreateRechirp(r.Context(), database.CreateRechirpParams{
+		UserID:     userID,
+		OriginalID: uuid.NullUUID{UUID: original.ID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			errJSON(w, http.StatusConflict, ErrMessage{Message: "Already rechirped"})
+			return
+		}
+		log.Printf("Error rechirping: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to rechirp"})
+		return
+	}
+
+	h.notify(r.Context(), original.UserID, userID, notificationRechirp, uuid.NullUUID{UUID: original.ID, Valid: true})
+	h.cfg.Timeline.FanOut(rechirp.ID)
+	h.publishChirpEvent(r.Context(), streamChirpCreated, userID, rechirp, streamContent{
+		authorID: original.UserID,
+		body:     original.Body,
+	})
+
+	respondJSON(w, http.StatusCreated, rechirp)
+}
+
+func (h *APIHandler) UndoRechirp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
+		return
+	}
+
+	original, err := h.getOriginalChirp(r.Context(), chirpID, userID)
+	if err != nil {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp not found"})
+		return
+	}
+
+	deleted, err := h.cfg.DB.DeleteRechirp(r.Context(), database.DeleteRechirpParams{
+		UserID:     userID,
+		OriginalID: uuid.NullUUID{UUID: original.ID, Valid: true},
+	})
+	if err != nil {
+		log.Printf("Error undoing rechirp: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to undo rechirp"})
+		return
+	}
+
+	if deleted == 0 {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Rechirp not found"})
+		return
+	}
+
+	// Timeline rows for the rechirp cascade away with it; re-deliver the
+	// original to readers who still follow its author.
+	h.cfg.Timeline.FanOut(original.ID)
+
+	h.publishChirpEvent(r.Context(), streamRechirpDeleted, userID, struct {
+		OriginalID  uuid.UUID `json:"original_id"`
+		RechirpedBy uuid.UUID `json:"rechirped_by"`
+	}{
+		OriginalID:  original.ID,
+		RechirpedBy: userID,
+	}, streamContent{related: []uuid.UUID{original.UserID}})
+
+	w.WriteHeader(http.StatusNoContent)
+}
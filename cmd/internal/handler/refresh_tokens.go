@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const refreshTokenTTL = 60 * 24 * time.Hour
+
+var (
+	errRefreshTokenInvalid = errors.New("refresh token is unknown or expired")
+	errRefreshTokenReused  = errors.New("revoked refresh token presented again")
+)
+
+// refreshTokenStore is the part of *database.Queries used to issue and
+// rotate refresh tokens.
+type refreshTokenStore interface {
+	CreateSession(ctx context.Context, arg database.CreateSessionParams) (database.RefreshToken, error)
+	GetRefreshToken(ctx context.Context, tokenHash string) (database.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, arg database.RotateRefreshTokenParams) (database.RefreshToken, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) (int64, error)
+}
+
+// issueRefreshToken starts a new session, and with it a new token family,
+// for userID. Only the hash of the returned token is stored.
+func issueRefreshToken(ctx context.Context, store refreshTokenStore, userID uuid.UUID, device sessionDevice) (string, error) {
+	token, err := auth.MakeRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = store.CreateSession(ctx, database.CreateSessionParams{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      sql.NullString{String: device.Name, Valid: device.Name != ""},
+		UserAgent: device.UserAgent,
+		IpAddress: device.IPAddress,
+		TokenHash: auth.HashRefreshToken(token),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// rotateRefreshToken revokes presented and returns its successor in the same
+// family. A revoked token can only be presented again if it leaked, so doing
+// so revokes the whole family and logs out whoever holds the latest token.
+func rotateRefreshToken(ctx context.Context, store refreshTokenStore, presented string, device sessionDevice) (string, database.RefreshToken, error) {
+	hash := auth.HashRefreshToken(presented)
+
+	current, err := store.GetRefreshToken(ctx, hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", database.RefreshToken{}, errRefreshTokenInvalid
+	}
+	if err != nil {
+		return "", database.RefreshToken{}, err
+	}
+
+	if current.RevokedAt.Valid {
+		return "", database.RefreshToken{}, revokeRefreshTokenFamily(ctx, store, current)
+	}
+
+	token, err := auth.MakeRefreshToken()
+	if err != nil {
+		return "", database.RefreshToken{}, err
+	}
+
+	next, err := store.RotateRefreshToken(ctx, database.RotateRefreshTokenParams{
+		ParentHash: hash,
+		UserAgent:  device.UserAgent,
+		IpAddress:  device.IPAddress,
+		TokenHash:  auth.HashRefreshToken(token),
+		ExpiresAt:  time.Now().Add(refreshTokenTTL),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Either the token expired or another request revoked it after we
+		// read it; only the latter is reuse.
+		current, err = store.GetRefreshToken(ctx, hash)
+		if err == nil && current.RevokedAt.Valid {
+			return "", database.RefreshToken{}, revokeRefreshTokenFamily(ctx, store, current)
+		}
+		return "", database.RefreshToken{}, errRefreshTokenInvalid
+	}
+	if err != nil {
+		return "", database.RefreshToken{}, err
+	}
+
+	return token, next, nil
+}
+
+func revokeRefreshTokenFamily(ctx context.Context, store refreshTokenStore, token database.RefreshToken) error {
+	revoked, err := store.RevokeRefreshTokenFamily(ctx, token.FamilyID)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Refresh token reuse for user %v: revoked %d tokens in family %v", token.UserID, revoked, token.FamilyID)
+	return errRefreshTokenReused
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+// memTokenStore mirrors the refresh token queries in memory.
+type memTokenStore struct {
+	tokens map[string]database.RefreshToken
+	// beforeRotate runs inside RotateRefreshToken to simulate a concurrent
+	// request landing between the read and the rotation.
+	beforeRotate func()
+}
+
+func newMemTokenStore() *memTokenStore {
+	return &memTokenStore{tokens: map[string]database.RefreshToken{}}
+}
+
+func (s *memTokenStore) CreateSession(_ context.Context, arg database.CreateSessionParams) (database.RefreshToken, error) {
+	t := database.RefreshToken{
+		TokenHash: arg.TokenHash,
+		UserID:    arg.UserID,
+		ExpiresAt: arg.ExpiresAt,
+		FamilyID:  arg.ID,
+	}
+	s.tokens[t.TokenHash] = t
+	return t, nil
+}
+
+func (s *memTokenStore) GetRefreshToken(_ context.Context, tokenHash string) (database.RefreshToken, error) {
+	t, ok := s.tokens[tokenHash]
+	if !ok {
+		return database.RefreshToken{}, sql.ErrNoRows
+	}
+	return t, nil
+}
+
+func (s *memTokenStore) RotateRefreshToken(_ context.Context, arg database.RotateRefreshTokenParams) (database.RefreshToken, error) {
+	if s.beforeRotate != nil {
+		s.beforeRotate()
+	}
+
+	parent, ok := s.tokens[arg.ParentHash]
+	if !ok || parent.RevokedAt.Valid || !parent.ExpiresAt.After(time.Now()) {
+		return database.RefreshToken{}, sql.ErrNoRows
+	}
+	parent.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	s.tokens[parent.TokenHash] = parent
+
+	t := database.RefreshToken{
+		TokenHash:  arg.TokenHash,
+		UserID:     parent.UserID,
+		ExpiresAt:  arg.ExpiresAt,
+		FamilyID:   parent.FamilyID,
+		ParentHash: sql.NullString{String: parent.TokenHash, Valid: true},
+	}
+	s.tokens[t.TokenHash] = t
+	return t, nil
+}
+
+func (s *memTokenStore) RevokeRefreshTokenFamily(_ context.Context, familyID uuid.UUID) (int64, error) {
+	var n int64
+	for hash, t := range s.tokens {
+		if t.FamilyID == familyID && !t.RevokedAt.Valid {
+			t.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			s.tokens[hash] = t
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *memTokenStore) revoke(token string) {
+	t := s.tokens[auth.HashRefreshToken(token)]
+	t.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	s.tokens[t.TokenHash] = t
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	store := newMemTokenStore()
+	first, err := issueRefreshToken(ctx, store, userID, sessionDevice{})
+	if err != nil {
+		t.Fatalf("issueRefreshToken: %v", err)
+	}
+	if _, ok := store.tokens[first]; ok {
+		t.Fatal("refresh token stored in plaintext")
+	}
+
+	second, next, err := rotateRefreshToken(ctx, store, first, sessionDevice{})
+	if err != nil {
+		t.Fatalf("rotateRefreshToken: %v", err)
+	}
+	if second == first {
+		t.Error("rotation returned the presented token")
+	}
+	if next.UserID != userID {
+		t.Errorf("user = %v, want %v", next.UserID, userID)
+	}
+	if next.ParentHash.String != auth.HashRefreshToken(first) {
+		t.Error("rotated token is not linked to its predecessor")
+	}
+	if next.FamilyID != store.tokens[auth.HashRefreshToken(first)].FamilyID {
+		t.Error("rotated token left its family")
+	}
+	if !store.tokens[auth.HashRefreshToken(first)].RevokedAt.Valid {
+		t.Error("presented token was not revoked")
+	}
+
+	if _, _, err := rotateRefreshToken(ctx, store, second, sessionDevice{}); err != nil {
+		t.Errorf("rotating the successor: %v", err)
+	}
+}
+
+func TestRotateRefreshTokenReuse(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// attack replays stolen and returns the newest token issued from
+		// it, whether the client or the attacker holds it.
+		attack func(t *testing.T, store *memTokenStore, stolen string) (held string)
+	}{
+		{
+			name: "attacker replays after client rotated",
+			attack: func(t *testing.T, store *memTokenStore, stolen string) string {
+				held, _, err := rotateRefreshToken(ctx, store, stolen, sessionDevice{})
+				if err != nil {
+					t.Fatalf("client rotation: %v", err)
+				}
+				if _, _, err := rotateRefreshToken(ctx, store, stolen, sessionDevice{}); !errors.Is(err, errRefreshTokenReused) {
+					t.Errorf("replay err = %v, want %v", err, errRefreshTokenReused)
+				}
+				return held
+			},
+		},
+		{
+			name: "replay of an older generation",
+			attack: func(t *testing.T, store *memTokenStore, stolen string) string {
+				held := stolen
+				for range 3 {
+					var err error
+					held, _, err = rotateRefreshToken(ctx, store, held, sessionDevice{})
+					if err != nil {
+						t.Fatalf("client rotation: %v", err)
+					}
+				}
+				if _, _, err := rotateRefreshToken(ctx, store, stolen, sessionDevice{}); !errors.Is(err, errRefreshTokenReused) {
+					t.Errorf("replay err = %v, want %v", err, errRefreshTokenReused)
+				}
+				return held
+			},
+		},
+		{
+			name: "concurrent refresh with the same token",
+			attack: func(t *testing.T, store *memTokenStore, stolen string) string {
+				var held string
+				store.beforeRotate = func() {
+					store.beforeRotate = nil
+					var err error
+					held, _, err = rotateRefreshToken(ctx, store, stolen, sessionDevice{})
+					if err != nil {
+						t.Fatalf("concurrent rotation: %v", err)
+					}
+				}
+				if _, _, err := rotateRefreshToken(ctx, store, stolen, sessionDevice{}); !errors.Is(err, errRefreshTokenReused) {
+					t.Errorf("losing request err = %v, want %v", err, errRefreshTokenReused)
+				}
+				return held
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemTokenStore()
+
+			stolen, err := issueRefreshToken(ctx, store, uuid.New(), sessionDevice{})
+			if err != nil {
+				t.Fatalf("issueRefreshToken: %v", err)
+			}
+			bystander, err := issueRefreshToken(ctx, store, uuid.New(), sessionDevice{})
+			if err != nil {
+				t.Fatalf("issueRefreshToken: %v", err)
+			}
+
+			held := tt.attack(t, store, stolen)
+
+			for hash, tok := range store.tokens {
+				if tok.FamilyID == store.tokens[auth.HashRefreshToken(stolen)].FamilyID && !tok.RevokedAt.Valid {
+					t.Errorf("token %s in the compromised family is still active", hash)
+				}
+			}
+			if _, _, err := rotateRefreshToken(ctx, store, held, sessionDevice{}); !errors.Is(err, errRefreshTokenReused) {
+				t.Errorf("latest token err = %v, want %v", err, errRefreshTokenReused)
+			}
+			if _, _, err := rotateRefreshToken(ctx, store, bystander, sessionDevice{}); err != nil {
+				t.Errorf("unrelated family was affected: %v", err)
+			}
+		})
+	}
+}
+
+func TestRotateRefreshTokenInvalid(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		setup func(store *memTokenStore) string
+		want  error
+	}{
+		{
+			name:  "unknown token",
+			setup: func(*memTokenStore) string { return "not-a-token" },
+			want:  errRefreshTokenInvalid,
+		},
+		{
+			name: "expired token",
+			setup: func(store *memTokenStore) string {
+				token, _ := issueRefreshToken(ctx, store, uuid.New(), sessionDevice{})
+				hash := auth.HashRefreshToken(token)
+				expired := store.tokens[hash]
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				store.tokens[hash] = expired
+				return token
+			},
+			want: errRefreshTokenInvalid,
+		},
+		{
+			name: "logged out token",
+			setup: func(store *memTokenStore) string {
+				token, _ := issueRefreshToken(ctx, store, uuid.New(), sessionDevice{})
+				store.revoke(token)
+				return token
+			},
+			want: errRefreshTokenReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemTokenStore()
+			token := tt.setup(store)
+
+			if _, _, err := rotateRefreshToken(ctx, store, token, sessionDevice{}); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
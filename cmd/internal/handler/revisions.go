@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const defaultRevisionsLimit = 20
+const maxRevisionsLimit = 100
+
+// GetChirpHistory returns a chirp's current body followed by the bodies it
+// had before each edit, newest first.
+func (h *APIHandler) GetChirpHistory(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
+		return
+	}
+
+	chirp, err := h.getVisibleChirp(r.Context(), chirpID, h.optionalViewerID(r))
+	if err != nil {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp not found"})
+		return
+	}
+
+	limit, cursor := parsePageParams(r, defaultRevisionsLimit, maxRevisionsLimit)
+
+	revisions, err := h.cfg.DB.GetChirpRevisions(r.Context(), database.GetChirpRevisionsParams{
+		ChirpID:   chirpID,
+		Cursor:    cursor,
+		PageLimit: limit,
+	})
+	if err != nil {
+		log.Printf("Error fetching chirp revisions: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch chirp history"})
+		return
+	}
+
+	var nextCursor *string
+	if len(revisions) == int(limit) {
+		lastID := revisions[len(revisions)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	if revisions == nil {
+		revisions = []database.ChirpRevision{}
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		ChirpID    uuid.UUID                `json:"chirp_id"`
+		Body       string                   `json:"body"`
+		UpdatedAt  time.Time                `json:"updated_at"`
+		EditCount  int32                    `json:"edit_count"`
+		Edited     bool                     `json:"edited"`
+		Revisions  []database.ChirpRevision `json:"revisions"`
+		NextCursor *string                  `json:"next_cursor,omitempty"`
+	}{
+		ChirpID:    chirp.ID,
+		Body:       chirp.Body,
+		UpdatedAt:  chirp.UpdatedAt,
+		EditCount:  chirp.EditCount,
+		Edited:     chirp.Edited,
+		Revisions:  revisions,
+		NextCursor: nextCursor,
+	})
+}
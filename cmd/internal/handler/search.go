@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const defaultSearchLimit = 20
+const maxSearchLimit = 50
+
+const maxSearchQueryLength = 200
+const maxSearchTerms = 10
+
+const maxUserQueryLength = 50
+
+// splitSearchWords breaks text into lowercase runs of letters and digits,
+// which drops anything Postgres would treat as a tsquery operator.
+func splitSearchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// buildTSQuery turns user input into a to_tsquery expression. Quoted text
+// becomes a phrase match, a trailing * makes a prefix match, and all terms
+// must match.
+//
+//	big "yellow bird" chir*  =>  big & (yellow <-> bird) & chir:*
+func buildTSQuery(input string) (string, error) {
+	if len(input) > maxSearchQueryLength {
+		return "", errors.New("search query is too long")
+	}
+
+	var terms []string
+	addTerm := func(term string) error {
+		if len(terms) == maxSearchTerms {
+			return errors.New("search query has too many terms")
+		}
+		terms = append(terms, term)
+		return nil
+	}
+
+	rest := input
+	for rest != "" {
+		start := strings.IndexByte(rest, '"')
+		if start == -1 {
+			start = len(rest)
+		}
+
+		for _, field := range strings.Fields(rest[:start]) {
+			words := splitSearchWords(field)
+			if len(words) == 0 {
+				continue
+			}
+			if strings.HasSuffix(field, "*") {
+				words[len(words)-1] += ":*"
+			}
+			for _, word := range words {
+				if err := addTerm(word); err != nil {
+					return "", err
+				}
+			}
+		}
+
+		if start == len(rest) {
+			break
+		}
+
+		// An unterminated quote runs to the end of the input.
+		rest = rest[start+1:]
+		end := strings.IndexByte(rest, '"')
+		if end == -1 {
+			end = len(rest)
+		}
+
+		phrase := splitSearchWords(rest[:end])
+		switch len(phrase) {
+		case 0:
+		case 1:
+			if err := addTerm(phrase[0]); err != nil {
+				return "", err
+			}
+		default:
+			if err := addTerm("(" + strings.Join(phrase, " <-> ") + ")"); err != nil {
+				return "", err
+			}
+		}
+
+		if end == len(rest) {
+			break
+		}
+		rest = rest[end+1:]
+	}
+
+	if len(terms) == 0 {
+		return "", errors.New("search query is empty")
+	}
+
+	return strings.Join(terms, " & "), nil
+}
+
+// normalizeUserQuery lowercases a user search and drops a leading "@" so
+// "@Bird" and "bird" find the same accounts.
+func normalizeUserQuery(input string) (string, error) {
+	query := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(input), "@"))
+	if query == "" {
+		return "", errors.New("search query is empty")
+	}
+	if len(query) > maxUserQueryLength {
+		return "", errors.New("search query is too long")
+	}
+	return query, nil
+}
+
+// likePrefixPattern builds a LIKE pattern matching values that start with
+// query, escaping LIKE wildcards in the input.
+func likePrefixPattern(query string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return escaper.Replace(query) + "%"
+}
+
+// parseSearchTime accepts either an RFC 3339 timestamp or a plain date.
+func parseSearchTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// resolveAuthor accepts an author filter given as a user ID or a handle.
+func (h *APIHandler) resolveAuthor(ctx context.Context, author string) (uuid.UUID, error) {
+	if authorID, err := uuid.Parse(author); err == nil {
+		return authorID, nil
+	}
+
+	user, err := h.cfg.DB.GetUserByHandle(ctx, strings.TrimPrefix(author, "@"))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return user.ID, nil
+}
+
+func (h *APIHandler) SearchChirps(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	tsQuery, err := buildTSQuery(query.Get("q"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: err.Error()})
+		return
+	}
+
+	limit, cursor := parsePageParams(r, defaultSearchLimit, maxSearchLimit)
+	viewerID := h.optionalViewerID(r)
+
+	params := database.SearchChirpsParams{
+		Query:     tsQuery,
+		ViewerID:  nullViewerID(viewerID),
+		Cursor:    cursor,
+		PageLimit: limit,
+	}
+
+	if author := query.Get("author"); author != "" {
+		authorID, err := h.resolveAuthor(r.Context(), author)
+		if err != nil {
+			errJSON(w, http.StatusNotFound, ErrMessage{Message: "Author not found"})
+			return
+		}
+		params.AuthorID = uuid.NullUUID{UUID: authorID, Valid: true}
+	}
+
+	if since := query.Get("since"); since != "" {
+		t, err := parseSearchTime(since)
+		if err != nil {
+			errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid since date"})
+			return
+		}
+		params.Since.Time, params.Since.Valid = t, true
+	}
+
+	if until := query.Get("until"); until != "" {
+		t, err := parseSearchTime(until)
+		if err != nil {
+			errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid until date"})
+			return
+		}
+		params.Until.Time, params.Until.Valid = t, true
+	}
+
+	if query.Get("following") == "true" {
+		if viewerID == nil {
+			errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Unauthorized"})
+			return
+		}
+		params.FollowingOnly = true
+	}
+
+	results, err := h.cfg.DB.SearchChirps(r.Context(), params)
+	if err != nil {
+		log.Printf("Error searching chirps: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to search chirps"})
+		return
+	}
+
+	if results == nil {
+		results = []database.SearchChirpsRow{}
+	}
+
+	var nextCursor *string
+	if len(results) == int(limit) {
+		lastID := results[len(results)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	type SearchResponse struct {
+		Results    []database.SearchChirpsRow `json:"results"`
+		NextCursor *string                    `json:"next_cursor,omitempty"`
+	}
+
+	respondJSON(w, http.StatusOK, SearchResponse{
+		Results:    results,
+		NextCursor: nextCursor,
+	})
+}
+
+func (h *APIHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	query, err := normalizeUserQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: err.Error()})
+		return
+	}
+
+	limit, cursor := parsePageParams(r, defaultSearchLimit, maxSearchLimit)
+
+	users, err := h.cfg.DB.SearchUsers(r.Context(), database.SearchUsersParams{
+		ViewerID:  nullViewerID(h.optionalViewerID(r)),
+		Query:     query,
+		Prefix:    likePrefixPattern(query),
+		Cursor:    cursor,
+		PageLimit: limit,
+	})
+	if err != nil {
+		log.Printf("Error searching users: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to search users"})
+		return
+	}
+
+	// Only public fields are returned; emails are matched on but never shown.
+	type UserResult struct {
+		ID            uuid.UUID `json:"id"`
+		Handle        string    `json:"handle"`
+		DisplayName   string    `json:"display_name"`
+		AvatarURL     string    `json:"avatar_url,omitempty"`
+		IsChirpyRed   bool      `json:"is_chirpy_red"`
+		IsFollowing   bool      `json:"is_following"`
+		FollowsViewer bool      `json:"follows_you"`
+	}
+
+	results := make([]UserResult, len(users))
+	for i, u := range users {
+		results[i] = UserResult{
+			ID:            u.ID,
+			Handle:        u.Handle,
+			DisplayName:   u.DisplayName,
+			IsChirpyRed:   u.IsChirpyRed,
+			IsFollowing:   u.IsFollowing,
+			FollowsViewer: u.FollowsViewer,
+		}
+		if u.AvatarKey != "" {
+			results[i].AvatarURL = h.cfg.Blobs.URL(u.AvatarKey)
+		}
+	}
+
+	var nextCursor *string
+	if len(users) == int(limit) {
+		lastID := users[len(users)-1].ID.String()
+		nextCursor = &lastID
+	}
+
+	type UserSearchResponse struct {
+		Results    []UserResult `json:"results"`
+		NextCursor *string      `json:"next_cursor,omitempty"`
+	}
+
+	respondJSON(w, http.StatusOK, UserSearchResponse{
+		Results:    results,
+		NextCursor: nextCursor,
+	})
+}
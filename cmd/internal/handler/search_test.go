@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildTSQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "single word", input: "chirpy", want: "chirpy"},
+		{name: "words are and-ed", input: "big bird", want: "big & bird"},
+		{name: "lowercased", input: "Big BIRD", want: "big & bird"},
+		{name: "prefix", input: "chir*", want: "chir:*"},
+		{name: "phrase", input: `"big yellow bird"`, want: "(big <-> yellow <-> bird)"},
+		{name: "single word phrase", input: `"bird"`, want: "bird"},
+		{name: "mixed", input: `seeds "big bird" chir*`, want: "seeds & (big <-> bird) & chir:*"},
+		{name: "unterminated phrase", input: `hello "big bird`, want: "hello & (big <-> bird)"},
+		{name: "operators are stripped", input: "cats & !dogs | (birds)", want: "cats & dogs & birds"},
+		{name: "punctuation splits words", input: "e-mail", want: "e & mail"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "only punctuation", input: `!!! "" *`, wantErr: true},
+		{name: "too many terms", input: strings.Repeat("a ", maxSearchTerms+1), wantErr: true},
+		{name: "too long", input: strings.Repeat("a", maxSearchQueryLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildTSQuery(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildTSQuery(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("buildTSQuery(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeUserQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", input: "bird", want: "bird"},
+		{name: "lowercased", input: "BigBird", want: "bigbird"},
+		{name: "leading at sign", input: "@bird", want: "bird"},
+		{name: "trimmed", input: "  bird  ", want: "bird"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "only at sign", input: "@", wantErr: true},
+		{name: "too long", input: strings.Repeat("a", maxUserQueryLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeUserQuery(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeUserQuery(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeUserQuery(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLikePrefixPattern(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "bird", want: "bird%"},
+		{input: "big_bird", want: `big\_bird%`},
+		{input: "100%", want: `100\%%`},
+		{input: `back\slash`, want: `back\\slash%`},
+	}
+
+	for _, tt := range tests {
+		if got := likePrefixPattern(tt.input); got != tt.want {
+			t.Errorf("likePrefixPattern(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const (
+	accessTokenTTL       = time.Hour
+	maxSessionNameLength = 64
+	maxUserAgentLength   = 512
+)
+
+// sessionDevice describes the client a session was started or last used
+// from.
+type sessionDevice struct {
+	Name      string
+	UserAgent string
+	IPAddress string
+}
+
+func deviceFromRequest(r *http.Request, name string) sessionDevice {
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return sessionDevice{
+		Name:      name,
+		UserAgent: userAgent,
+		IPAddress: ip,
+	}
+}
+
+// makeAccessToken mints an access token carrying the user's current token
+// version, so it stops validating once they log out everywhere.
+func (h *APIHandler) makeAccessToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	version, err := h.cfg.DB.GetUserTokenVersion(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return auth.MakeJWT(userID, version, h.cfg.Keys, accessTokenTTL)
+}
+
+type SessionItem struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name,omitempty"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// GetSessions lists the devices the caller is logged in on, most recently
+// used first.
+func (h *APIHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.cfg.DB.GetUserSessions(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching sessions: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch sessions"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		Sessions []SessionItem `json:"sessions"`
+	}{
+		Sessions: sessionItems(sessions),
+	})
+}
+
+func sessionItems(sessions []database.Session) []SessionItem {
+	items := make([]SessionItem, 0, len(sessions))
+	for _, s := range sessions {
+		items = append(items, SessionItem{
+			ID:         s.ID,
+			Name:       s.Name.String,
+			UserAgent:  s.UserAgent,
+			IPAddress:  s.IpAddress,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+		})
+	}
+	return items
+}
+
+// RevokeSession logs the caller out on one device by revoking the refresh
+// tokens of that session.
+func (h *APIHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	sessionID, err := uuid.Parse(r.PathValue("sessionID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid session ID"})
+		return
+	}
+
+	revoked, err := h.cfg.DB.RevokeSession(r.Context(), database.RevokeSessionParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Printf("Error revoking session: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to revoke session"})
+		return
+	}
+
+	if revoked == 0 {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Session not found"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeAllSessions logs the caller out everywhere. Besides revoking every
+// refresh token it bumps the token version, so access tokens already handed
+// out stop working too, including the one used for this request.
+func (h *APIHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.cfg.DB.RevokeAllSessions(r.Context(), userID); err != nil {
+		log.Printf("Error revoking all sessions: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to revoke sessions"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// JWKS publishes the public keys access tokens can currently be verified
+// with, so other services can check them without sharing a secret.
+func (h *APIHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondJSON(w, http.StatusOK, h.cfg.Keys.JWKS())
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDeviceFromRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		userAgent  string
+		want       sessionDevice
+	}{
+		{
+			name:       "ipv4",
+			remoteAddr: "203.0.113.7:52314",
+			userAgent:  "chirpy-tui/1.0",
+			want:       sessionDevice{Name: "laptop", UserAgent: "chirpy-tui/1.0", IPAddress: "203.0.113.7"},
+		},
+		{
+			name:       "ipv6",
+			remoteAddr: "[2001:db8::1]:443",
+			want:       sessionDevice{Name: "laptop", IPAddress: "2001:db8::1"},
+		},
+		{
+			name:       "address without port",
+			remoteAddr: "unix",
+			want:       sessionDevice{Name: "laptop", IPAddress: "unix"},
+		},
+		{
+			name:       "long user agent is truncated",
+			remoteAddr: "203.0.113.7:1",
+			userAgent:  strings.Repeat("a", maxUserAgentLength+10),
+			want:       sessionDevice{Name: "laptop", UserAgent: strings.Repeat("a", maxUserAgentLength), IPAddress: "203.0.113.7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			r.Header.Set("User-Agent", tt.userAgent)
+
+			if got := deviceFromRequest(r, "laptop"); got != tt.want {
+				t.Errorf("deviceFromRequest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
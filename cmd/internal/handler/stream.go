@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+	"github.com/shubh-man007/Chirpy/cmd/internal/stream"
+)
+
+const (
+	streamChirpCreated   = "chirp.created"
+	streamChirpUpdated   = "chirp.updated"
+	streamChirpDeleted   = "chirp.deleted"
+	streamRechirpDeleted = "rechirp.deleted"
+	streamFollowerAdded  = "follower.added"
+	// streamReset tells the client that events were lost and it has to
+	// refetch instead of applying deltas.
+	streamReset = "reset"
+)
+
+const (
+	streamHeartbeatInterval = 25 * time.Second
+	streamWriteTimeout      = 10 * time.Second
+	streamRetry             = 3 * time.Second
+)
+
+// streamContent is what a chirp event shows: the chirp's author and text,
+// plus any other account it involves, such as the author of a quoted chirp.
+// Each recipient's blocks, mutes and muted words are checked against it.
+type streamContent struct {
+	authorID uuid.UUID
+	body     string
+	related  []uuid.UUID
+}
+
+// publishChirpEvent sends a chirp event to the author's listening followers
+// and to the author's own open streams. Followers who have silenced anyone
+// the event involves do not get it, and muted words are applied per follower
+// the same way as in listings. Like notify, failures are only logged.
+func (h *APIHandler) publishChirpEvent(ctx context.Context, eventType string, authorID uuid.UUID, data any, content streamContent) {
+	subjects := append([]uuid.UUID{authorID}, content.related...)
+	if content.authorID != uuid.Nil && content.authorID != authorID {
+		subjects = append(subjects, content.authorID)
+	}
+
+	followers, err := h.cfg.DB.GetFollowerIDs(ctx, database.GetFollowerIDsParams{
+		FolloweeID: authorID,
+		SubjectIds: subjects,
+	})
+	if err != nil {
+		log.Printf("Error fetching followers for %s event: %v", eventType, err)
+		return
+	}
+
+	// Followers without a stream to receive it are not worth filtering for.
+	listening := h.cfg.Stream.Listening(followers)
+
+	var filters map[uuid.UUID]wordFilters
+	if content.body != "" {
+		filters = h.wordFiltersForAll(ctx, listening)
+	}
+
+	// Followers whose muted words flag rather than hide the chirp get a copy
+	// carrying the match, as listed chirps do.
+	recipients := []uuid.UUID{authorID}
+	flagged := make(map[FilterMatch][]uuid.UUID)
+	for _, followerID := range listening {
+		match, keep := filters[followerID].apply(content.authorID, content.body)
+		switch {
+		case !keep:
+		case match.Filtered:
+			flagged[match] = append(flagged[match], followerID)
+		default:
+			recipients = append(recipients, followerID)
+		}
+	}
+
+	if _, err := h.cfg.Stream.Publish(eventType, data, recipients...); err != nil {
+		log.Printf("Error publishing %s event: %v", eventType, err)
+	}
+	for match, ids := range flagged {
+		flaggedData, err := withFilterMatch(data, match)
+		if err != nil {
+			log.Printf("Error flagging %s event: %v", eventType, err)
+			continue
+		}
+		if _, err := h.cfg.Stream.Publish(eventType, flaggedData, ids...); err != nil {
+			log.Printf("Error publishing %s event: %v", eventType, err)
+		}
+	}
+}
+
+// withFilterMatch adds the filtered fields to an event payload.
+func withFilterMatch(data any, match FilterMatch) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	raw, err = json.Marshal(match)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func (h *APIHandler) publishFollowerAdded(followerID, followeeID uuid.UUID) {
+	data := struct {
+		FollowerID uuid.UUID `json:"follower_id"`
+		FollowedAt time.Time `json:"followed_at"`
+	}{
+		FollowerID: followerID,
+		FollowedAt: time.Now().UTC(),
+	}
+
+	if _, err := h.cfg.Stream.Publish(streamFollowerAdded, data, followeeID); err != nil {
+		log.Printf("Error publishing %s event: %v", streamFollowerAdded, err)
+	}
+}
+
+// parseLastEventID reads the resume position from the Last-Event-ID header,
+// falling back to a last_event_id query parameter for clients that cannot
+// set headers on reconnect.
+func parseLastEventID(r *http.Request) (uint64, error) {
+	raw := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if raw == "" {
+		raw = strings.TrimSpace(r.URL.Query().Get("last_event_id"))
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
+
+// formatSSE encodes an event in the text/event-stream wire format. Data is
+// split on newlines so multi-line payloads stay a single event.
+func formatSSE(event stream.Event) []byte {
+	var buf bytes.Buffer
+	if event.ID != 0 {
+		fmt.Fprintf(&buf, "id: %d\n", event.ID)
+	}
+	if event.Type != "" {
+		fmt.Fprintf(&buf, "event: %s\n", event.Type)
+	}
+	for _, line := range bytes.Split(event.Data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+// Stream is a Server-Sent Events endpoint pushing feed and follower activity
+// for the authenticated user.
+func (h *APIHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid Last-Event-ID"})
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	sub, missed, complete := h.cfg.Stream.Subscribe(userID, lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// write sends one chunk with a deadline, so a client that stops reading
+	// cannot pin this goroutine forever.
+	write := func(chunk []byte) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if err := write(fmt.Appendf(nil, "retry: %d\n\n", streamRetry.Milliseconds())); err != nil {
+		return
+	}
+
+	if !complete {
+		if err := write(formatSSE(stream.Event{Type: streamReset, Data: []byte("{}")})); err != nil {
+			return
+		}
+	}
+	for _, event := range missed {
+		if err := write(formatSSE(event)); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Dropped for falling behind; the client reconnects with its
+				// Last-Event-ID and replays from the hub's backlog.
+				if sub.Lagged() {
+					log.Printf("Stream for user %s dropped: client fell behind", userID)
+				}
+				return
+			}
+			if err := write(formatSSE(event)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := write([]byte(": heartbeat\n\n")); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shubh-man007/Chirpy/cmd/internal/stream"
+)
+
+func TestFormatSSE(t *testing.T) {
+	tests := []struct {
+		name  string
+		event stream.Event
+		want  string
+	}{
+		{
+			name:  "full event",
+			event: stream.Event{ID: 7, Type: streamChirpCreated, Data: []byte(`{"id":"x"}`)},
+			want:  "id: 7\nevent: chirp.created\ndata: {\"id\":\"x\"}\n\n",
+		},
+		{
+			name:  "no id",
+			event: stream.Event{Type: streamReset, Data: []byte("{}")},
+			want:  "event: reset\ndata: {}\n\n",
+		},
+		{
+			name:  "multi-line data",
+			event: stream.Event{ID: 1, Data: []byte("a\nb")},
+			want:  "id: 1\ndata: a\ndata: b\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(formatSSE(tt.event)); got != tt.want {
+				t.Errorf("formatSSE() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLastEventID(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		url     string
+		want    uint64
+		wantErr bool
+	}{
+		{name: "none", url: "/api/stream", want: 0},
+		{name: "header", header: "42", url: "/api/stream", want: 42},
+		{name: "query fallback", url: "/api/stream?last_event_id=17", want: 17},
+		{name: "header wins", header: "5", url: "/api/stream?last_event_id=17", want: 5},
+		{name: "invalid", header: "abc", url: "/api/stream", wantErr: true},
+		{name: "negative", header: "-1", url: "/api/stream", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.header != "" {
+				r.Header.Set("Last-Event-ID", tt.header)
+			}
+
+			got, err := parseLastEventID(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLastEventID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseLastEventID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithFilterMatch(t *testing.T) {
+	data := struct {
+		ID   string `json:"id"`
+		Body string `json:"body"`
+	}{
+		ID:   "x",
+		Body: "spoilers ahead",
+	}
+
+	fields, err := withFilterMatch(data, FilterMatch{Filtered: true, FilteredBy: "spoilers"})
+	if err != nil {
+		t.Fatalf("withFilterMatch() error = %v", err)
+	}
+
+	got, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `{"body":"spoilers ahead","filtered":true,"filtered_by":"spoilers","id":"x"}`
+	if string(got) != want {
+		t.Errorf("withFilterMatch() = %s, want %s", got, want)
+	}
+}
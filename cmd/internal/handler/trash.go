@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const defaultTrashLimit = 20
+const maxTrashLimit = 100
+
+// TrashedChirp is a deleted chirp waiting in its author's trash.
+type TrashedChirp struct {
+	database.Chirp
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// GetTrash lists the caller's deleted chirps, most recently deleted first.
+func (h *APIHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := parsePageLimit(r, defaultTrashLimit, maxTrashLimit)
+	cursor, err := parseCursorParam(r, "cursor")
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid cursor"})
+		return
+	}
+
+	params := database.GetDeletedChirpsParams{
+		UserID:    userID,
+		PageLimit: limit,
+	}
+	params.CursorAt, params.CursorID = cursorArgs(cursor)
+
+	chirps, err := h.cfg.DB.GetDeletedChirps(r.Context(), params)
+	if err != nil {
+		log.Printf("Error fetching trash: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to fetch trash"})
+		return
+	}
+
+	items := make([]TrashedChirp, len(chirps))
+	for i, c := range chirps {
+		items[i] = TrashedChirp{
+			Chirp:     c,
+			DeletedAt: c.DeletedAt.Time,
+			PurgeAt:   c.DeletedAt.Time.Add(h.cfg.TrashRetention),
+		}
+	}
+
+	var nextCursor *string
+	if len(chirps) == int(limit) {
+		last := chirps[len(chirps)-1]
+		next := pageCursor{At: last.DeletedAt.Time, ID: last.ID}.String()
+		nextCursor = &next
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		Chirps     []TrashedChirp `json:"chirps"`
+		NextCursor *string        `json:"next_cursor,omitempty"`
+	}{
+		Chirps:     items,
+		NextCursor: nextCursor,
+	})
+}
+
+// RestoreChirp takes a chirp out of the caller's trash.
+func (h *APIHandler) RestoreChirp(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid chirp ID"})
+		return
+	}
+
+	chirp, err := h.cfg.DB.RestoreChirp(r.Context(), database.RestoreChirpParams{
+		ID:     chirpID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		errJSON(w, http.StatusNotFound, ErrMessage{Message: "Chirp not found in trash"})
+		return
+	}
+	if err != nil {
+		log.Printf("Error restoring chirp: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Failed to restore chirp"})
+		return
+	}
+
+	resp := ChirpResponse{
+		Chirp:    chirp,
+		Entities: h.chirpEntities(r.Context(), chirp.ID, chirp.Body),
+	}
+	h.publishChirpEvent(r.Context(), streamChirpCreated, userID, resp, streamContent{
+		authorID: userID,
+		body:     chirp.Body,
+	})
+
+	respondJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,391 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const (
+	totpIssuer           = "Chirpy"
+	recoveryCodeCount    = 10
+	loginChallengeTTL    = 5 * time.Minute
+	maxChallengeAttempts = 5
+	// Failed codes are also counted per user, across challenges, so asking
+	// for fresh challenges does not buy more guesses.
+	maxTwoFactorFailures = 5
+	twoFactorLockout     = 15 * time.Minute
+)
+
+var errTwoFactorLocked = errors.New("too many failed second factor attempts")
+
+// secondFactorStore is the part of *database.Queries used to check a TOTP
+// or recovery code during login.
+type secondFactorStore interface {
+	GetUserTwoFactor(ctx context.Context, id uuid.UUID) (database.GetUserTwoFactorRow, error)
+	RecordTwoFactorFailure(ctx context.Context, arg database.RecordTwoFactorFailureParams) (bool, error)
+	ResetTwoFactorFailures(ctx context.Context, id uuid.UUID) error
+	UseRecoveryCode(ctx context.Context, arg database.UseRecoveryCodeParams) (int64, error)
+	UseTOTPStep(ctx context.Context, arg database.UseTOTPStepParams) (int64, error)
+}
+
+// verifySecondFactor checks a TOTP code or, failing that, a recovery code.
+// Either is consumed on success, so a code that was intercepted or shoulder
+// surfed cannot be replayed. Failures count towards a per-user lockout,
+// during which it returns errTwoFactorLocked without checking anything.
+func verifySecondFactor(ctx context.Context, store secondFactorStore, secrets *auth.SecretBox, userID uuid.UUID, code, recoveryCode string, now time.Time) (bool, error) {
+	twoFactor, err := store.GetUserTwoFactor(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if twoFactor.TwoFactorLocked {
+		return false, errTwoFactorLocked
+	}
+
+	ok, err := checkSecondFactor(ctx, store, secrets, userID, twoFactor, code, recoveryCode, now)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return true, store.ResetTwoFactorFailures(ctx, userID)
+	}
+
+	locked, err := store.RecordTwoFactorFailure(ctx, database.RecordTwoFactorFailureParams{
+		MaxFailures:    maxTwoFactorFailures,
+		LockoutSeconds: int32(twoFactorLockout / time.Second),
+		ID:             userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	if locked {
+		log.Printf("Locked 2FA logins for user %v after %d failed codes", userID, maxTwoFactorFailures)
+	}
+
+	return false, nil
+}
+
+func checkSecondFactor(ctx context.Context, store secondFactorStore, secrets *auth.SecretBox, userID uuid.UUID, twoFactor database.GetUserTwoFactorRow, code, recoveryCode string, now time.Time) (bool, error) {
+	if recoveryCode != "" {
+		used, err := store.UseRecoveryCode(ctx, database.UseRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: auth.HashRecoveryCode(recoveryCode),
+		})
+		return used == 1, err
+	}
+
+	if !twoFactor.TotpEnabledAt.Valid {
+		return false, nil
+	}
+
+	secret, err := secrets.Open(twoFactor.TotpSecret.String)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := auth.ValidateTOTP(secret, code, now)
+	if !ok {
+		return false, nil
+	}
+
+	used, err := store.UseTOTPStep(ctx, database.UseTOTPStepParams{
+		ID:           userID,
+		TotpLastStep: step,
+	})
+	return used == 1, err
+}
+
+// startLoginChallenge answers a correct password for an account with 2FA
+// enabled. No tokens are issued until the challenge is completed at
+// /api/login/2fa.
+func (h *APIHandler) startLoginChallenge(w http.ResponseWriter, r *http.Request, userID uuid.UUID, deviceName string) {
+	token, err := auth.MakeRefreshToken()
+	if err != nil {
+		log.Printf("Error creating login challenge: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	expiresAt := time.Now().Add(loginChallengeTTL)
+	err = h.cfg.DB.CreateLoginChallenge(r.Context(), database.CreateLoginChallengeParams{
+		TokenHash:  auth.HashRefreshToken(token),
+		UserID:     userID,
+		DeviceName: deviceName,
+		ExpiresAt:  expiresAt,
+	})
+	if err != nil {
+		log.Printf("Error storing login challenge: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		TwoFactorRequired bool      `json:"two_factor_required"`
+		ChallengeToken    string    `json:"challenge_token"`
+		ExpiresAt         time.Time `json:"expires_at"`
+	}{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresAt:         expiresAt,
+	})
+}
+
+func writeTwoFactorLocked(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", fmt.Sprint(int(twoFactorLockout/time.Second)))
+	errJSON(w, http.StatusTooManyRequests, ErrMessage{Message: "Too many failed attempts, try again later"})
+}
+
+// CompleteTwoFactorLogin finishes a login started at /api/login by checking
+// a TOTP code or a recovery code against the challenge token.
+func (h *APIHandler) CompleteTwoFactorLogin(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code"`
+		RecoveryCode   string `json:"recovery_code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid request body"})
+		return
+	}
+
+	if req.ChallengeToken == "" || (req.Code == "") == (req.RecoveryCode == "") {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Provide a challenge token and either a code or a recovery code"})
+		return
+	}
+
+	challengeHash := auth.HashRefreshToken(req.ChallengeToken)
+	challenge, err := h.cfg.DB.AttemptLoginChallenge(r.Context(), database.AttemptLoginChallengeParams{
+		TokenHash:   challengeHash,
+		MaxAttempts: maxChallengeAttempts,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Login challenge is invalid or expired"})
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching login challenge: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	ok, err := verifySecondFactor(r.Context(), h.cfg.DB, h.cfg.TOTPSecrets, challenge.UserID, req.Code, req.RecoveryCode, time.Now())
+	if errors.Is(err, errTwoFactorLocked) {
+		writeTwoFactorLocked(w)
+		return
+	}
+	if err != nil {
+		log.Printf("Error verifying second factor: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+	if !ok {
+		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Invalid authentication code"})
+		return
+	}
+
+	// Only one request gets to complete a challenge.
+	deleted, err := h.cfg.DB.DeleteLoginChallenge(r.Context(), challengeHash)
+	if err != nil {
+		log.Printf("Error deleting login challenge: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+	if deleted == 0 {
+		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Login challenge is invalid or expired"})
+		return
+	}
+
+	user, err := h.cfg.DB.GetUserByID(r.Context(), challenge.UserID)
+	if err != nil {
+		log.Printf("Error fetching user: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	h.completeLogin(w, r, database.GetUserByEmailRow(user), challenge.DeviceName)
+}
+
+// EnrollTOTP starts 2FA enrollment with a fresh secret. 2FA is not enforced
+// until a first code has been confirmed at /api/me/2fa/verify; calling this
+// again before then replaces the secret.
+func (h *APIHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.cfg.DB.GetUserByID(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching user: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		log.Printf("Error generating TOTP secret: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	sealed, err := h.cfg.TOTPSecrets.Seal(secret)
+	if err != nil {
+		log.Printf("Error encrypting TOTP secret: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	updated, err := h.cfg.DB.SetPendingTOTPSecret(r.Context(), database.SetPendingTOTPSecretParams{
+		ID:         userID,
+		TotpSecret: sql.NullString{String: sealed, Valid: true},
+	})
+	if err != nil {
+		log.Printf("Error storing TOTP secret: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+	if updated == 0 {
+		errJSON(w, http.StatusConflict, ErrMessage{Message: "Two-factor authentication is already enabled"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		Secret     string `json:"secret"`
+		OtpauthURI string `json:"otpauth_uri"`
+	}{
+		Secret:     secret,
+		OtpauthURI: auth.TOTPURI(totpIssuer, user.Email, secret),
+	})
+}
+
+// VerifyTOTP confirms enrollment with a first code from the authenticator
+// app, turns 2FA on and returns the recovery codes. They are shown only
+// this once.
+func (h *APIHandler) VerifyTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid request body"})
+		return
+	}
+
+	twoFactor, err := h.cfg.DB.GetUserTwoFactor(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching 2FA state: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+	if twoFactor.TotpEnabledAt.Valid {
+		errJSON(w, http.StatusConflict, ErrMessage{Message: "Two-factor authentication is already enabled"})
+		return
+	}
+	if !twoFactor.TotpSecret.Valid {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Start enrollment before verifying a code"})
+		return
+	}
+
+	secret, err := h.cfg.TOTPSecrets.Open(twoFactor.TotpSecret.String)
+	if err != nil {
+		log.Printf("Error decrypting TOTP secret: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	step, ok := auth.ValidateTOTP(secret, req.Code, time.Now())
+	if !ok {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid authentication code"})
+		return
+	}
+
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		log.Printf("Error generating recovery codes: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, auth.HashRecoveryCode(code))
+	}
+
+	enabled, err := h.cfg.DB.EnableTOTP(r.Context(), database.EnableTOTPParams{
+		Step:       step,
+		ID:         userID,
+		TotpSecret: twoFactor.TotpSecret,
+		CodeHashes: hashes,
+	})
+	if err != nil {
+		log.Printf("Error enabling 2FA: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+	if enabled == 0 {
+		// Enrollment was restarted or completed by another request meanwhile.
+		errJSON(w, http.StatusConflict, ErrMessage{Message: "Enrollment changed, please start again"})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}{
+		RecoveryCodes: codes,
+	})
+}
+
+// DisableTOTP turns 2FA off. A stolen access token alone is not enough, so
+// the password has to be confirmed.
+func (h *APIHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{Message: "Invalid request body"})
+		return
+	}
+
+	twoFactor, err := h.cfg.DB.GetUserTwoFactor(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching 2FA state: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	match, err := auth.CheckPasswordHash(req.Password, twoFactor.HashedPassword)
+	if err != nil || !match {
+		errJSON(w, http.StatusForbidden, ErrMessage{Message: "Incorrect password"})
+		return
+	}
+
+	disabled, err := h.cfg.DB.DisableTOTP(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error disabling 2FA: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+	if disabled == 0 {
+		errJSON(w, http.StatusConflict, ErrMessage{Message: "Two-factor authentication is not enabled"})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+// memSecondFactorStore mirrors the 2FA queries in memory for one user.
+type memSecondFactorStore struct {
+	userID        uuid.UUID
+	twoFactor     database.GetUserTwoFactorRow
+	recoveryCodes map[string]bool
+	failures      int32
+}
+
+func (s *memSecondFactorStore) GetUserTwoFactor(_ context.Context, id uuid.UUID) (database.GetUserTwoFactorRow, error) {
+	if id != s.userID {
+		return database.GetUserTwoFactorRow{}, sql.ErrNoRows
+	}
+	return s.twoFactor, nil
+}
+
+func (s *memSecondFactorStore) RecordTwoFactorFailure(_ context.Context, arg database.RecordTwoFactorFailureParams) (bool, error) {
+	s.failures++
+	if s.failures >= arg.MaxFailures {
+		s.failures = 0
+		s.twoFactor.TwoFactorLocked = true
+	}
+	return s.twoFactor.TwoFactorLocked, nil
+}
+
+func (s *memSecondFactorStore) ResetTwoFactorFailures(context.Context, uuid.UUID) error {
+	s.failures = 0
+	return nil
+}
+
+func (s *memSecondFactorStore) UseRecoveryCode(_ context.Context, arg database.UseRecoveryCodeParams) (int64, error) {
+	if arg.UserID != s.userID || !s.recoveryCodes[arg.CodeHash] {
+		return 0, nil
+	}
+	s.recoveryCodes[arg.CodeHash] = false
+	return 1, nil
+}
+
+func (s *memSecondFactorStore) UseTOTPStep(_ context.Context, arg database.UseTOTPStepParams) (int64, error) {
+	if arg.ID != s.userID || !s.twoFactor.TotpEnabledAt.Valid || s.twoFactor.TotpLastStep >= arg.TotpLastStep {
+		return 0, nil
+	}
+	s.twoFactor.TotpLastStep = arg.TotpLastStep
+	return 1, nil
+}
+
+func TestVerifySecondFactor(t *testing.T) {
+	// RFC 6238 test secret; 050471 is its code at 1111111111.
+	const (
+		secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+		code   = "050471"
+	)
+	now := time.Unix(1111111111, 0)
+	secrets := newTestSecretBox(t)
+
+	newStore := func(enabled, plaintext bool) *memSecondFactorStore {
+		stored := secret
+		if !plaintext {
+			var err error
+			if stored, err = secrets.Seal(secret); err != nil {
+				t.Fatalf("Seal failed: %v", err)
+			}
+		}
+		return &memSecondFactorStore{
+			userID: uuid.New(),
+			twoFactor: database.GetUserTwoFactorRow{
+				TotpSecret:    sql.NullString{String: stored, Valid: true},
+				TotpEnabledAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: enabled},
+			},
+			recoveryCodes: map[string]bool{auth.HashRecoveryCode("abcdefgh-ijklmnop-qrstuvwx-yz234567"): true},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		enabled   bool
+		plaintext bool
+		attempts  []struct{ code, recovery string }
+		want      []bool
+	}{
+		{
+			name:     "totp code",
+			enabled:  true,
+			attempts: []struct{ code, recovery string }{{code: code}},
+			want:     []bool{true},
+		},
+		{
+			name:      "secret stored before encryption",
+			enabled:   true,
+			plaintext: true,
+			attempts:  []struct{ code, recovery string }{{code: code}},
+			want:      []bool{true},
+		},
+		{
+			name:     "totp code replayed",
+			enabled:  true,
+			attempts: []struct{ code, recovery string }{{code: code}, {code: code}},
+			want:     []bool{true, false},
+		},
+		{
+			name:     "wrong totp code",
+			enabled:  true,
+			attempts: []struct{ code, recovery string }{{code: "123456"}},
+			want:     []bool{false},
+		},
+		{
+			name:     "pending enrollment is not a second factor",
+			enabled:  false,
+			attempts: []struct{ code, recovery string }{{code: code}},
+			want:     []bool{false},
+		},
+		{
+			name:     "recovery code is single use",
+			enabled:  true,
+			attempts: []struct{ code, recovery string }{{recovery: "ABCDEFGH IJKLMNOP QRSTUVWX YZ234567"}, {recovery: "abcdefgh-ijklmnop-qrstuvwx-yz234567"}},
+			want:     []bool{true, false},
+		},
+		{
+			name:     "unknown recovery code",
+			enabled:  true,
+			attempts: []struct{ code, recovery string }{{recovery: "zzzzzzzz-zzzzzzzz-zzzzzzzz-zzzzzzzz"}},
+			want:     []bool{false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStore(tt.enabled, tt.plaintext)
+
+			for i, attempt := range tt.attempts {
+				ok, err := verifySecondFactor(context.Background(), store, secrets, store.userID, attempt.code, attempt.recovery, now)
+				if err != nil {
+					t.Fatalf("verifySecondFactor failed: %v", err)
+				}
+				if ok != tt.want[i] {
+					t.Errorf("attempt %d = %v, want %v", i+1, ok, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestVerifySecondFactorLockout(t *testing.T) {
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	now := time.Unix(1111111111, 0)
+
+	store := &memSecondFactorStore{
+		userID: uuid.New(),
+		twoFactor: database.GetUserTwoFactorRow{
+			TotpSecret:    sql.NullString{String: secret, Valid: true},
+			TotpEnabledAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+		},
+		recoveryCodes: map[string]bool{},
+	}
+	secrets := newTestSecretBox(t)
+
+	// A success in between resets the count.
+	for range maxTwoFactorFailures - 1 {
+		if ok, err := verifySecondFactor(context.Background(), store, secrets, store.userID, "123456", "", now); ok || err != nil {
+			t.Fatalf("wrong code = (%v, %v), want (false, nil)", ok, err)
+		}
+	}
+	if ok, err := verifySecondFactor(context.Background(), store, secrets, store.userID, "050471", "", now); !ok || err != nil {
+		t.Fatalf("correct code = (%v, %v), want (true, nil)", ok, err)
+	}
+	if store.failures != 0 {
+		t.Errorf("failures = %d after success, want 0", store.failures)
+	}
+
+	// maxTwoFactorFailures wrong codes in a row lock out even the right one,
+	// however many challenges they are spread across.
+	for range maxTwoFactorFailures {
+		if _, err := verifySecondFactor(context.Background(), store, secrets, store.userID, "123456", "", now); err != nil {
+			t.Fatalf("verifySecondFactor failed: %v", err)
+		}
+	}
+	later := now.Add(2 * time.Minute)
+	if _, err := verifySecondFactor(context.Background(), store, secrets, store.userID, "000000", "", later); !errors.Is(err, errTwoFactorLocked) {
+		t.Errorf("err = %v after %d failures, want %v", err, maxTwoFactorFailures, errTwoFactorLocked)
+	}
+}
+
+func newTestSecretBox(t *testing.T) *auth.SecretBox {
+	t.Helper()
+	box, err := auth.NewSecretBox(make([]byte, auth.SecretKeySize))
+	if err != nil {
+		t.Fatalf("NewSecretBox failed: %v", err)
+	}
+	return box
+}
@@ -1,288 +1,367 @@
-package handler
-
-import (
-	"encoding/json"
-	"log"
-	"net/http"
-	"time"
-
-	"github.com/google/uuid"
-	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
-	"github.com/shubh-man007/Chirpy/cmd/internal/database"
-)
-
-func (h *APIHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
-	var req UserLogin
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("Error decoding request JSON: %v", err)
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Invalid email or password",
-		})
-		return
-	}
-
-	hashedPassword, err := auth.HashPassword(req.Password)
-	if err != nil {
-		log.Printf("Error hashing password: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-
-	userParams := database.CreateUserParams{
-		Email:          req.Email,
-		HashedPassword: hashedPassword,
-	}
-	user, err := h.cfg.DB.CreateUser(r.Context(), userParams)
-	if err != nil {
-		log.Printf("Error creating user: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	respondJSON(w, http.StatusCreated, user)
-}
-
-func (h *APIHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
-	var req UserLogin
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("Error decoding request JSON: %v", err)
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-
-	userCreds, err := h.cfg.DB.GetUserPassByEmail(r.Context(), req.Email)
-	if err != nil {
-		log.Printf("Error validating user: %s", err)
-		http.Error(w, "Invalid email or password", http.StatusInternalServerError)
-		return
-	}
-
-	val, err := auth.CheckPasswordHash(req.Password, userCreds)
-	if err != nil {
-		log.Printf("Unauthorized User: %s", err)
-		http.Error(w, "Something went wrong", http.StatusUnauthorized)
-		return
-	}
-
-	if !val {
-		log.Printf("Unauthorized User: %s", err)
-		http.Error(w, "Invalid email or password", http.StatusUnauthorized)
-		return
-	}
-
-	user, err := h.cfg.DB.GetUserByEmail(r.Context(), req.Email)
-	if err != nil {
-		log.Printf("Error fetching user: %v", err)
-		http.Error(w, "Invalid email or password", http.StatusInternalServerError)
-		return
-	}
-
-	jwtToken, err := auth.MakeJWT(user.ID, h.cfg.JWTSecret, time.Hour)
-	if err != nil {
-		log.Printf("Error creating JWT token: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	refreshToken, err := auth.MakeRefreshToken()
-	if err != nil {
-		log.Printf("Error creating refresh token: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	_, err = h.cfg.DB.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
-		Token:     refreshToken,
-		UserID:    user.ID,
-		ExpiresAt: time.Now().Add(60 * 24 * time.Hour), // 60 days
-	})
-	if err != nil {
-		log.Printf("Error storing refresh token: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-
-	type LoginResponse struct {
-		database.GetUserByEmailRow
-		Token        string `json:"token"`
-		RefreshToken string `json:"refresh_token"`
-	}
-
-	respondJSON(w, http.StatusOK, LoginResponse{
-		GetUserByEmailRow: user,
-		Token:             jwtToken,
-		RefreshToken:      refreshToken,
-	})
-}
-
-func (h *APIHandler) UpdateUserCred(w http.ResponseWriter, r *http.Request) {
-	var updateCred UserLogin
-	err := json.NewDecoder(r.Body).Decode(&updateCred)
-	if err != nil {
-		log.Printf("Error decoding request JSON: %v", err)
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Invalid email or password",
-		})
-		return
-	}
-
-	accessToken, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		log.Printf("Access token absent: %v", err)
-		http.Error(w, "Something went wrong", http.StatusUnauthorized)
-		return
-	}
-
-	userID, err := auth.ValidateJWT(accessToken, h.cfg.JWTSecret)
-	if err != nil {
-		log.Printf("Invalid access token: %v", err)
-		http.Error(w, "Something went wrong", http.StatusUnauthorized)
-		return
-	}
-
-	hashedPassword, err := auth.HashPassword(updateCred.Password)
-	if err != nil {
-		log.Printf("Could not hash password: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	updatedUser, err := h.cfg.DB.UpdateUserCred(r.Context(), database.UpdateUserCredParams{
-		Email:          updateCred.Email,
-		HashedPassword: hashedPassword,
-		ID:             userID,
-	})
-	if err != nil {
-		log.Printf("Could not update DB: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	respondJSON(w, http.StatusOK, updatedUser)
-}
-
-func (h *APIHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
-	refreshToken, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		log.Printf("Refresh token absent: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	user, err := h.cfg.DB.GetUserFromRefreshToken(r.Context(), refreshToken)
-	if err != nil {
-		log.Printf("Invalid refresh token: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	accessToken, err := auth.MakeJWT(user.ID, h.cfg.JWTSecret, time.Hour)
-	if err != nil {
-		log.Printf("Error creating access token: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-
-	type RefreshResponse struct {
-		Token string `json:"token"`
-	}
-
-	respondJSON(w, http.StatusOK, RefreshResponse{
-		Token: accessToken,
-	})
-}
-
-func (h *APIHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
-	refreshToken, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		log.Printf("Refresh token absent: %v", err)
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	err = h.cfg.DB.RevokeRefreshToken(r.Context(), refreshToken)
-	if err != nil {
-		log.Printf("Error revoking token: %v", err)
-		errJSON(w, http.StatusInternalServerError, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-
-	w.WriteHeader(http.StatusNoContent)
-}
-
-func (h *APIHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
-	pathUserID, err := uuid.Parse(r.PathValue("userID"))
-	if err != nil {
-		log.Printf("Could not parse user ID: %v", err)
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-
-	accessToken, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		log.Printf("Access token absent: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	userID, err := auth.ValidateJWT(accessToken, h.cfg.JWTSecret)
-	if err != nil {
-		log.Printf("Invalid access token: %v", err)
-		errJSON(w, http.StatusUnauthorized, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	if pathUserID != userID {
-		log.Print("UserID not matched with ID sent via path")
-		errJSON(w, http.StatusForbidden, ErrMessage{
-			Message: "Unauthorized",
-		})
-		return
-	}
-
-	err = h.cfg.DB.DeleteUserByID(r.Context(), userID)
-	if err != nil {
-		log.Printf("Error deleting user: %v", err)
-		http.Error(w, "Something went wrong", http.StatusNotFound)
-		return
-	}
-	w.WriteHeader(http.StatusNoContent)
-}
-
-func (h *APIHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
-	userID, err := uuid.Parse(r.PathValue("userID"))
-	if err != nil {
-		log.Printf("Could not parse user ID: %v", err)
-		errJSON(w, http.StatusBadRequest, ErrMessage{
-			Message: "Something went wrong",
-		})
-		return
-	}
-
-	user, err := h.cfg.DB.GetUserByID(r.Context(), userID)
-	if err != nil {
-		log.Printf("Error fetching user: %v", err)
-		http.Error(w, "User not found", http.StatusNotFound)
-		return
-	}
-
-	respondJSON(w, http.StatusOK, user)
-}
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+	"github.com/shubh-man007/Chirpy/cmd/internal/middleware"
+)
+
+func (h *APIHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var req UserLogin
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Error decoding request JSON: %v", err)
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Invalid email or password",
+		})
+		return
+	}
+
+	handle := req.Handle
+	if handle == "" {
+		handle = defaultHandle()
+	} else if err := validateHandle(handle); err != nil {
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Invalid handle: " + err.Error(),
+		})
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		log.Printf("Error hashing password: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+
+	userParams := database.CreateUserParams{
+		Email:          req.Email,
+		HashedPassword: hashedPassword,
+		Handle:         handle,
+	}
+	user, err := h.cfg.DB.CreateUser(r.Context(), userParams)
+	if err != nil {
+		if isUniqueViolation(err, "idx_users_handle_lower") {
+			errJSON(w, http.StatusConflict, ErrMessage{
+				Message: "Handle already taken",
+			})
+			return
+		}
+		log.Printf("Error creating user: %v", err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, user)
+}
+
+var unknownUserPasswordHash = sync.OnceValue(func() string {
+	hash, err := auth.HashPassword(uuid.NewString())
+	if err != nil {
+		log.Printf("Error creating placeholder password hash: %v", err)
+	}
+	return hash
+})
+
+func (h *APIHandler) LoginUser(w http.ResponseWriter, r *http.Request) {
+	var req UserLogin
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Error decoding request JSON: %v", err)
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+
+	if len(req.DeviceName) > maxSessionNameLength {
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Device name is too long",
+		})
+		return
+	}
+
+	// Every credential failure gets the same 401, so responses do not reveal
+	// which emails have accounts.
+	userCreds, err := h.cfg.DB.GetUserPassByEmail(r.Context(), req.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Spend the same time hashing as for a real account.
+		auth.CheckPasswordHash(req.Password, unknownUserPasswordHash())
+		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Invalid email or password"})
+		return
+	}
+	if err != nil {
+		log.Printf("Error validating user: %s", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	val, err := auth.CheckPasswordHash(req.Password, userCreds)
+	if err != nil || !val {
+		log.Printf("Unauthorized User: %v", err)
+		errJSON(w, http.StatusUnauthorized, ErrMessage{Message: "Invalid email or password"})
+		return
+	}
+
+	user, err := h.cfg.DB.GetUserByEmail(r.Context(), req.Email)
+	if err != nil {
+		log.Printf("Error fetching user: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{Message: "Something went wrong"})
+		return
+	}
+
+	twoFactor, err := h.cfg.DB.GetUserTwoFactor(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error fetching 2FA state: %v", err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if twoFactor.TotpEnabledAt.Valid {
+		if twoFactor.TwoFactorLocked {
+			writeTwoFactorLocked(w)
+			return
+		}
+		h.startLoginChallenge(w, r, user.ID, req.DeviceName)
+		return
+	}
+
+	h.completeLogin(w, r, user, req.DeviceName)
+}
+
+// completeLogin issues the tokens for a user who has passed every login
+// step.
+func (h *APIHandler) completeLogin(w http.ResponseWriter, r *http.Request, user database.GetUserByEmailRow, deviceName string) {
+	// Logging in during the grace period cancels a pending deletion.
+	cancelled, err := h.cfg.DB.CancelUserDeletion(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error cancelling account deletion: %v", err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	jwtToken, err := h.makeAccessToken(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error creating JWT token: %v", err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	refreshToken, err := issueRefreshToken(r.Context(), h.cfg.DB, user.ID, deviceFromRequest(r, deviceName))
+	if err != nil {
+		log.Printf("Error storing refresh token: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+
+	type LoginResponse struct {
+		database.GetUserByEmailRow
+		Token             string `json:"token"`
+		RefreshToken      string `json:"refresh_token"`
+		DeletionCancelled bool   `json:"deletion_cancelled,omitempty"`
+	}
+
+	respondJSON(w, http.StatusOK, LoginResponse{
+		GetUserByEmailRow: user,
+		Token:             jwtToken,
+		RefreshToken:      refreshToken,
+		DeletionCancelled: cancelled > 0,
+	})
+}
+
+func (h *APIHandler) UpdateUserCred(w http.ResponseWriter, r *http.Request) {
+	var updateCred UserLogin
+	err := json.NewDecoder(r.Body).Decode(&updateCred)
+	if err != nil {
+		log.Printf("Error decoding request JSON: %v", err)
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Invalid email or password",
+		})
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var handle sql.NullString
+	if updateCred.Handle != "" {
+		if err := validateHandle(updateCred.Handle); err != nil {
+			errJSON(w, http.StatusBadRequest, ErrMessage{
+				Message: "Invalid handle: " + err.Error(),
+			})
+			return
+		}
+		handle = sql.NullString{String: updateCred.Handle, Valid: true}
+	}
+
+	hashedPassword, err := auth.HashPassword(updateCred.Password)
+	if err != nil {
+		log.Printf("Could not hash password: %v", err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	updatedUser, err := h.cfg.DB.UpdateUserCred(r.Context(), database.UpdateUserCredParams{
+		Email:          updateCred.Email,
+		HashedPassword: hashedPassword,
+		Handle:         handle,
+		ID:             userID,
+	})
+	if err != nil {
+		if isUniqueViolation(err, "idx_users_handle_lower") {
+			errJSON(w, http.StatusConflict, ErrMessage{
+				Message: "Handle already taken",
+			})
+			return
+		}
+		log.Printf("Could not update DB: %v", err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updatedUser)
+}
+
+func (h *APIHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	refreshToken, authErr := middleware.BearerToken(r)
+	if authErr != nil {
+		middleware.WriteAuthError(w, authErr)
+		return
+	}
+
+	newRefreshToken, rotated, err := rotateRefreshToken(r.Context(), h.cfg.DB, refreshToken, deviceFromRequest(r, ""))
+	if errors.Is(err, errRefreshTokenInvalid) || errors.Is(err, errRefreshTokenReused) {
+		log.Printf("Invalid refresh token: %v", err)
+		middleware.WriteAuthError(w, middleware.InvalidToken("Refresh token is invalid or expired"))
+		return
+	}
+	if err != nil {
+		log.Printf("Error rotating refresh token: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+
+	accessToken, err := h.makeAccessToken(r.Context(), rotated.UserID)
+	if err != nil {
+		log.Printf("Error creating access token: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+
+	type RefreshResponse struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	respondJSON(w, http.StatusOK, RefreshResponse{
+		Token:        accessToken,
+		RefreshToken: newRefreshToken,
+	})
+}
+
+func (h *APIHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	refreshToken, authErr := middleware.BearerToken(r)
+	if authErr != nil {
+		middleware.WriteAuthError(w, authErr)
+		return
+	}
+
+	err := h.cfg.DB.RevokeRefreshToken(r.Context(), auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		log.Printf("Error revoking token: %v", err)
+		errJSON(w, http.StatusInternalServerError, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteUser schedules the caller's account for deletion. The account is
+// hidden right away and purged after the grace period unless its owner
+// logs in again.
+func (h *APIHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	pathUserID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		log.Printf("Could not parse user ID: %v", err)
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if pathUserID != userID {
+		log.Print("UserID not matched with ID sent via path")
+		errJSON(w, http.StatusForbidden, ErrMessage{
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	deletedAt, err := h.cfg.DB.ScheduleUserDeletion(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error deleting user: %v", err)
+		http.Error(w, "Something went wrong", http.StatusNotFound)
+		return
+	}
+
+	if err := h.cfg.DB.RevokeUserRefreshTokens(r.Context(), userID); err != nil {
+		log.Printf("Error revoking refresh tokens: %v", err)
+	}
+
+	respondJSON(w, http.StatusAccepted, struct {
+		DeletedAt time.Time `json:"deleted_at"`
+		PurgeAt   time.Time `json:"purge_at"`
+	}{
+		DeletedAt: deletedAt.Time,
+		PurgeAt:   deletedAt.Time.Add(h.cfg.AccountGrace),
+	})
+}
+
+func (h *APIHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		log.Printf("Could not parse user ID: %v", err)
+		errJSON(w, http.StatusBadRequest, ErrMessage{
+			Message: "Something went wrong",
+		})
+		return
+	}
+
+	user, err := h.cfg.DB.GetUserByID(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error fetching user: %v", err)
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, PublicUser{
+		ID:          user.ID,
+		Handle:      user.Handle,
+		CreatedAt:   user.CreatedAt,
+		IsChirpyRed: user.IsChirpyRed,
+	})
+}
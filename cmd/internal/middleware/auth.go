@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+)
+
+const realm = "chirpy"
+
+// Identity is the authenticated caller of a request. Roles and scopes will
+// live here too once tokens carry them.
+type Identity struct {
+	UserID uuid.UUID
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying id.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// IdentityFrom returns the caller stored by an Authenticator, if any.
+func IdentityFrom(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// AuthError is a failed authentication, described in the terms of RFC 6750
+// so it can be turned into a WWW-Authenticate challenge.
+type AuthError struct {
+	Status      int
+	Code        string
+	Description string
+}
+
+func (e *AuthError) Error() string {
+	return e.Description
+}
+
+// ErrMissingToken is returned when a request carries no credentials at all.
+var ErrMissingToken = &AuthError{
+	Status:      http.StatusUnauthorized,
+	Description: "Authentication required",
+}
+
+// InvalidToken reports a bearer token that is expired, revoked or otherwise
+// unusable.
+func InvalidToken(description string) *AuthError {
+	return &AuthError{
+		Status:      http.StatusUnauthorized,
+		Code:        "invalid_token",
+		Description: description,
+	}
+}
+
+// WriteAuthError responds with e and the matching WWW-Authenticate challenge.
+func WriteAuthError(w http.ResponseWriter, e *AuthError) {
+	challenge := fmt.Sprintf("Bearer realm=%q", realm)
+	if e.Code != "" {
+		challenge += fmt.Sprintf(", error=%q, error_description=%q", e.Code, e.Description)
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	if err := json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{
+		Error: e.Description,
+	}); err != nil {
+		log.Printf("Error encoding response: %s", err)
+	}
+}
+
+// BearerToken extracts the bearer token from the Authorization header.
+func BearerToken(r *http.Request) (string, *AuthError) {
+	if r.Header.Get("Authorization") == "" {
+		return "", ErrMissingToken
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		return "", &AuthError{
+			Status:      http.StatusBadRequest,
+			Code:        "invalid_request",
+			Description: capitalize(err.Error()),
+		}
+	}
+
+	return token, nil
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// Authenticator validates access tokens once per request and stores the
+// caller's Identity in the request context for handlers to read.
+type Authenticator struct {
+	keys     *auth.KeySet
+	versions auth.TokenVersions
+}
+
+func NewAuthenticator(keys *auth.KeySet, versions auth.TokenVersions) *Authenticator {
+	return &Authenticator{
+		keys:     keys,
+		versions: versions,
+	}
+}
+
+// Required rejects requests without a valid access token.
+func (a *Authenticator) Required(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, authErr := a.authenticate(r)
+		if authErr != nil {
+			WriteAuthError(w, authErr)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), id)))
+	})
+}
+
+// Optional lets anonymous requests through. A token that is present but
+// invalid is still rejected, so clients learn they need to refresh it
+// instead of silently getting the anonymous view.
+func (a *Authenticator) Optional(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		id, authErr := a.authenticate(r)
+		if authErr != nil {
+			WriteAuthError(w, authErr)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithIdentity(r.Context(), id)))
+	})
+}
+
+func (a *Authenticator) authenticate(r *http.Request) (Identity, *AuthError) {
+	token, authErr := BearerToken(r)
+	if authErr != nil {
+		return Identity{}, authErr
+	}
+
+	userID, err := auth.ValidateJWT(r.Context(), token, a.keys, a.versions)
+	if err != nil {
+		log.Printf("Invalid access token: %v", err)
+		return Identity{}, InvalidToken("Access token is invalid or expired")
+	}
+
+	return Identity{UserID: userID}, nil
+}
+
+// Admins reports whether a user may use the admin endpoints.
+// *database.Queries satisfies it.
+type Admins interface {
+	GetUserIsAdmin(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// ErrNotAdmin is returned when an authenticated caller lacks admin rights.
+var ErrNotAdmin = &AuthError{
+	Status:      http.StatusForbidden,
+	Code:        "insufficient_scope",
+	Description: "Admin access required",
+}
+
+// RequireAdmin only lets admins through. It must run inside
+// Authenticator.Required, which provides the caller's Identity.
+func RequireAdmin(admins Admins, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := IdentityFrom(r.Context())
+		if !ok {
+			WriteAuthError(w, ErrMissingToken)
+			return
+		}
+
+		isAdmin, err := admins.GetUserIsAdmin(r.Context(), id.UserID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Error checking admin rights: %v", err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+		if !isAdmin {
+			WriteAuthError(w, ErrNotAdmin)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+)
+
+type versionMap map[uuid.UUID]int32
+
+func (m versionMap) GetUserTokenVersion(_ context.Context, id uuid.UUID) (int32, error) {
+	return m[id], nil
+}
+
+func TestAuthenticator(t *testing.T) {
+	keys := auth.NewHMACKeySet("test-secret", auth.DefaultIssuer, auth.DefaultAudience)
+	userID := uuid.New()
+	revokedID := uuid.New()
+	versions := versionMap{revokedID: 1}
+
+	valid, err := auth.MakeJWT(userID, 0, keys, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+	expired, err := auth.MakeJWT(userID, 0, keys, -time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+	revoked, err := auth.MakeJWT(revokedID, 0, keys, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		optional      bool
+		authorization string
+		wantStatus    int
+		wantChallenge string
+		wantIdentity  bool
+	}{
+		{name: "required valid", authorization: "Bearer " + valid, wantStatus: http.StatusOK, wantIdentity: true},
+		{name: "required missing", wantStatus: http.StatusUnauthorized, wantChallenge: `Bearer realm="chirpy"`},
+		{name: "required wrong scheme", authorization: "Basic abc", wantStatus: http.StatusBadRequest, wantChallenge: `error="invalid_request"`},
+		{name: "required expired", authorization: "Bearer " + expired, wantStatus: http.StatusUnauthorized, wantChallenge: `error="invalid_token"`},
+		{name: "required revoked", authorization: "Bearer " + revoked, wantStatus: http.StatusUnauthorized, wantChallenge: `error="invalid_token"`},
+		{name: "optional anonymous", optional: true, wantStatus: http.StatusOK},
+		{name: "optional valid", optional: true, authorization: "Bearer " + valid, wantStatus: http.StatusOK, wantIdentity: true},
+		{name: "optional expired", optional: true, authorization: "Bearer " + expired, wantStatus: http.StatusUnauthorized, wantChallenge: `error="invalid_token"`},
+	}
+
+	authn := NewAuthenticator(keys, versions)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIdentity *Identity
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if id, ok := IdentityFrom(r.Context()); ok {
+					gotIdentity = &id
+				}
+			})
+
+			h := authn.Required(next)
+			if tt.optional {
+				h = authn.Optional(next)
+			}
+
+			r := httptest.NewRequest("GET", "/api/me/profile", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if challenge := w.Header().Get("WWW-Authenticate"); !strings.Contains(challenge, tt.wantChallenge) ||
+				(tt.wantChallenge == "") != (challenge == "") {
+				t.Errorf("WWW-Authenticate = %q, want it to contain %q", challenge, tt.wantChallenge)
+			}
+			if tt.wantIdentity {
+				if gotIdentity == nil || gotIdentity.UserID != userID {
+					t.Errorf("identity = %v, want user %v", gotIdentity, userID)
+				}
+			} else if gotIdentity != nil {
+				t.Errorf("identity = %v, want none", gotIdentity)
+			}
+		})
+	}
+}
+
+type adminSet map[uuid.UUID]bool
+
+func (s adminSet) GetUserIsAdmin(_ context.Context, id uuid.UUID) (bool, error) {
+	return s[id], nil
+}
+
+func TestRequireAdmin(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	admins := adminSet{adminID: true}
+
+	tests := []struct {
+		name       string
+		identity   *Identity
+		wantStatus int
+	}{
+		{name: "admin", identity: &Identity{UserID: adminID}, wantStatus: http.StatusOK},
+		{name: "regular user", identity: &Identity{UserID: userID}, wantStatus: http.StatusForbidden},
+		{name: "unauthenticated", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RequireAdmin(admins, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			r := httptest.NewRequest("GET", "/admin/moderation/flags", nil)
+			if tt.identity != nil {
+				r = r.WithContext(WithIdentity(r.Context(), *tt.identity))
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
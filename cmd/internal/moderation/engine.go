@@ -0,0 +1,160 @@
+package moderation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Action is what happens to a chirp containing a listed word.
+type Action string
+
+const (
+	// ActionMask replaces the word with Mask and lets the chirp through.
+	ActionMask Action = "mask"
+	// ActionReject refuses the chirp.
+	ActionReject Action = "reject"
+	// ActionFlag lets the chirp through unchanged and queues it for review.
+	ActionFlag Action = "flag"
+)
+
+const Mask = "****"
+
+func ParseAction(s string) (Action, error) {
+	switch a := Action(strings.ToLower(strings.TrimSpace(s))); a {
+	case ActionMask, ActionReject, ActionFlag:
+		return a, nil
+	case "":
+		return ActionMask, nil
+	default:
+		return "", fmt.Errorf("unknown action %q", s)
+	}
+}
+
+// Rule is one entry of a word list.
+type Rule struct {
+	Word   string
+	Action Action
+}
+
+// DefaultRules is the list used until a source has been loaded.
+var DefaultRules = []Rule{
+	{Word: "kerfuffle", Action: ActionMask},
+	{Word: "sharbert", Action: ActionMask},
+	{Word: "fornax", Action: ActionMask},
+}
+
+// Result is the outcome of checking a chirp body.
+type Result struct {
+	// Text is the body with masked words replaced.
+	Text string
+	// Rejected is set when a word with ActionReject was found.
+	Rejected bool
+	// Flagged lists the folded words with ActionFlag that were found.
+	Flagged []string
+}
+
+// Engine checks text against a word list that can be swapped at runtime.
+// It is safe for concurrent use.
+type Engine struct {
+	mu    sync.RWMutex
+	rules map[string]Action
+}
+
+func NewEngine(rules []Rule) *Engine {
+	e := &Engine{}
+	e.Replace(rules)
+	return e
+}
+
+// Replace swaps in a new word list. Words are folded so the list can be
+// written in plain lower-case ASCII. When a word is listed more than once
+// the strictest action wins.
+func (e *Engine) Replace(rules []Rule) {
+	compiled := make(map[string]Action, len(rules))
+	for _, rule := range rules {
+		word := Fold(rule.Word)
+		if word == "" {
+			continue
+		}
+		if existing, ok := compiled[word]; !ok || severity(rule.Action) > severity(existing) {
+			compiled[word] = rule.Action
+		}
+	}
+
+	e.mu.Lock()
+	e.rules = compiled
+	e.mu.Unlock()
+}
+
+// Len returns the number of words in the active list.
+func (e *Engine) Len() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.rules)
+}
+
+func severity(a Action) int {
+	switch a {
+	case ActionReject:
+		return 2
+	case ActionFlag:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Check runs text through the word list. Words are split on any Unicode
+// white space and matched after stripping surrounding punctuation and
+// folding, so the original spacing and punctuation survive masking.
+func (e *Engine) Check(text string) Result {
+	e.mu.RLock()
+	rules := e.rules
+	e.mu.RUnlock()
+
+	var b strings.Builder
+	b.Grow(len(text))
+	result := Result{}
+
+	rest := text
+	for len(rest) > 0 {
+		// Copy white space through untouched.
+		wordStart := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsSpace(r) })
+		if wordStart < 0 {
+			b.WriteString(rest)
+			break
+		}
+		b.WriteString(rest[:wordStart])
+		rest = rest[wordStart:]
+
+		wordEnd := strings.IndexFunc(rest, unicode.IsSpace)
+		if wordEnd < 0 {
+			wordEnd = len(rest)
+		}
+		word := rest[:wordEnd]
+		rest = rest[wordEnd:]
+
+		leading, core, trailing := splitEdges(word)
+		action, ok := rules[Fold(core)]
+		if !ok || core == "" {
+			b.WriteString(word)
+			continue
+		}
+
+		switch action {
+		case ActionReject:
+			result.Rejected = true
+			b.WriteString(word)
+		case ActionFlag:
+			result.Flagged = append(result.Flagged, Fold(core))
+			b.WriteString(word)
+		default:
+			b.WriteString(leading + Mask + trailing)
+		}
+	}
+
+	result.Text = b.String()
+	return result
+}
@@ -0,0 +1,232 @@
+package moderation
+
+import (
+	"bufio"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckMasksDefaultWords(t *testing.T) {
+	engine := NewEngine(DefaultRules)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "single profane word",
+			input:    "This is kerfuffle",
+			expected: "This is ****",
+		},
+		{
+			name:     "multiple profane words",
+			input:    "What a kerfuffle and sharbert situation",
+			expected: "What a **** and **** situation",
+		},
+		{
+			name:     "profane word with punctuation",
+			input:    "This is a kerfuffle!",
+			expected: "This is a ****!",
+		},
+		{
+			name:     "profane word with mixed case",
+			input:    "This is a KERFUFFLE test",
+			expected: "This is a **** test",
+		},
+		{
+			name:     "profane word at start",
+			input:    "Kerfuffle is happening",
+			expected: "**** is happening",
+		},
+		{
+			name:     "profane word at end",
+			input:    "What a fornax",
+			expected: "What a ****",
+		},
+		{
+			name:     "no profane words",
+			input:    "This is a clean message",
+			expected: "This is a clean message",
+		},
+		{
+			name:     "empty string",
+			input:    "",
+			expected: "",
+		},
+		{
+			name:     "all profane words",
+			input:    "kerfuffle sharbert fornax",
+			expected: "**** **** ****",
+		},
+		{
+			name:     "profane word with multiple punctuation",
+			input:    "What the kerfuffle?!?",
+			expected: "What the ****?!?",
+		},
+		{
+			name:     "profane word in middle of sentence",
+			input:    "I think kerfuffle, you know what I mean",
+			expected: "I think ****, you know what I mean",
+		},
+		{
+			name:     "separated by newline",
+			input:    "what a\nkerfuffle\ttoday",
+			expected: "what a\n****\ttoday",
+		},
+		{
+			name:     "fullwidth letters",
+			input:    "ｋｅｒｆｕｆｆｌｅ!",
+			expected: "****!",
+		},
+		{
+			name:     "diacritics",
+			input:    "such a kérfüffle",
+			expected: "such a ****",
+		},
+		{
+			name:     "cyrillic look-alikes",
+			input:    "sharbеrt", // Cyrillic е
+			expected: "****",
+		},
+		{
+			name:     "leetspeak",
+			input:    "k3rfuffl3 and f0rn@x",
+			expected: "**** and ****",
+		},
+		{
+			name:     "quoted",
+			input:    `he said "fornax"`,
+			expected: `he said "****"`,
+		},
+		{
+			name:     "part of a longer word",
+			input:    "kerfuffles happen",
+			expected: "kerfuffles happen",
+		},
+		{
+			name:     "numbers are not folded",
+			input:    "call 5348",
+			expected: "call 5348",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := engine.Check(tt.input)
+			if result.Text != tt.expected {
+				t.Errorf("Check(%q).Text = %q, want %q", tt.input, result.Text, tt.expected)
+			}
+			if result.Rejected || len(result.Flagged) != 0 {
+				t.Errorf("Check(%q) = %+v, want only masking", tt.input, result)
+			}
+		})
+	}
+}
+
+func TestCheckActions(t *testing.T) {
+	engine := NewEngine([]Rule{
+		{Word: "kerfuffle", Action: ActionMask},
+		{Word: "sharbert", Action: ActionReject},
+		{Word: "fornax", Action: ActionFlag},
+		// The strictest action wins for duplicates.
+		{Word: "FORNAX", Action: ActionMask},
+	})
+
+	tests := []struct {
+		name         string
+		input        string
+		wantText     string
+		wantRejected bool
+		wantFlagged  []string
+	}{
+		{
+			name:     "mask",
+			input:    "a kerfuffle",
+			wantText: "a ****",
+		},
+		{
+			name:         "reject",
+			input:        "a Sh@rbert",
+			wantText:     "a Sh@rbert",
+			wantRejected: true,
+		},
+		{
+			name:        "flag keeps text",
+			input:       "a fornax, a kerfuffle",
+			wantText:    "a fornax, a ****",
+			wantFlagged: []string{"fornax"},
+		},
+		{
+			name:     "clean",
+			input:    "nothing to see",
+			wantText: "nothing to see",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := engine.Check(tt.input)
+			if result.Text != tt.wantText {
+				t.Errorf("Text = %q, want %q", result.Text, tt.wantText)
+			}
+			if result.Rejected != tt.wantRejected {
+				t.Errorf("Rejected = %v, want %v", result.Rejected, tt.wantRejected)
+			}
+			if !slices.Equal(result.Flagged, tt.wantFlagged) {
+				t.Errorf("Flagged = %v, want %v", result.Flagged, tt.wantFlagged)
+			}
+		})
+	}
+}
+
+func TestReplace(t *testing.T) {
+	engine := NewEngine(DefaultRules)
+	engine.Replace([]Rule{{Word: "gadzooks", Action: ActionMask}})
+
+	if got := engine.Check("kerfuffle gadzooks").Text; got != "kerfuffle ****" {
+		t.Errorf("Check after Replace = %q, want %q", got, "kerfuffle ****")
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Rule
+		wantErr bool
+	}{
+		{
+			name:  "words with and without actions",
+			input: "# list\n\nkerfuffle\nsharbert reject\n  fornax FLAG  \n",
+			want: []Rule{
+				{Word: "kerfuffle", Action: ActionMask},
+				{Word: "sharbert", Action: ActionReject},
+				{Word: "fornax", Action: ActionFlag},
+			},
+		},
+		{
+			name:    "unknown action",
+			input:   "kerfuffle delete\n",
+			wantErr: true,
+		},
+		{
+			name:    "too many fields",
+			input:   "kerfuffle mask now\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRules(bufio.NewScanner(strings.NewReader(tt.input)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
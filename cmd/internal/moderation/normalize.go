@@ -0,0 +1,99 @@
+package moderation
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps letters that are commonly swapped in to dodge filters
+// onto the Latin letter they imitate. NFKC already handles fullwidth,
+// circled, mathematical and similar compatibility forms; this covers the
+// Cyrillic and Greek look-alikes it leaves untouched.
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'ё': 'e', 'к': 'k', 'м': 'm', 'н': 'h',
+	'о': 'o', 'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'і': 'i',
+	'ї': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd', 'һ': 'h', 'ԛ': 'q',
+	'ԝ': 'w',
+	// Greek
+	'α': 'a', 'β': 'b', 'γ': 'y', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k',
+	'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'ω': 'w',
+	// Latin
+	'ı': 'i', 'ɡ': 'g', 'ł': 'l', 'ø': 'o', 'đ': 'd', 'ß': 's',
+}
+
+// leetspeak maps digits and symbols used as letters. It is only applied to
+// tokens that also contain a letter, so plain numbers are left alone.
+var leetspeak = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b',
+	'9': 'g', '@': 'a', '$': 's', '|': 'l', '+': 't',
+}
+
+// Fold reduces a word to the form word lists are matched in: NFKC
+// normalized, stripped of diacritics, lower-cased, with confusable letters
+// and leetspeak replaced by the Latin letters they stand for, so both
+// "Ｋé𝐫fuffle" and "k3rfuffl3" fold to "kerfuffle".
+func Fold(word string) string {
+	// NFKC first so compatibility characters decompose to their base
+	// letters, then NFD to split off combining marks for removal.
+	word = norm.NFD.String(norm.NFKC.String(word))
+
+	hasLetter := false
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+			break
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(word))
+	for _, r := range word {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+		if mapped, ok := confusables[r]; ok {
+			r = mapped
+		} else if mapped, ok := leetspeak[r]; ok && hasLetter {
+			r = mapped
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isEdge reports whether r is trimmed from the ends of a word before it is
+// matched. Symbols that double as leetspeak letters are kept.
+func isEdge(r rune) bool {
+	if _, ok := leetspeak[r]; ok && !unicode.IsDigit(r) {
+		return false
+	}
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// splitEdges separates a word into leading punctuation, the core word and
+// trailing punctuation, e.g. `"hello!"` into `"`, `hello`, `!"`.
+func splitEdges(word string) (leading, core, trailing string) {
+	start := strings.IndexFunc(word, func(r rune) bool { return !isEdge(r) })
+	if start < 0 {
+		return word, "", ""
+	}
+	end := strings.LastIndexFunc(word, func(r rune) bool { return !isEdge(r) })
+	_, size := utf8.DecodeRuneInString(word[end:])
+	end += size
+	return word[:start], word[start:end], word[end:]
+}
+
+// SplitHashtag is splitEdges for text where hashtags matter: a '#' right
+// before the core word stays with it, e.g. `(#go)` into `(`, `#go`, `)`.
+func SplitHashtag(word string) (leading, core, trailing string) {
+	leading, core, trailing = splitEdges(word)
+	if core != "" && strings.HasSuffix(leading, "#") {
+		leading, core = leading[:len(leading)-1], "#"+core
+	}
+	return leading, core, trailing
+}
@@ -0,0 +1,154 @@
+package moderation
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const DefaultReloadInterval = 30 * time.Second
+
+// Source provides a word list. Stamp returns a value that changes whenever
+// the list does, so a reload can be skipped when nothing changed.
+type Source interface {
+	Stamp(ctx context.Context) (string, error)
+	Load(ctx context.Context) ([]Rule, error)
+}
+
+// FileSource reads a word list from a text file with one word per line,
+// optionally followed by an action:
+//
+//	# comments and blank lines are ignored
+//	kerfuffle
+//	sharbert reject
+//	fornax flag
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) Stamp(ctx context.Context) (string, error) {
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size()), nil
+}
+
+func (s FileSource) Load(ctx context.Context) ([]Rule, error) {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseRules(bufio.NewScanner(f))
+}
+
+func parseRules(scanner *bufio.Scanner) ([]Rule, error) {
+	var rules []Rule
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected a word and an optional action", line)
+		}
+
+		action := ActionMask
+		if len(fields) == 2 {
+			var err error
+			action, err = ParseAction(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+
+		rules = append(rules, Rule{Word: fields[0], Action: action})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// DBSource reads the word list from the filter_words table.
+type DBSource struct {
+	DB *database.Queries
+}
+
+func (s DBSource) Stamp(ctx context.Context) (string, error) {
+	return s.DB.GetFilterWordsStamp(ctx)
+}
+
+func (s DBSource) Load(ctx context.Context) ([]Rule, error) {
+	rows, err := s.DB.GetFilterWords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]Rule, 0, len(rows))
+	for _, row := range rows {
+		action, err := ParseAction(row.Action)
+		if err != nil {
+			return nil, fmt.Errorf("word %q: %w", row.Word, err)
+		}
+		rules = append(rules, Rule{Word: row.Word, Action: action})
+	}
+	return rules, nil
+}
+
+// Watch loads src into the engine and reloads it every interval until ctx
+// is cancelled. A list that fails to load is logged and the previous one
+// stays active.
+func (e *Engine) Watch(ctx context.Context, src Source, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+
+	var lastStamp string
+	reload := func() {
+		stamp, err := src.Stamp(ctx)
+		if err != nil {
+			log.Printf("Error checking content filter list: %v", err)
+			return
+		}
+		if stamp == lastStamp {
+			return
+		}
+
+		rules, err := src.Load(ctx)
+		if err != nil {
+			log.Printf("Error loading content filter list: %v", err)
+			return
+		}
+
+		e.Replace(rules)
+		lastStamp = stamp
+		log.Printf("Loaded %d content filter words", e.Len())
+	}
+
+	reload()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reload()
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package purge
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const (
+	DefaultInterval = time.Hour
+	runTimeout      = 5 * time.Minute
+)
+
+// Purger hard-deletes chirps that have sat in the trash longer than the
+// retention period, accounts whose deletion grace period has passed, and
+// refresh tokens, sessions and login challenges that have expired.
+type Purger struct {
+	db             *database.Queries
+	chirpRetention time.Duration
+	accountGrace   time.Duration
+}
+
+func NewPurger(db *database.Queries, chirpRetention, accountGrace time.Duration) *Purger {
+	return &Purger{
+		db:             db,
+		chirpRetention: chirpRetention,
+		accountGrace:   accountGrace,
+	}
+}
+
+// Run purges once at start and then every interval until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	p.purge(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	chirps, err := p.db.PurgeDeletedChirps(ctx, int32(p.chirpRetention/time.Second))
+	if err != nil {
+		log.Printf("Error purging deleted chirps: %v", err)
+	} else if chirps > 0 {
+		log.Printf("Purged %d deleted chirps", chirps)
+	}
+
+	users, err := p.db.PurgeDeletedUsers(ctx, int32(p.accountGrace/time.Second))
+	if err != nil {
+		log.Printf("Error purging deleted accounts: %v", err)
+	} else if users > 0 {
+		log.Printf("Purged %d deleted accounts", users)
+	}
+
+	tokens, err := p.db.PurgeExpiredRefreshTokens(ctx)
+	if err != nil {
+		log.Printf("Error purging expired refresh tokens: %v", err)
+	} else if tokens > 0 {
+		log.Printf("Purged %d expired refresh tokens", tokens)
+	}
+
+	sessions, err := p.db.PurgeExpiredSessions(ctx)
+	if err != nil {
+		log.Printf("Error purging expired sessions: %v", err)
+	} else if sessions > 0 {
+		log.Printf("Purged %d expired sessions", sessions)
+	}
+
+	challenges, err := p.db.PurgeExpiredLoginChallenges(ctx)
+	if err != nil {
+		log.Printf("Error purging expired login challenges: %v", err)
+	} else if challenges > 0 {
+		log.Printf("Purged %d expired login challenges", challenges)
+	}
+}
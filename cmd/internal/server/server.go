@@ -1,28 +1,55 @@
 package server
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
 	"github.com/shubh-man007/Chirpy/cmd/internal/config"
 	"github.com/shubh-man007/Chirpy/cmd/internal/database"
 	"github.com/shubh-man007/Chirpy/cmd/internal/handler"
 	"github.com/shubh-man007/Chirpy/cmd/internal/middleware"
+	"github.com/shubh-man007/Chirpy/cmd/internal/moderation"
+	"github.com/shubh-man007/Chirpy/cmd/internal/purge"
+	"github.com/shubh-man007/Chirpy/cmd/internal/storage"
+	"github.com/shubh-man007/Chirpy/cmd/internal/stream"
+	"github.com/shubh-man007/Chirpy/cmd/internal/timeline"
 )
 
+const assetsDir = "../assets"
+
 type Server struct {
-	Port       string
-	apiCfg     *config.ApiConfig
-	httpServer *http.Server
+	Port         string
+	apiCfg       *config.ApiConfig
+	httpServer   *http.Server
+	filterSource moderation.Source
 }
 
-func New(port string, db *database.Queries, platform string, jwtSecret string, polkaAPI string) *Server {
-	cfg := config.NewApiCfg(db, platform, jwtSecret, polkaAPI)
+// New builds the server. The content filter word list is read from
+// filterFile when it is set and from the filter_words table otherwise.
+func New(port string, db *database.Queries, platform string, keys *auth.KeySet, polkaAPI string, filterFile string, editWindow time.Duration, totpSecrets *auth.SecretBox) *Server {
+	cfg := config.NewApiCfg(db, platform, keys, polkaAPI)
 	cfg.FileserverHits.Store(0)
+	cfg.Blobs = storage.NewLocalStore(assetsDir, "/assets")
+	cfg.Stream = stream.NewHub(stream.DefaultBufferSize, stream.DefaultBacklogSize)
+	cfg.Timeline = timeline.NewWorker(db, timeline.DefaultQueueSize)
+	cfg.Filter = moderation.NewEngine(moderation.DefaultRules)
+	cfg.EditWindow = editWindow
+	cfg.TOTPSecrets = totpSecrets
+	cfg.TrashRetention = config.DefaultTrashRetention
+	cfg.AccountGrace = config.DefaultAccountGracePeriod
+
+	var filterSource moderation.Source = moderation.DBSource{DB: db}
+	if filterFile != "" {
+		filterSource = moderation.FileSource{Path: filterFile}
+	}
 
 	return &Server{
-		Port:   port,
-		apiCfg: cfg,
+		Port:         port,
+		apiCfg:       cfg,
+		filterSource: filterSource,
 	}
 }
 
@@ -30,7 +57,7 @@ func (s *Server) Routes() http.Handler {
 	mux := http.NewServeMux()
 
 	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("../static"))))
-	mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.Dir("../assets"))))
+	mux.Handle("/assets/", http.StripPrefix("/assets/", http.FileServer(http.Dir(assetsDir))))
 
 	//app:
 	fileserver := http.FileServer(http.Dir("../static"))
@@ -39,25 +66,66 @@ func (s *Server) Routes() http.Handler {
 	//api:
 	apiHandler := handler.NewAPIHandler(s.apiCfg)
 
+	authn := middleware.NewAuthenticator(s.apiCfg.Keys, s.apiCfg.DB)
+	required := func(h http.HandlerFunc) http.Handler { return authn.Required(h) }
+	optional := func(h http.HandlerFunc) http.Handler { return authn.Optional(h) }
+	admin := func(h http.HandlerFunc) http.Handler {
+		return authn.Required(middleware.RequireAdmin(s.apiCfg.DB, h))
+	}
+
 	//readiness
 	mux.HandleFunc("GET /api/healthz", handler.Health)
 
 	//users:
 	mux.HandleFunc("POST /api/login", apiHandler.LoginUser)
+	mux.HandleFunc("POST /api/login/2fa", apiHandler.CompleteTwoFactorLogin)
 	mux.HandleFunc("POST /api/users", apiHandler.CreateUser)
-	mux.HandleFunc("GET /api/me/profile", apiHandler.GetMyProfile)
-	mux.HandleFunc("GET /api/users/{userID}/profile", apiHandler.GetProfileByUserID)
-	mux.HandleFunc("PUT /api/users", apiHandler.UpdateUserCred)
-	mux.HandleFunc("DELETE /api/users/{userID}", apiHandler.DeleteUser)
+	mux.Handle("GET /api/me/profile", required(apiHandler.GetMyProfile))
+	mux.Handle("PATCH /api/me/profile", required(apiHandler.UpdateMyProfile))
+	mux.Handle("PUT /api/me/avatar", required(apiHandler.UploadAvatar))
+	mux.Handle("DELETE /api/me/avatar", required(apiHandler.DeleteAvatar))
+	mux.Handle("GET /api/users/{userID}/profile", optional(apiHandler.GetProfileByUserID))
+	mux.Handle("GET /api/handles/{handle}", optional(apiHandler.GetProfileByHandle))
+	mux.Handle("PUT /api/users", required(apiHandler.UpdateUserCred))
+	mux.Handle("DELETE /api/users/{userID}", required(apiHandler.DeleteUser))
 
 	//auth:
 	mux.HandleFunc("POST /api/refresh", apiHandler.RefreshToken)
 	mux.HandleFunc("POST /api/revoke", apiHandler.RevokeToken)
+	mux.HandleFunc("GET /.well-known/jwks.json", apiHandler.JWKS)
+	mux.Handle("GET /api/me/sessions", required(apiHandler.GetSessions))
+	mux.Handle("DELETE /api/me/sessions/{sessionID}", required(apiHandler.RevokeSession))
+	mux.Handle("POST /api/me/sessions/revoke-all", required(apiHandler.RevokeAllSessions))
+
+	// two-factor:
+	mux.Handle("POST /api/me/2fa/enroll", required(apiHandler.EnrollTOTP))
+	mux.Handle("POST /api/me/2fa/verify", required(apiHandler.VerifyTOTP))
+	mux.Handle("POST /api/me/2fa/disable", required(apiHandler.DisableTOTP))
 
 	//chirps:
-	mux.HandleFunc("POST /api/chirps", apiHandler.CreateChirp)
-	mux.HandleFunc("PATCH /api/chirps/{chirpID}", apiHandler.UpdateChirp)
-	mux.HandleFunc("DELETE /api/chirps/{chirpID}", apiHandler.DeleteChirp)
+	mux.Handle("GET /api/chirps", optional(apiHandler.GetAllChirps))
+	mux.Handle("POST /api/chirps", required(apiHandler.CreateChirp))
+	mux.Handle("GET /api/chirps/{chirpID}", optional(apiHandler.GetChirpByChirpID))
+	mux.Handle("GET /api/me/chirps", required(apiHandler.GetMyChirps))
+	mux.Handle("GET /api/users/{userID}/chirps", optional(apiHandler.GetChirpsByUser))
+	mux.Handle("GET /api/chirps/{chirpID}/thread", optional(apiHandler.GetChirpThread))
+	mux.Handle("GET /api/chirps/{chirpID}/history", optional(apiHandler.GetChirpHistory))
+	mux.Handle("PATCH /api/chirps/{chirpID}", required(apiHandler.UpdateChirp))
+	mux.Handle("DELETE /api/chirps/{chirpID}", required(apiHandler.DeleteChirp))
+
+	// trash:
+	mux.Handle("GET /api/me/trash", required(apiHandler.GetTrash))
+	mux.Handle("POST /api/me/trash/{chirpID}/restore", required(apiHandler.RestoreChirp))
+
+	// rechirps:
+	mux.Handle("POST /api/chirps/{chirpID}/rechirp", required(apiHandler.Rechirp))
+	mux.Handle("DELETE /api/chirps/{chirpID}/rechirp", required(apiHandler.UndoRechirp))
+
+	// likes:
+	mux.Handle("POST /api/chirps/{chirpID}/like", required(apiHandler.LikeChirp))
+	mux.Handle("DELETE /api/chirps/{chirpID}/like", required(apiHandler.UnlikeChirp))
+	mux.Handle("GET /api/chirps/{chirpID}/likes", optional(apiHandler.GetChirpLikes))
+	mux.Handle("GET /api/users/{userID}/likes", optional(apiHandler.GetUserLikes))
 
 	// friends:
 	// mux.HandleFunc("POST /api/friends/request", apiHandler.SendFriendRequest)
@@ -69,13 +137,51 @@ func (s *Server) Routes() http.Handler {
 	// mux.HandleFunc("GET /api/friends/sent", apiHandler.GetSentFriendRequests)
 
 	// follows:
-	mux.HandleFunc("POST /api/follow", apiHandler.FollowUser)
-	mux.HandleFunc("DELETE /api/follow/{userID}", apiHandler.UnfollowUser)
-	mux.HandleFunc("GET /api/followers", apiHandler.GetFollowers)
-	mux.HandleFunc("GET /api/following", apiHandler.GetFollowing)
+	mux.Handle("POST /api/follow", required(apiHandler.FollowUser))
+	mux.Handle("DELETE /api/follow/{userID}", required(apiHandler.UnfollowUser))
+	mux.Handle("GET /api/followers", required(apiHandler.GetFollowers))
+	mux.Handle("GET /api/following", required(apiHandler.GetFollowing))
+
+	// private accounts:
+	mux.Handle("PUT /api/me/privacy", required(apiHandler.UpdateMyPrivacy))
+	mux.Handle("GET /api/me/follow-requests", required(apiHandler.GetFollowRequests))
+	mux.Handle("POST /api/me/follow-requests/{userID}/accept", required(apiHandler.AcceptFollowRequest))
+	mux.Handle("POST /api/me/follow-requests/{userID}/reject", required(apiHandler.RejectFollowRequest))
+
+	// blocks and mutes:
+	mux.Handle("GET /api/me/blocks", required(apiHandler.GetBlockedUsers))
+	mux.Handle("POST /api/me/blocks", required(apiHandler.BlockUser))
+	mux.Handle("DELETE /api/me/blocks/{userID}", required(apiHandler.UnblockUser))
+	mux.Handle("GET /api/me/mutes", required(apiHandler.GetMutedUsers))
+	mux.Handle("POST /api/me/mutes", required(apiHandler.MuteUser))
+	mux.Handle("DELETE /api/me/mutes/{userID}", required(apiHandler.UnmuteUser))
+	mux.Handle("GET /api/me/muted-words", required(apiHandler.GetMutedWords))
+	mux.Handle("POST /api/me/muted-words", required(apiHandler.CreateMutedWord))
+	mux.Handle("DELETE /api/me/muted-words/{wordID}", required(apiHandler.DeleteMutedWord))
+
+	// hashtags:
+	mux.Handle("GET /api/hashtags/{tag}/chirps", optional(apiHandler.GetHashtagChirps))
+	mux.HandleFunc("GET /api/trending", apiHandler.GetTrending)
+
+	// mentions:
+	mux.Handle("GET /api/me/mentions", required(apiHandler.GetMyMentions))
+
+	// notifications:
+	mux.Handle("GET /api/notifications", required(apiHandler.GetNotifications))
+	mux.Handle("GET /api/notifications/unread", required(apiHandler.GetUnreadNotificationCount))
+	mux.Handle("POST /api/notifications/read", required(apiHandler.MarkNotificationsRead))
+	mux.Handle("GET /api/notifications/preferences", required(apiHandler.GetNotificationPreferences))
+	mux.Handle("PUT /api/notifications/preferences", required(apiHandler.UpdateNotificationPreferences))
+
+	// stream:
+	mux.Handle("GET /api/stream", required(apiHandler.Stream))
+
+	// search:
+	mux.Handle("GET /api/search/chirps", optional(apiHandler.SearchChirps))
+	mux.Handle("GET /api/search/users", optional(apiHandler.SearchUsers))
 
 	// feed:
-	mux.HandleFunc("GET /api/feed", apiHandler.GetFeed)
+	mux.Handle("GET /api/feed", required(apiHandler.GetFeed))
 
 	// membership:
 	mux.HandleFunc("POST /api/polka/webhooks", apiHandler.UpdateUserMembership)
@@ -84,6 +190,8 @@ func (s *Server) Routes() http.Handler {
 	adminHandler := handler.NewAdminHandler(s.apiCfg)
 	mux.HandleFunc("GET /admin/metrics", adminHandler.Metrics)
 	mux.HandleFunc("POST /admin/reset", adminHandler.Reset)
+	mux.Handle("GET /admin/moderation/flags", admin(adminHandler.GetFlaggedChirps))
+	mux.Handle("DELETE /admin/moderation/flags/{chirpID}", admin(adminHandler.ClearChirpFlag))
 
 	return middleware.LogMiddleware(mux)
 }
@@ -94,6 +202,11 @@ func (s *Server) Start() error {
 		Handler: s.Routes(),
 	}
 
+	go s.apiCfg.Keys.Watch(context.Background(), auth.DefaultKeyReloadInterval)
+	go s.apiCfg.Timeline.Run(context.Background())
+	go s.apiCfg.Filter.Watch(context.Background(), s.filterSource, moderation.DefaultReloadInterval)
+	go purge.NewPurger(s.apiCfg.DB, s.apiCfg.TrashRetention, s.apiCfg.AccountGrace).Run(context.Background(), purge.DefaultInterval)
+
 	log.Printf("Running server at port:%s", s.Port)
 	return s.httpServer.ListenAndServe()
 }
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/auth"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+// fakeResult answers one query. Queries without an entry return no rows.
+type fakeResult func(args []driver.Value) (columns []string, rows [][]driver.Value)
+
+// fakeDriver serves canned results keyed by sqlc query name, so routes can be
+// exercised end to end without a database.
+type fakeDriver struct {
+	results map[string]fakeResult
+}
+
+var (
+	queryName    = regexp.MustCompile(`-- name: (\w+)`)
+	registerOnce sync.Once
+	fakeDB       = &fakeDriver{}
+)
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.d, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(0), nil }
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows := &fakeRows{}
+	if m := queryName.FindStringSubmatch(s.query); m != nil {
+		if result, ok := s.d.results[m[1]]; ok {
+			rows.columns, rows.rows = result(args)
+		}
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func newFakeQueries(t *testing.T, results map[string]fakeResult) *database.Queries {
+	t.Helper()
+	registerOnce.Do(func() { sql.Register("chirpy-fake", fakeDB) })
+	fakeDB.results = results
+
+	db, err := sql.Open("chirpy-fake", "")
+	if err != nil {
+		t.Fatalf("sql.Open failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return database.New(db)
+}
+
+// TestOptionalAuthViewer checks that routes serving viewer-specific data see
+// the caller's identity when a bearer token is sent.
+func TestOptionalAuthViewer(t *testing.T) {
+	viewerID := uuid.New()
+	authorID := uuid.New()
+	now := time.Now()
+
+	db := newFakeQueries(t, map[string]fakeResult{
+		"GetUserTokenVersion": func([]driver.Value) ([]string, [][]driver.Value) {
+			return []string{"token_version"}, [][]driver.Value{{int64(0)}}
+		},
+		"GetActiveMutedWords": func(args []driver.Value) ([]string, [][]driver.Value) {
+			return []string{"id", "user_id", "phrase", "whole_word", "action", "expires_at", "created_at"},
+				[][]driver.Value{{uuid.NewString(), args[0], "spoiler", false, "hide", nil, now}}
+		},
+		"ListChirpsAsc": func(args []driver.Value) ([]string, [][]driver.Value) {
+			// liked_by_viewer is computed against the viewer_id argument.
+			liked := args[0] != nil
+			chirp := func(body string) []driver.Value {
+				return []driver.Value{uuid.NewString(), now, now, int64(0), false, body, authorID.String(), nil, "post", nil, "author", int64(0), int64(1), liked}
+			}
+			return []string{"id", "created_at", "updated_at", "edit_count", "edited", "body", "user_id", "parent_id", "kind", "original_id", "author_handle", "reply_count", "like_count", "liked_by_viewer"},
+				[][]driver.Value{chirp("hello"), chirp("spoiler alert")}
+		},
+	})
+
+	keys := auth.NewHMACKeySet("test-secret", auth.DefaultIssuer, auth.DefaultAudience)
+	token, err := auth.MakeJWT(viewerID, 0, keys, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT failed: %v", err)
+	}
+	routes := New("0", db, "dev", keys, "", "", 0, nil).Routes()
+
+	tests := []struct {
+		name       string
+		token      string
+		wantBodies []string
+		wantLiked  bool
+	}{
+		{name: "anonymous", wantBodies: []string{"hello", "spoiler alert"}},
+		{name: "authenticated", token: token, wantBodies: []string{"hello"}, wantLiked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/chirps", nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			routes.ServeHTTP(w, r.WithContext(context.Background()))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body)
+			}
+
+			var resp struct {
+				Chirps []struct {
+					Body          string `json:"body"`
+					LikedByViewer bool   `json:"liked_by_viewer"`
+				} `json:"chirps"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+
+			if len(resp.Chirps) != len(tt.wantBodies) {
+				t.Fatalf("got %d chirps, want %v", len(resp.Chirps), tt.wantBodies)
+			}
+			for i, c := range resp.Chirps {
+				if c.Body != tt.wantBodies[i] {
+					t.Errorf("chirp %d body = %q, want %q", i, c.Body, tt.wantBodies[i])
+				}
+				if c.LikedByViewer != tt.wantLiked {
+					t.Errorf("chirp %d liked_by_viewer = %v, want %v", i, c.LikedByViewer, tt.wantLiked)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorePutDelete(t *testing.T) {
+	root := t.TempDir()
+	store := NewLocalStore(root, "/assets/")
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "avatars/a.png", strings.NewReader("image")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "avatars", "a.png"))
+	if err != nil {
+		t.Fatalf("reading blob: %v", err)
+	}
+	if string(data) != "image" {
+		t.Errorf("blob content = %q, want %q", data, "image")
+	}
+
+	if got := store.URL("avatars/a.png"); got != "/assets/avatars/a.png" {
+		t.Errorf("URL() = %q, want %q", got, "/assets/avatars/a.png")
+	}
+
+	if err := store.Delete(ctx, "avatars/a.png"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "avatars", "a.png")); !os.IsNotExist(err) {
+		t.Errorf("blob still exists after Delete()")
+	}
+
+	if err := store.Delete(ctx, "avatars/a.png"); err != nil {
+		t.Errorf("Delete() of missing blob error = %v", err)
+	}
+}
+
+func TestLocalStoreRejectsBadKeys(t *testing.T) {
+	store := NewLocalStore(t.TempDir(), "/assets")
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "Empty", key: ""},
+		{name: "Parent", key: "../secret"},
+		{name: "Nested parent", key: "avatars/../../secret"},
+		{name: "Absolute", key: "/etc/passwd"},
+		{name: "Backslash", key: "avatars\\a.png"},
+		{name: "Unclean", key: "avatars//a.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.Put(context.Background(), tt.key, strings.NewReader("x"))
+			if err != ErrInvalidKey {
+				t.Errorf("Put(%q) error = %v, want ErrInvalidKey", tt.key, err)
+			}
+		})
+	}
+}
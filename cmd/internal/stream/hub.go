@@ -0,0 +1,251 @@
+package stream
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultBufferSize  = 64
+	DefaultBacklogSize = 1024
+	// ResumeWindow is how long after a user's last stream closes they still
+	// count as listening, so a client that reconnects can replay what it
+	// missed from the backlog.
+	ResumeWindow = time.Minute
+)
+
+// Event is a single message delivered to stream subscribers. IDs increase
+// monotonically within a process and are used for Last-Event-ID resume.
+type Event struct {
+	ID   uint64
+	Type string
+	Data []byte
+}
+
+type backlogEntry struct {
+	event      Event
+	recipients []uuid.UUID
+}
+
+// Hub is an in-process pub/sub broker. Publishers address events to a set of
+// users and every open subscription of those users receives a copy.
+//
+// Publishers may address only users who are Listening. A client that
+// reconnects after it stopped counting as listening is therefore told its
+// replay is incomplete.
+//
+// Subscribers get a bounded buffer. A subscriber that falls behind is
+// disconnected instead of blocking publishers; it can reconnect with the ID
+// of the last event it saw and replay what it missed from the backlog.
+type Hub struct {
+	mu          sync.Mutex
+	lastID      uint64
+	backlog     []backlogEntry
+	backlogSize int
+	bufferSize  int
+	subs        map[uuid.UUID]map[*Subscription]struct{}
+	// closedAt is when each user without an open subscription last had
+	// one, for users still within ResumeWindow of it.
+	closedAt  map[uuid.UUID]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewHub(bufferSize, backlogSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	if backlogSize <= 0 {
+		backlogSize = DefaultBacklogSize
+	}
+
+	return &Hub{
+		// Seeding with the start time keeps IDs from a previous process
+		// below every ID issued by this one, so stale Last-Event-IDs are
+		// detected as gaps rather than replayed against the wrong events.
+		lastID:      uint64(time.Now().UnixNano()),
+		backlogSize: backlogSize,
+		bufferSize:  bufferSize,
+		subs:        make(map[uuid.UUID]map[*Subscription]struct{}),
+		closedAt:    make(map[uuid.UUID]time.Time),
+		now:         time.Now,
+	}
+}
+
+// Listening returns the users among ids who can receive events: those with
+// an open subscription and those whose last one closed within ResumeWindow.
+func (h *Hub) Listening(ids []uuid.UUID) []uuid.UUID {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	h.sweepLocked(now)
+
+	var listening []uuid.UUID
+	for _, id := range ids {
+		if h.listeningLocked(id, now) {
+			listening = append(listening, id)
+		}
+	}
+	return listening
+}
+
+func (h *Hub) listeningLocked(userID uuid.UUID, now time.Time) bool {
+	if len(h.subs[userID]) > 0 {
+		return true
+	}
+	closed, ok := h.closedAt[userID]
+	return ok && now.Sub(closed) < ResumeWindow
+}
+
+// sweepLocked forgets users who stopped listening, at most once per
+// ResumeWindow.
+func (h *Hub) sweepLocked(now time.Time) {
+	if now.Sub(h.lastSweep) < ResumeWindow {
+		return
+	}
+	h.lastSweep = now
+
+	for id, closed := range h.closedAt {
+		if now.Sub(closed) >= ResumeWindow {
+			delete(h.closedAt, id)
+		}
+	}
+}
+
+// Publish marshals data and delivers it to every subscription of the given
+// recipients. It never blocks on slow subscribers.
+func (h *Hub) Publish(eventType string, data any, recipients ...uuid.UUID) (uint64, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return 0, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event := Event{ID: h.lastID, Type: eventType, Data: payload}
+
+	if len(h.backlog) == h.backlogSize {
+		copy(h.backlog, h.backlog[1:])
+		h.backlog = h.backlog[:len(h.backlog)-1]
+	}
+	h.backlog = append(h.backlog, backlogEntry{event: event, recipients: recipients})
+
+	seen := make(map[uuid.UUID]struct{}, len(recipients))
+	for _, userID := range recipients {
+		if _, ok := seen[userID]; ok {
+			continue
+		}
+		seen[userID] = struct{}{}
+
+		for sub := range h.subs[userID] {
+			select {
+			case sub.events <- event:
+			default:
+				sub.lagged = true
+				h.removeLocked(sub)
+			}
+		}
+	}
+
+	return event.ID, nil
+}
+
+// Subscribe registers a subscription for userID. When lastEventID is non-zero
+// the events the user missed since then are returned for replay; complete is
+// false if some of them have already left the backlog, in which case the
+// client has to refetch instead of relying on the replay.
+func (h *Hub) Subscribe(userID uuid.UUID, lastEventID uint64) (sub *Subscription, missed []Event, complete bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	complete = true
+	if lastEventID != 0 {
+		complete = lastEventID == h.lastID ||
+			(len(h.backlog) > 0 && lastEventID < h.lastID && lastEventID+1 >= h.backlog[0].event.ID &&
+				h.listeningLocked(userID, h.now()))
+		for _, entry := range h.backlog {
+			if entry.event.ID > lastEventID && addressedTo(entry.recipients, userID) {
+				missed = append(missed, entry.event)
+			}
+		}
+	}
+
+	sub = &Subscription{
+		hub:    h,
+		userID: userID,
+		events: make(chan Event, h.bufferSize),
+	}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*Subscription]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+	delete(h.closedAt, userID)
+
+	return sub, missed, complete
+}
+
+// LastID returns the ID of the most recently published event.
+func (h *Hub) LastID() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lastID
+}
+
+func (h *Hub) removeLocked(sub *Subscription) {
+	subs, ok := h.subs[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subs, sub.userID)
+		h.closedAt[sub.userID] = h.now()
+	}
+	close(sub.events)
+}
+
+func addressedTo(recipients []uuid.UUID, userID uuid.UUID) bool {
+	for _, id := range recipients {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription is one open stream. Its channel is closed when the
+// subscription is closed or dropped for falling behind.
+type Subscription struct {
+	hub    *Hub
+	userID uuid.UUID
+	events chan Event
+	lagged bool
+}
+
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Lagged reports whether the hub dropped the subscription because its
+// buffer was full.
+func (s *Subscription) Lagged() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.lagged
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.removeLocked(s)
+}
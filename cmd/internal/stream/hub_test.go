@@ -0,0 +1,163 @@
+package stream
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPublishDeliversToRecipients(t *testing.T) {
+	hub := NewHub(4, 16)
+	alice, bob := uuid.New(), uuid.New()
+
+	aliceSub, _, _ := hub.Subscribe(alice, 0)
+	defer aliceSub.Close()
+	bobSub, _, _ := hub.Subscribe(bob, 0)
+	defer bobSub.Close()
+
+	id, err := hub.Publish("chirp.created", map[string]string{"body": "hi"}, alice, alice)
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	select {
+	case event := <-aliceSub.Events():
+		if event.ID != id || event.Type != "chirp.created" || string(event.Data) != `{"body":"hi"}` {
+			t.Errorf("got event %+v", event)
+		}
+	default:
+		t.Fatal("alice did not receive the event")
+	}
+
+	select {
+	case event := <-aliceSub.Events():
+		t.Errorf("duplicate recipient delivered twice: %+v", event)
+	case event := <-bobSub.Events():
+		t.Errorf("bob received an event not addressed to him: %+v", event)
+	default:
+	}
+}
+
+func TestSubscribeReplaysMissedEvents(t *testing.T) {
+	hub := NewHub(4, 16)
+	alice, bob := uuid.New(), uuid.New()
+
+	earlier, _, _ := hub.Subscribe(alice, 0)
+	first, _ := hub.Publish("chirp.created", 1, alice)
+	<-earlier.Events()
+	earlier.Close()
+
+	hub.Publish("chirp.created", 2, bob)
+	third, _ := hub.Publish("chirp.updated", 3, alice)
+
+	sub, missed, complete := hub.Subscribe(alice, first)
+	defer sub.Close()
+
+	if !complete {
+		t.Error("complete = false, want true")
+	}
+	if len(missed) != 1 || missed[0].ID != third {
+		t.Errorf("missed = %+v, want only event %d", missed, third)
+	}
+}
+
+func TestSubscribeDetectsGaps(t *testing.T) {
+	hub := NewHub(4, 2)
+	alice := uuid.New()
+
+	first, _ := hub.Publish("chirp.created", 1, alice)
+	hub.Publish("chirp.created", 2, alice)
+	hub.Publish("chirp.created", 3, alice)
+	last, _ := hub.Publish("chirp.created", 4, alice)
+
+	tests := []struct {
+		name        string
+		lastEventID uint64
+		want        bool
+	}{
+		{name: "fresh connection", lastEventID: 0, want: true},
+		{name: "up to date", lastEventID: last, want: true},
+		{name: "fell out of backlog", lastEventID: first, want: false},
+		{name: "previous process", lastEventID: 42, want: false},
+		{name: "from the future", lastEventID: last + 10, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, _, complete := hub.Subscribe(alice, tt.lastEventID)
+			defer sub.Close()
+			if complete != tt.want {
+				t.Errorf("complete = %v, want %v", complete, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	hub := NewHub(1, 16)
+	alice := uuid.New()
+
+	sub, _, _ := hub.Subscribe(alice, 0)
+
+	hub.Publish("chirp.created", 1, alice)
+	hub.Publish("chirp.created", 2, alice)
+
+	if !sub.Lagged() {
+		t.Fatal("Lagged() = false, want true")
+	}
+
+	<-sub.Events()
+	if _, ok := <-sub.Events(); ok {
+		t.Error("events channel still open after subscriber was dropped")
+	}
+
+	// Closing an already dropped subscription must be safe.
+	sub.Close()
+}
+
+func TestListening(t *testing.T) {
+	hub := NewHub(4, 16)
+	now := time.Now()
+	hub.now = func() time.Time { return now }
+	open, closed, never := uuid.New(), uuid.New(), uuid.New()
+
+	openSub, _, _ := hub.Subscribe(open, 0)
+	defer openSub.Close()
+	closedSub, _, _ := hub.Subscribe(closed, 0)
+	closedSub.Close()
+
+	got := hub.Listening([]uuid.UUID{open, closed, never})
+	if !slices.Equal(got, []uuid.UUID{open, closed}) {
+		t.Errorf("Listening() = %v, want the open and recently closed users", got)
+	}
+
+	now = now.Add(ResumeWindow)
+	got = hub.Listening([]uuid.UUID{open, closed, never})
+	if !slices.Equal(got, []uuid.UUID{open}) {
+		t.Errorf("Listening() after ResumeWindow = %v, want only the open user", got)
+	}
+}
+
+func TestSubscribeAfterResumeWindowIsIncomplete(t *testing.T) {
+	hub := NewHub(4, 16)
+	now := time.Now()
+	hub.now = func() time.Time { return now }
+	alice := uuid.New()
+
+	sub, _, _ := hub.Subscribe(alice, 0)
+	first, _ := hub.Publish("chirp.created", 1, alice)
+	sub.Close()
+
+	// Publishers stop addressing alice once she is no longer listening, so
+	// the backlog cannot be trusted to hold everything she missed.
+	now = now.Add(ResumeWindow)
+	hub.Publish("chirp.created", 2, uuid.New())
+
+	sub, _, complete := hub.Subscribe(alice, first)
+	defer sub.Close()
+	if complete {
+		t.Error("complete = true after ResumeWindow, want false")
+	}
+}
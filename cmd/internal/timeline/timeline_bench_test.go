@@ -0,0 +1,172 @@
+package timeline
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+// The benchmarks compare the fan-out-on-read feed query (GetFeed) with the
+// materialized timeline (GetHomeTimeline). They need a migrated Postgres
+// database and write their own users, so they only run when
+// CHIRPY_BENCH_DB_URL is set:
+//
+//	CHIRPY_BENCH_DB_URL=postgres://... go test -run '^$' -bench . ./cmd/internal/timeline/
+const (
+	benchFollowing      = 500
+	benchChirpsPerUser  = 20
+	benchPageLimit      = 20
+	benchDeepPageOffset = 200
+)
+
+type benchFixture struct {
+	queries  *database.Queries
+	viewerID uuid.UUID
+	// deepCursor and deepAt are the activity benchDeepPageOffset items into
+	// the feed.
+	deepCursor uuid.NullUUID
+	deepAt     sql.NullTime
+}
+
+func setupBenchFixture(b *testing.B) *benchFixture {
+	b.Helper()
+
+	connStr := os.Getenv("CHIRPY_BENCH_DB_URL")
+	if connStr == "" {
+		b.Skip("CHIRPY_BENCH_DB_URL not set")
+	}
+
+	pgx, err := database.NewDbPgx(connStr)
+	if err != nil {
+		b.Fatalf("connecting to DB: %v", err)
+	}
+
+	ctx := context.Background()
+	run := uuid.NewString()[:8]
+	var userIDs []uuid.UUID
+
+	b.Cleanup(func() {
+		for _, id := range userIDs {
+			if _, err := pgx.DB.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id); err != nil {
+				b.Logf("cleaning up user %s: %v", id, err)
+			}
+		}
+		pgx.Close()
+	})
+
+	createUser := func(name string) uuid.UUID {
+		var id uuid.UUID
+		err := pgx.DB.QueryRowContext(ctx, `
+			INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
+			VALUES (gen_random_uuid(), NOW(), NOW(), $1, 'unused', $2)
+			RETURNING id`,
+			name+"@bench.invalid", name,
+		).Scan(&id)
+		if err != nil {
+			b.Fatalf("creating user %s: %v", name, err)
+		}
+		userIDs = append(userIDs, id)
+		return id
+	}
+
+	viewerID := createUser(fmt.Sprintf("bench_%s_viewer", run))
+
+	for i := range benchFollowing {
+		authorID := createUser(fmt.Sprintf("bench_%s_%d", run, i))
+
+		_, err := pgx.DB.ExecContext(ctx, `
+			INSERT INTO chirps (id, created_at, updated_at, body, user_id)
+			SELECT gen_random_uuid(), NOW() - (n || ' minutes')::interval, NOW(), 'benchmark chirp ' || n, $1
+			FROM generate_series(1, $2::int) n`,
+			authorID, benchChirpsPerUser,
+		)
+		if err != nil {
+			b.Fatalf("creating chirps: %v", err)
+		}
+
+		_, err = pgx.Queries.FollowUser(ctx, database.FollowUserParams{
+			FollowerID: viewerID,
+			FolloweeID: authorID,
+		})
+		if err != nil {
+			b.Fatalf("following: %v", err)
+		}
+
+		err = pgx.Queries.BackfillHomeTimeline(ctx, database.BackfillHomeTimelineParams{
+			FollowerID:    viewerID,
+			FolloweeID:    authorID,
+			BackfillLimit: BackfillLimit,
+		})
+		if err != nil {
+			b.Fatalf("backfilling: %v", err)
+		}
+	}
+
+	var deepCursor uuid.NullUUID
+	var deepAt sql.NullTime
+	err = pgx.DB.QueryRowContext(ctx, `
+		SELECT activity_id, activity_at FROM home_timeline
+		WHERE user_id = $1
+		ORDER BY activity_at DESC, activity_id DESC
+		OFFSET $2 LIMIT 1`,
+		viewerID, benchDeepPageOffset,
+	).Scan(&deepCursor, &deepAt)
+	if err != nil && err != sql.ErrNoRows {
+		b.Fatalf("finding deep cursor: %v", err)
+	}
+
+	return &benchFixture{
+		queries:    pgx.Queries,
+		viewerID:   viewerID,
+		deepCursor: deepCursor,
+		deepAt:     deepAt,
+	}
+}
+
+func BenchmarkFeed(b *testing.B) {
+	f := setupBenchFixture(b)
+	ctx := context.Background()
+
+	pages := []struct {
+		name     string
+		cursor   uuid.NullUUID
+		cursorAt sql.NullTime
+	}{
+		{name: "first_page"},
+		{name: "deep_page", cursor: f.deepCursor, cursorAt: f.deepAt},
+	}
+
+	for _, page := range pages {
+		b.Run("fanout_on_read/"+page.name, func(b *testing.B) {
+			for b.Loop() {
+				_, err := f.queries.GetFeed(ctx, database.GetFeedParams{
+					FollowerID: f.viewerID,
+					Cursor:     page.cursor,
+					PageLimit:  benchPageLimit,
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run("home_timeline/"+page.name, func(b *testing.B) {
+			for b.Loop() {
+				_, err := f.queries.GetHomeTimeline(ctx, database.GetHomeTimelineParams{
+					ViewerID:  f.viewerID,
+					CursorAt:  page.cursorAt,
+					CursorID:  page.cursor,
+					PageLimit: benchPageLimit,
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
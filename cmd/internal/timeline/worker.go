@@ -0,0 +1,124 @@
+package timeline
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+const (
+	// FanoutLimit is the follower count from which an account's chirps are
+	// no longer copied into every follower's timeline and are merged in at
+	// read time instead. It is checked once, when a chirp is posted.
+	FanoutLimit = 10000
+	// BackfillLimit caps how many chirps of a newly followed account are
+	// copied into the follower's timeline.
+	BackfillLimit = 200
+
+	DefaultQueueSize = 1024
+	jobTimeout       = 30 * time.Second
+)
+
+type jobKind int
+
+const (
+	jobFanOut jobKind = iota
+	jobBackfill
+	jobPrune
+)
+
+type job struct {
+	kind       jobKind
+	chirpID    uuid.UUID
+	followerID uuid.UUID
+	followeeID uuid.UUID
+}
+
+// Worker maintains the materialized home_timeline table in the background so
+// request handlers never wait on fan-out.
+type Worker struct {
+	db   *database.Queries
+	jobs chan job
+}
+
+func NewWorker(db *database.Queries, queueSize int) *Worker {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+
+	return &Worker{
+		db:   db,
+		jobs: make(chan job, queueSize),
+	}
+}
+
+// Run processes jobs until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-w.jobs:
+			w.process(ctx, j)
+		}
+	}
+}
+
+// FanOut queues a new chirp for delivery to its author's followers.
+func (w *Worker) FanOut(chirpID uuid.UUID) {
+	w.enqueue(job{kind: jobFanOut, chirpID: chirpID})
+}
+
+// Backfill queues copying a newly followed account's recent chirps.
+func (w *Worker) Backfill(followerID, followeeID uuid.UUID) {
+	w.enqueue(job{kind: jobBackfill, followerID: followerID, followeeID: followeeID})
+}
+
+// Prune queues removing an unfollowed account's chirps from the follower's
+// timeline. Deleted chirps need no job; their rows cascade.
+func (w *Worker) Prune(followerID, followeeID uuid.UUID) {
+	w.enqueue(job{kind: jobPrune, followerID: followerID, followeeID: followeeID})
+}
+
+// enqueue hands a job to Run. When the queue is full the job runs on its own
+// goroutine instead, trading ordering for never dropping timeline updates.
+func (w *Worker) enqueue(j job) {
+	select {
+	case w.jobs <- j:
+	default:
+		log.Printf("Timeline queue full, running job inline")
+		go w.process(context.Background(), j)
+	}
+}
+
+func (w *Worker) process(ctx context.Context, j job) {
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+
+	var err error
+	switch j.kind {
+	case jobFanOut:
+		err = w.db.FanOutChirp(ctx, database.FanOutChirpParams{
+			FanoutLimit: FanoutLimit,
+			ChirpID:     j.chirpID,
+		})
+	case jobBackfill:
+		err = w.db.BackfillHomeTimeline(ctx, database.BackfillHomeTimelineParams{
+			FollowerID:    j.followerID,
+			FolloweeID:    j.followeeID,
+			BackfillLimit: BackfillLimit,
+		})
+	case jobPrune:
+		err = w.db.PruneHomeTimeline(ctx, database.PruneHomeTimelineParams{
+			FollowerID: j.followerID,
+			FolloweeID: j.followeeID,
+		})
+	}
+
+	if err != nil {
+		log.Printf("Error processing timeline job %d: %v", j.kind, err)
+	}
+}
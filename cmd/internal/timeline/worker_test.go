@@ -0,0 +1,206 @@
+package timeline
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shubh-man007/Chirpy/cmd/internal/database"
+)
+
+// The tests below run the timeline queries against a migrated Postgres
+// database and only run when CHIRPY_TEST_DB_URL is set:
+//
+//	CHIRPY_TEST_DB_URL=postgres://... go test ./cmd/internal/timeline/
+func openTestDB(t *testing.T) *database.DbPgx {
+	t.Helper()
+
+	connStr := os.Getenv("CHIRPY_TEST_DB_URL")
+	if connStr == "" {
+		t.Skip("CHIRPY_TEST_DB_URL not set")
+	}
+
+	pgx, err := database.NewDbPgx(connStr)
+	if err != nil {
+		t.Fatalf("connecting to DB: %v", err)
+	}
+	t.Cleanup(func() { pgx.Close() })
+	return pgx
+}
+
+// timelineFixture creates users, chirps and follows for one test and
+// deletes the users, and with them everything else, when it ends.
+type timelineFixture struct {
+	t   *testing.T
+	ctx context.Context
+	pgx *database.DbPgx
+	run string
+}
+
+func newTimelineFixture(t *testing.T) *timelineFixture {
+	return &timelineFixture{
+		t:   t,
+		ctx: context.Background(),
+		pgx: openTestDB(t),
+		run: uuid.NewString()[:8],
+	}
+}
+
+func (f *timelineFixture) createUser(name string) uuid.UUID {
+	f.t.Helper()
+
+	var id uuid.UUID
+	err := f.pgx.DB.QueryRowContext(f.ctx, `
+		INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
+		VALUES (gen_random_uuid(), NOW(), NOW(), $1, 'unused', $2)
+		RETURNING id`,
+		f.run+"_"+name+"@test.invalid", "t_"+f.run+"_"+name,
+	).Scan(&id)
+	if err != nil {
+		f.t.Fatalf("creating user %s: %v", name, err)
+	}
+	f.t.Cleanup(func() {
+		if _, err := f.pgx.DB.ExecContext(f.ctx, "DELETE FROM users WHERE id = $1", id); err != nil {
+			f.t.Logf("cleaning up user %s: %v", id, err)
+		}
+	})
+	return id
+}
+
+func (f *timelineFixture) createChirp(authorID uuid.UUID, originalID uuid.NullUUID, age time.Duration) uuid.UUID {
+	f.t.Helper()
+
+	kind := "chirp"
+	if originalID.Valid {
+		kind = "rechirp"
+	}
+	var id uuid.UUID
+	err := f.pgx.DB.QueryRowContext(f.ctx, `
+		INSERT INTO chirps (id, created_at, updated_at, body, user_id, kind, original_id)
+		VALUES (gen_random_uuid(), NOW() - $1::interval, NOW(), 'timeline test', $2, $3, $4)
+		RETURNING id`,
+		age.String(), authorID, kind, originalID,
+	).Scan(&id)
+	if err != nil {
+		f.t.Fatalf("creating chirp: %v", err)
+	}
+	return id
+}
+
+func (f *timelineFixture) follow(followerID, followeeID uuid.UUID) {
+	f.t.Helper()
+
+	if _, err := f.pgx.Queries.FollowUser(f.ctx, database.FollowUserParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	}); err != nil {
+		f.t.Fatalf("following: %v", err)
+	}
+	err := f.pgx.Queries.BackfillHomeTimeline(f.ctx, database.BackfillHomeTimelineParams{
+		FollowerID:    followerID,
+		FolloweeID:    followeeID,
+		BackfillLimit: BackfillLimit,
+	})
+	if err != nil {
+		f.t.Fatalf("backfilling: %v", err)
+	}
+}
+
+func (f *timelineFixture) setFollowerCount(userID uuid.UUID, count int) {
+	f.t.Helper()
+
+	if _, err := f.pgx.DB.ExecContext(f.ctx,
+		"UPDATE users SET follower_count = $2 WHERE id = $1", userID, count,
+	); err != nil {
+		f.t.Fatalf("setting follower count: %v", err)
+	}
+}
+
+func TestPruneHomeTimeline(t *testing.T) {
+	f := newTimelineFixture(t)
+
+	viewer := f.createUser("viewer")
+	author := f.createUser("author")
+	stranger := f.createUser("stranger")
+	rechirper := f.createUser("rechirper")
+
+	// rechirper shares a post by author, whom the viewer also follows, and
+	// one by stranger, whom the viewer does not follow.
+	followedPost := f.createChirp(author, uuid.NullUUID{}, 3*time.Minute)
+	strangerPost := f.createChirp(stranger, uuid.NullUUID{}, 3*time.Minute)
+	f.createChirp(rechirper, uuid.NullUUID{UUID: followedPost, Valid: true}, time.Minute)
+	f.createChirp(rechirper, uuid.NullUUID{UUID: strangerPost, Valid: true}, time.Minute)
+
+	f.follow(viewer, author)
+	f.follow(viewer, rechirper)
+
+	if err := f.pgx.Queries.UnfollowUser(f.ctx, database.UnfollowUserParams{
+		FollowerID: viewer,
+		FolloweeID: rechirper,
+	}); err != nil {
+		t.Fatalf("unfollowing: %v", err)
+	}
+	if err := f.pgx.Queries.PruneHomeTimeline(f.ctx, database.PruneHomeTimelineParams{
+		FollowerID: viewer,
+		FolloweeID: rechirper,
+	}); err != nil {
+		t.Fatalf("pruning: %v", err)
+	}
+
+	entry := func(chirpID uuid.UUID) (activityID uuid.UUID, rechirpedBy uuid.NullUUID, err error) {
+		err = f.pgx.DB.QueryRowContext(f.ctx, `
+			SELECT activity_id, rechirped_by FROM home_timeline
+			WHERE user_id = $1 AND chirp_id = $2`,
+			viewer, chirpID,
+		).Scan(&activityID, &rechirpedBy)
+		return activityID, rechirpedBy, err
+	}
+
+	activityID, rechirpedBy, err := entry(followedPost)
+	if err != nil {
+		t.Fatalf("post by a still followed author was removed: %v", err)
+	}
+	if activityID != followedPost || rechirpedBy.Valid {
+		t.Errorf("entry = (%v, %v), want the original post without a rechirper", activityID, rechirpedBy)
+	}
+
+	if _, _, err := entry(strangerPost); err != sql.ErrNoRows {
+		t.Errorf("rechirp of an unfollowed author was kept: %v", err)
+	}
+}
+
+func TestPulledChirpsOutliveFanoutLimit(t *testing.T) {
+	f := newTimelineFixture(t)
+
+	viewer := f.createUser("viewer")
+	author := f.createUser("author")
+	f.follow(viewer, author)
+
+	// Posted while the author is over the limit, so the chirp is pulled at
+	// read time rather than copied into the viewer's timeline.
+	f.setFollowerCount(author, FanoutLimit)
+	chirpID := f.createChirp(author, uuid.NullUUID{}, time.Minute)
+	if err := f.pgx.Queries.FanOutChirp(f.ctx, database.FanOutChirpParams{
+		FanoutLimit: FanoutLimit,
+		ChirpID:     chirpID,
+	}); err != nil {
+		t.Fatalf("fanning out: %v", err)
+	}
+
+	// The author then loses followers and falls back below the limit.
+	f.setFollowerCount(author, 1)
+
+	chirps, err := f.pgx.Queries.GetHomeTimeline(f.ctx, database.GetHomeTimelineParams{
+		ViewerID:  viewer,
+		PageLimit: 20,
+	})
+	if err != nil {
+		t.Fatalf("reading timeline: %v", err)
+	}
+	if len(chirps) != 1 || chirps[0].ID != chirpID {
+		t.Errorf("timeline = %v, want only the chirp posted over the limit", chirps)
+	}
+}
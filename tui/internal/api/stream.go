@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/shubh-man007/Chirpy/tui/internal/models"
+)
+
+// Stream opens the server-sent event stream. Events are delivered on the
+// returned channel, which is closed when ctx is cancelled or the connection
+// drops; callers reconnect with the ID of the last event they received.
+func (c *Chirpy) Stream(ctx context.Context, lastEventID string) (<-chan models.StreamEvent, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/api/stream", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	// The shared client has a request timeout, which would cut the
+	// long-lived stream off.
+	client := *c.Client
+	client.Timeout = 0
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 400 {
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("API error %d: %s", res.StatusCode, string(body))
+	}
+
+	events := make(chan models.StreamEvent)
+	go func() {
+		defer close(events)
+		defer res.Body.Close()
+		readEvents(ctx, res.Body, events)
+	}()
+
+	return events, nil
+}
+
+func readEvents(ctx context.Context, body io.Reader, events chan<- models.StreamEvent) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event models.StreamEvent
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			if len(data) > 0 {
+				event.Data = []byte(strings.Join(data, "\n"))
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			event = models.StreamEvent{}
+			data = nil
+			continue
+		}
+
+		// Comment lines are heartbeats.
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.ID = value
+		case "event":
+			event.Type = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
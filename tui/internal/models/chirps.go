@@ -1,15 +1,37 @@
-package models
-
-import "time"
-
-type ChirpBody struct {
-	Body string `json:"body"`
-}
-
-type Chirp struct {
-	ID        string    `json:"id"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	UserID    string    `json:"user_id"`
-}
+package models
+
+import "time"
+
+type ChirpBody struct {
+	Body      string `json:"body"`
+	InReplyTo string `json:"in_reply_to,omitempty"`
+	QuoteOf   string `json:"quote_of,omitempty"`
+}
+
+type Chirp struct {
+	ID            string    `json:"id"`
+	Body          string    `json:"body"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	UserID        string    `json:"user_id"`
+	AuthorHandle  string    `json:"author_handle,omitempty"`
+	ParentID      *string   `json:"parent_id,omitempty"`
+	Kind          string    `json:"kind"`
+	OriginalID    *string   `json:"original_id,omitempty"`
+	RechirpedBy   *string   `json:"rechirped_by,omitempty"`
+	ActivityID    string    `json:"activity_id,omitempty"`
+	ReplyCount    int64     `json:"reply_count"`
+	LikeCount     int64     `json:"like_count"`
+	LikedByViewer bool      `json:"liked_by_viewer"`
+}
+
+type ChirpsResponse struct {
+	Chirps     []Chirp `json:"chirps"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+type FeedResponse struct {
+	Chirps     []Chirp `json:"chirps"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	PrevCursor *string `json:"prev_cursor,omitempty"`
+}
@@ -1,23 +1,35 @@
-package models
-
-import "time"
-
-type ProfileResponse struct {
-	ID             string      `json:"id"`
-	Email          string      `json:"email"`
-	CreatedAt      time.Time   `json:"created_at"`
-	UpdatedAt      time.Time   `json:"updated_at"`
-	IsChirpyRed    bool        `json:"is_chirpy_red"`
-	FollowersCount int64       `json:"followers_count"`
-	FollowingCount int64       `json:"following_count"`
-	ChirpsCount    int64       `json:"chirps_count"`
-	Chirps         []ChirpItem `json:"chirps"`
-	NextCursor     *string     `json:"next_cursor,omitempty"`
-	IsFollowing    *bool       `json:"is_following,omitempty"`
-}
-
-type ChirpItem struct {
-	ID        string    `json:"id"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"created_at"`
-}
+package models
+
+import "time"
+
+type ProfileResponse struct {
+	ID             string      `json:"id"`
+	Handle         string      `json:"handle"`
+	Email          string      `json:"email,omitempty"`
+	DisplayName    string      `json:"display_name"`
+	Bio            string      `json:"bio"`
+	Location       string      `json:"location"`
+	Website        string      `json:"website"`
+	AvatarURL      string      `json:"avatar_url,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	IsChirpyRed    bool        `json:"is_chirpy_red"`
+	FollowersCount int64       `json:"followers_count"`
+	FollowingCount int64       `json:"following_count"`
+	ChirpsCount    int64       `json:"chirps_count"`
+	Chirps         []ChirpItem `json:"chirps"`
+	NextCursor     *string     `json:"next_cursor,omitempty"`
+	IsFollowing    *bool       `json:"is_following,omitempty"`
+}
+
+type ChirpItem struct {
+	ID            string    `json:"id"`
+	Body          string    `json:"body"`
+	CreatedAt     time.Time `json:"created_at"`
+	ParentID      *string   `json:"parent_id,omitempty"`
+	Kind          string    `json:"kind"`
+	OriginalID    *string   `json:"original_id,omitempty"`
+	ReplyCount    int64     `json:"reply_count"`
+	LikeCount     int64     `json:"like_count"`
+	LikedByViewer bool      `json:"liked_by_viewer"`
+}
@@ -0,0 +1,21 @@
+package models
+
+type SearchChirpsResponse struct {
+	Results    []Chirp `json:"results"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+type UserSearchResult struct {
+	ID          string `json:"id"`
+	Handle      string `json:"handle"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+	IsChirpyRed bool   `json:"is_chirpy_red"`
+	IsFollowing bool   `json:"is_following"`
+	FollowsYou  bool   `json:"follows_you"`
+}
+
+type SearchUsersResponse struct {
+	Results    []UserSearchResult `json:"results"`
+	NextCursor *string            `json:"next_cursor,omitempty"`
+}
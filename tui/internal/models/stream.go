@@ -0,0 +1,19 @@
+package models
+
+import "encoding/json"
+
+// StreamEvent is one event received from GET /api/stream.
+type StreamEvent struct {
+	ID   string
+	Type string
+	Data json.RawMessage
+}
+
+type ChirpDeletedEvent struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+}
+
+type FollowerAddedEvent struct {
+	FollowerID string `json:"follower_id"`
+}
@@ -1,10 +1,11 @@
-package models
-
-import "time"
-
-type User struct {
-	ID          string    `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	IsChirpyRed bool      `json:"is_chirpy_red"`
-}
+package models
+
+import "time"
+
+type User struct {
+	ID          string    `json:"id"`
+	Handle      string    `json:"handle"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	IsChirpyRed bool      `json:"is_chirpy_red"`
+}
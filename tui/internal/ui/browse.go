@@ -1,270 +1,446 @@
-package ui
-
-import (
-	"fmt"
-	"strings"
-	"time"
-
-	"github.com/charmbracelet/bubbles/spinner"
-	"github.com/charmbracelet/bubbles/textinput"
-	"github.com/charmbracelet/bubbles/viewport"
-	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/muesli/reflow/wordwrap"
-
-	"github.com/shubh-man007/Chirpy/tui/internal/api"
-	"github.com/shubh-man007/Chirpy/tui/internal/models"
-)
-
-type BrowseModel struct {
-	client *api.Chirpy
-
-	width  int
-	height int
-
-	input    textinput.Model
-	viewport viewport.Model
-
-	currentUserID string
-	profile       *models.ProfileResponse
-
-	loading  bool
-	errorMsg string
-	spin     spinner.Model
-}
-
-func NewBrowseModel(client *api.Chirpy) BrowseModel {
-	ti := textinput.New()
-	ti.Placeholder = "Enter user ID"
-	ti.Prompt = "User ID: "
-	ti.Focus()
-
-	vp := viewport.New(0, 0)
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-
-	return BrowseModel{
-		client:   client,
-		input:    ti,
-		viewport: vp,
-		spin:     s,
-	}
-}
-
-func (m BrowseModel) Init() tea.Cmd {
-	return textinput.Blink
-}
-
-func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		m.recalculateViewport()
-		return m, nil
-
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
-			userID := strings.TrimSpace(m.input.Value())
-			if userID == "" {
-				m.errorMsg = "User ID is required."
-				return m, nil
-			}
-			m.currentUserID = userID
-			m.loading = true
-			m.errorMsg = ""
-			m.profile = nil
-			return m, tea.Batch(m.spin.Tick, fetchUserProfileCmd(m.client, userID))
-		case "f":
-			if m.currentUserID == "" {
-				return m, nil
-			}
-			return m, followUserCmd(m.client, m.currentUserID)
-		case "u":
-			if m.currentUserID == "" {
-				return m, nil
-			}
-			return m, unfollowUserCmd(m.client, m.currentUserID)
-		}
-
-	case FollowUnfollowSuccessMsg:
-		if m.currentUserID != "" {
-			m.loading = true
-			return m, tea.Batch(m.spin.Tick, fetchUserProfileCmd(m.client, m.currentUserID))
-		}
-		return m, nil
-
-	case ProfileLoadedMsg:
-		m.loading = false
-		if msg.Err != nil {
-			m.errorMsg = msg.Err.Error()
-			m.profile = nil
-		} else {
-			m.errorMsg = ""
-			m.profile = msg.Profile
-		}
-		m.buildViewportContent()
-		return m, nil
-
-	case ErrorMsg:
-		m.loading = false
-		if msg.Err != nil {
-			m.errorMsg = msg.Err.Error()
-		}
-		m.buildViewportContent()
-		return m, nil
-	}
-
-	var cmds []tea.Cmd
-	var cmd tea.Cmd
-	m.input, cmd = m.input.Update(msg)
-	cmds = append(cmds, cmd)
-	m.viewport, cmd = m.viewport.Update(msg)
-	cmds = append(cmds, cmd)
-	if m.loading {
-		m.spin, cmd = m.spin.Update(msg)
-		cmds = append(cmds, cmd)
-	}
-	return m, tea.Batch(cmds...)
-}
-
-func (m *BrowseModel) recalculateViewport() {
-	if m.width == 0 || m.height == 0 {
-		return
-	}
-
-	header := headerStyle.Width(m.width).Render(" Chirpy | Browse Users ")
-	footer := footerStyle.Width(m.width).Render("")
-
-	contentHeight := m.height - lipgloss.Height(header) - lipgloss.Height(footer) - 3
-	if contentHeight < 1 {
-		contentHeight = 1
-	}
-
-	m.viewport.Width = m.width - 4
-	if m.viewport.Width < 20 {
-		m.viewport.Width = m.width
-	}
-	m.viewport.Height = contentHeight
-
-	m.buildViewportContent()
-}
-
-func (m *BrowseModel) buildViewportContent() {
-	var lines []string
-
-	if m.loading {
-		lines = append(lines,
-			lipgloss.NewStyle().Foreground(colorMuted).
-				Render(m.spin.View()+" Loading profile..."),
-		)
-	} else if m.profile == nil {
-		lines = append(lines,
-			lipgloss.NewStyle().Foreground(colorMuted).
-				Render("Enter a user ID and press Enter to view their profile."),
-		)
-	} else {
-		status := "Standard"
-		if m.profile.IsChirpyRed {
-			status = "Chirpy Red"
-		}
-		info := lipgloss.JoinVertical(lipgloss.Left,
-			"Email: "+m.profile.Email,
-			"User ID: "+m.profile.ID,
-			"Status: "+status,
-			fmt.Sprintf("Followers: %d  Following: %d  Chirps: %d",
-				m.profile.FollowersCount, m.profile.FollowingCount, m.profile.ChirpsCount),
-		)
-		if m.profile.IsFollowing != nil {
-			if *m.profile.IsFollowing {
-				info = lipgloss.JoinVertical(lipgloss.Left, info, authorStyle.Render("You follow this user"))
-			} else {
-				info = lipgloss.JoinVertical(lipgloss.Left, info, lipgloss.NewStyle().Foreground(colorMuted).Render("Not following"))
-			}
-		}
-		lines = append(lines, chirpBoxStyle.Width(m.viewport.Width).Render(info))
-		lines = append(lines, "")
-
-		if len(m.profile.Chirps) == 0 {
-			lines = append(lines, lipgloss.NewStyle().Foreground(colorMuted).Render("No chirps yet."))
-		} else {
-			lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Chirps:"))
-			for _, c := range m.profile.Chirps {
-				header := fmt.Sprintf("%s", relativeTime(c.CreatedAt))
-				body := wordwrap.String(c.Body, m.viewport.Width-4)
-				content := lipgloss.JoinVertical(
-					lipgloss.Left,
-					timestampStyle.Render(header),
-					body,
-				)
-				lines = append(lines, chirpBoxStyle.Width(m.viewport.Width).Render(content))
-			}
-		}
-	}
-
-	if m.errorMsg != "" {
-		lines = append([]string{errorStyle.Render("⚠ " + m.errorMsg), ""}, lines...)
-	}
-
-	m.viewport.SetContent(lipgloss.JoinVertical(lipgloss.Left, lines...))
-}
-
-func (m BrowseModel) View() string {
-	if m.width == 0 || m.height == 0 {
-		return "Browse"
-	}
-
-	header := headerStyle.Width(m.width).Render(" Chirpy | Browse Users ")
-
-	var bodyLines []string
-	bodyLines = append(bodyLines, m.input.View(), "")
-	bodyLines = append(bodyLines, m.viewport.View())
-
-	body := contentStyle.Render(
-		lipgloss.JoinVertical(lipgloss.Left, bodyLines...),
-	)
-
-	now := time.Now().Format("2006-01-02 15:04")
-	left := "[Enter] Load profile  [f] Follow  [u] Unfollow  [q] Quit"
-	space := ""
-	totalWidth := lipgloss.Width(left + now)
-	if m.width > totalWidth {
-		space = strings.Repeat(" ", m.width-totalWidth)
-	}
-	footer := footerStyle.Width(m.width).Render(left + space + now)
-
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		header,
-		body,
-		footer,
-	)
-}
-
-func fetchUserProfileCmd(client *api.Chirpy, userID string) tea.Cmd {
-	return func() tea.Msg {
-		profile, err := client.GetProfile(userID, "", 20)
-		return ProfileLoadedMsg{Profile: profile, Err: err}
-	}
-}
-
-func followUserCmd(client *api.Chirpy, userID string) tea.Cmd {
-	return func() tea.Msg {
-		err := client.FollowUser(userID)
-		if err != nil {
-			return ErrorMsg{Err: err}
-		}
-		return FollowUnfollowSuccessMsg{UserID: userID}
-	}
-}
-
-func unfollowUserCmd(client *api.Chirpy, userID string) tea.Cmd {
-	return func() tea.Msg {
-		err := client.UnfollowUser(userID)
-		if err != nil {
-			return ErrorMsg{Err: err}
-		}
-		return FollowUnfollowSuccessMsg{UserID: userID}
-	}
-}
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+
+	"github.com/shubh-man007/Chirpy/tui/internal/api"
+	"github.com/shubh-man007/Chirpy/tui/internal/models"
+)
+
+type BrowseModel struct {
+	client *api.Chirpy
+
+	width  int
+	height int
+
+	input    textinput.Model
+	viewport viewport.Model
+
+	currentUserID string
+	profile       *models.ProfileResponse
+
+	// User search results, shown until a profile is opened.
+	query      string
+	results    []models.UserSearchResult
+	nextCursor string
+	cursor     int
+	searched   bool
+
+	loading  bool
+	errorMsg string
+	spin     spinner.Model
+}
+
+func NewBrowseModel(client *api.Chirpy) BrowseModel {
+	ti := textinput.New()
+	ti.Placeholder = "Handle, name or user ID"
+	ti.Prompt = "Find: "
+	ti.Focus()
+
+	vp := viewport.New(0, 0)
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+
+	return BrowseModel{
+		client:   client,
+		input:    ti,
+		viewport: vp,
+		spin:     s,
+	}
+}
+
+func (m BrowseModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.recalculateViewport()
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.input.Focused() {
+			switch msg.String() {
+			case "enter":
+				query := strings.TrimSpace(m.input.Value())
+				if query == "" {
+					m.errorMsg = "Enter a handle, name or user ID."
+					m.buildViewportContent()
+					return m, nil
+				}
+				// A pasted UUID still opens the profile directly.
+				if isUUID(query) {
+					return m, m.openProfile(query)
+				}
+				m.query = query
+				m.searched = true
+				m.loading = true
+				m.errorMsg = ""
+				m.profile = nil
+				m.currentUserID = ""
+				m.results = nil
+				m.nextCursor = ""
+				m.cursor = 0
+				m.buildViewportContent()
+				return m, tea.Batch(m.spin.Tick, searchUsersCmd(m.client, query, "", 20, false))
+			case "down", "tab":
+				if m.profile == nil && len(m.results) > 0 {
+					m.input.Blur()
+					m.buildViewportContent()
+				}
+				return m, nil
+			}
+			break
+		}
+
+		switch msg.String() {
+		case "/", "tab":
+			m.input.Focus()
+			m.buildViewportContent()
+			return m, textinput.Blink
+		case "up", "k":
+			if m.profile == nil && m.cursor > 0 {
+				m.cursor--
+				m.buildViewportContent()
+			}
+			return m, nil
+		case "down", "j":
+			if m.profile == nil && m.cursor < len(m.results)-1 {
+				m.cursor++
+				m.buildViewportContent()
+			}
+			if m.profile == nil && m.cursor == len(m.results)-1 && m.nextCursor != "" && !m.loading {
+				m.loading = true
+				return m, tea.Batch(m.spin.Tick, searchUsersCmd(m.client, m.query, m.nextCursor, 20, true))
+			}
+			return m, nil
+		case "enter":
+			if m.profile == nil && m.cursor < len(m.results) {
+				return m, m.openProfile(m.results[m.cursor].ID)
+			}
+			return m, nil
+		case "backspace":
+			// Back from a profile to the search results.
+			if m.profile != nil && m.searched {
+				m.profile = nil
+				m.currentUserID = ""
+				m.buildViewportContent()
+			}
+			return m, nil
+		case "f":
+			if m.currentUserID == "" {
+				return m, nil
+			}
+			return m, followUserCmd(m.client, m.currentUserID)
+		case "u":
+			if m.currentUserID == "" {
+				return m, nil
+			}
+			return m, unfollowUserCmd(m.client, m.currentUserID)
+		}
+
+	case UserSearchResultsMsg:
+		m.loading = false
+		if msg.Err != nil {
+			m.errorMsg = msg.Err.Error()
+		} else {
+			m.errorMsg = ""
+			if msg.Append {
+				m.results = append(m.results, msg.Users...)
+			} else {
+				m.results = msg.Users
+			}
+			m.nextCursor = msg.NextCursor
+		}
+		m.buildViewportContent()
+		return m, nil
+
+	case FollowUnfollowSuccessMsg:
+		if m.currentUserID != "" {
+			m.loading = true
+			return m, tea.Batch(m.spin.Tick, fetchUserProfileCmd(m.client, m.currentUserID))
+		}
+		return m, nil
+
+	case ProfileLoadedMsg:
+		m.loading = false
+		if msg.Err != nil {
+			m.errorMsg = msg.Err.Error()
+			m.profile = nil
+		} else {
+			m.errorMsg = ""
+			m.profile = msg.Profile
+		}
+		m.buildViewportContent()
+		return m, nil
+
+	case ErrorMsg:
+		m.loading = false
+		if msg.Err != nil {
+			m.errorMsg = msg.Err.Error()
+		}
+		m.buildViewportContent()
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	cmds = append(cmds, cmd)
+	m.viewport, cmd = m.viewport.Update(msg)
+	cmds = append(cmds, cmd)
+	if m.loading {
+		m.spin, cmd = m.spin.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m *BrowseModel) openProfile(userID string) tea.Cmd {
+	m.input.Blur()
+	m.currentUserID = userID
+	m.loading = true
+	m.errorMsg = ""
+	m.profile = nil
+	return tea.Batch(m.spin.Tick, fetchUserProfileCmd(m.client, userID))
+}
+
+func (m *BrowseModel) recalculateViewport() {
+	if m.width == 0 || m.height == 0 {
+		return
+	}
+
+	header := headerStyle.Width(m.width).Render(" Chirpy | Browse Users ")
+	footer := footerStyle.Width(m.width).Render("")
+
+	contentHeight := m.height - lipgloss.Height(header) - lipgloss.Height(footer) - 3
+	if contentHeight < 1 {
+		contentHeight = 1
+	}
+
+	m.viewport.Width = m.width - 4
+	if m.viewport.Width < 20 {
+		m.viewport.Width = m.width
+	}
+	m.viewport.Height = contentHeight
+
+	m.buildViewportContent()
+}
+
+func (m *BrowseModel) buildViewportContent() {
+	var lines []string
+
+	if m.loading && m.currentUserID != "" {
+		lines = append(lines,
+			lipgloss.NewStyle().Foreground(colorMuted).
+				Render(m.spin.View()+" Loading profile..."),
+		)
+	} else if m.profile == nil {
+		lines = append(lines, m.renderResults()...)
+	} else {
+		status := "Standard"
+		if m.profile.IsChirpyRed {
+			status = "Chirpy Red"
+		}
+		details := append(profileDetails(m.profile),
+			"User ID: "+m.profile.ID,
+			"Status: "+status,
+			fmt.Sprintf("Followers: %d  Following: %d  Chirps: %d",
+				m.profile.FollowersCount, m.profile.FollowingCount, m.profile.ChirpsCount),
+		)
+		info := lipgloss.JoinVertical(lipgloss.Left, details...)
+		if m.profile.IsFollowing != nil {
+			if *m.profile.IsFollowing {
+				info = lipgloss.JoinVertical(lipgloss.Left, info, authorStyle.Render("You follow this user"))
+			} else {
+				info = lipgloss.JoinVertical(lipgloss.Left, info, lipgloss.NewStyle().Foreground(colorMuted).Render("Not following"))
+			}
+		}
+		lines = append(lines, chirpBoxStyle.Width(m.viewport.Width).Render(info))
+		lines = append(lines, "")
+
+		if len(m.profile.Chirps) == 0 {
+			lines = append(lines, lipgloss.NewStyle().Foreground(colorMuted).Render("No chirps yet."))
+		} else {
+			lines = append(lines, lipgloss.NewStyle().Bold(true).Render("Chirps:"))
+			for _, c := range m.profile.Chirps {
+				header := fmt.Sprintf("%s", relativeTime(c.CreatedAt))
+				body := wordwrap.String(c.Body, m.viewport.Width-4)
+				content := lipgloss.JoinVertical(
+					lipgloss.Left,
+					timestampStyle.Render(header),
+					body,
+				)
+				lines = append(lines, chirpBoxStyle.Width(m.viewport.Width).Render(content))
+			}
+		}
+	}
+
+	if m.errorMsg != "" {
+		lines = append([]string{errorStyle.Render("⚠ " + m.errorMsg), ""}, lines...)
+	}
+
+	m.viewport.SetContent(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+func (m *BrowseModel) renderResults() []string {
+	muted := lipgloss.NewStyle().Foreground(colorMuted)
+
+	switch {
+	case m.loading && len(m.results) == 0:
+		return []string{muted.Render(m.spin.View() + " Searching...")}
+	case !m.searched:
+		return []string{muted.Render("Search for people by handle or name and press Enter.")}
+	case len(m.results) == 0:
+		return []string{muted.Render("No users match \"" + m.query + "\".")}
+	}
+
+	var lines []string
+	for i, u := range m.results {
+		name := "@" + u.Handle
+		if u.DisplayName != "" {
+			name = u.DisplayName + "  @" + u.Handle
+		}
+
+		var tags []string
+		if u.IsFollowing {
+			tags = append(tags, "Following")
+		}
+		if u.FollowsYou {
+			tags = append(tags, "Follows you")
+		}
+		if u.IsChirpyRed {
+			tags = append(tags, "Chirpy Red")
+		}
+
+		content := authorStyle.Render(name)
+		if len(tags) > 0 {
+			content = lipgloss.JoinVertical(lipgloss.Left, content, muted.Render(strings.Join(tags, " · ")))
+		}
+
+		if i == m.cursor && !m.input.Focused() {
+			lines = append(lines, selectedChirpStyle.Width(m.viewport.Width).Render(content))
+		} else {
+			lines = append(lines, chirpBoxStyle.Width(m.viewport.Width).Render(content))
+		}
+	}
+
+	if m.nextCursor != "" {
+		lines = append(lines, "", muted.Render("↓ More results load as you scroll ↓"))
+	}
+	return lines
+}
+
+func (m BrowseModel) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Browse"
+	}
+
+	header := headerStyle.Width(m.width).Render(" Chirpy | Browse Users ")
+
+	var bodyLines []string
+	bodyLines = append(bodyLines, m.input.View(), "")
+	bodyLines = append(bodyLines, m.viewport.View())
+
+	body := contentStyle.Render(
+		lipgloss.JoinVertical(lipgloss.Left, bodyLines...),
+	)
+
+	now := time.Now().Format("2006-01-02 15:04")
+	left := "[Enter] Search  [tab] Results  [q] Quit"
+	switch {
+	case m.profile != nil:
+		left = "[f] Follow  [u] Unfollow  [backspace] Results  [/] Search  [q] Quit"
+	case !m.input.Focused():
+		left = "[↑/k ↓/j] Select  [Enter] Open profile  [/] Search  [q] Quit"
+	}
+	space := ""
+	totalWidth := lipgloss.Width(left + now)
+	if m.width > totalWidth {
+		space = strings.Repeat(" ", m.width-totalWidth)
+	}
+	footer := footerStyle.Width(m.width).Render(left + space + now)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		body,
+		footer,
+	)
+}
+
+func fetchUserProfileCmd(client *api.Chirpy, userID string) tea.Cmd {
+	return func() tea.Msg {
+		profile, err := client.GetProfile(userID, "", 20)
+		return ProfileLoadedMsg{Profile: profile, Err: err}
+	}
+}
+
+func followUserCmd(client *api.Chirpy, userID string) tea.Cmd {
+	return func() tea.Msg {
+		err := client.FollowUser(userID)
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return FollowUnfollowSuccessMsg{UserID: userID}
+	}
+}
+
+func unfollowUserCmd(client *api.Chirpy, userID string) tea.Cmd {
+	return func() tea.Msg {
+		err := client.UnfollowUser(userID)
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return FollowUnfollowSuccessMsg{UserID: userID}
+	}
+}
+
+func searchUsersCmd(client *api.Chirpy, query, cursor string, limit int, append bool) tea.Cmd {
+	return func() tea.Msg {
+		res, err := client.SearchUsers(query, cursor, limit)
+		if err != nil {
+			return UserSearchResultsMsg{Err: err, Append: append}
+		}
+		var next string
+		if res.NextCursor != nil {
+			next = *res.NextCursor
+		}
+		return UserSearchResultsMsg{
+			Users:      res.Results,
+			NextCursor: next,
+			Append:     append,
+		}
+	}
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+	return true
+}
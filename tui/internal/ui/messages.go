@@ -1,59 +1,91 @@
-package ui
-
-import (
-	"github.com/shubh-man007/Chirpy/tui/internal/models"
-)
-
-type LoginSuccessMsg struct {
-	User *models.User
-}
-
-type LoginFailureMsg struct {
-	Err error
-}
-
-type ErrorMsg struct {
-	Err error
-}
-
-type FeedLoadedMsg struct {
-	Chirps []models.Chirp
-	Append bool
-}
-
-type ChirpPostedMsg struct {
-	Chirp *models.Chirp
-}
-
-type UserUpdatedMsg struct {
-	User *models.User
-	Err  error
-}
-
-type UserDeletedMsg struct {
-	Err error
-}
-
-type UserChirpsLoadedMsg struct {
-	Chirps []models.Chirp
-	Err    error
-}
-
-type ProfileLoadedMsg struct {
-	Profile *models.ProfileResponse
-	Err     error
-}
-
-type FollowersLoadedMsg struct {
-	Followers []models.FollowerRow
-	Err       error
-}
-
-type FollowingLoadedMsg struct {
-	Following []models.FollowingRow
-	Err       error
-}
-
-type FollowUnfollowSuccessMsg struct {
-	UserID string
-}
+package ui
+
+import (
+	"github.com/shubh-man007/Chirpy/tui/internal/models"
+)
+
+type LoginSuccessMsg struct {
+	User *models.User
+}
+
+type LoginFailureMsg struct {
+	Err error
+}
+
+type ErrorMsg struct {
+	Err error
+}
+
+type FeedLoadedMsg struct {
+	Chirps     []models.Chirp
+	NextCursor string
+	PrevCursor string
+	Append     bool
+	Prepend    bool
+}
+
+type ChirpPostedMsg struct {
+	Chirp *models.Chirp
+}
+
+type UserUpdatedMsg struct {
+	User *models.User
+	Err  error
+}
+
+type UserDeletedMsg struct {
+	Err error
+}
+
+type UserChirpsLoadedMsg struct {
+	Chirps []models.Chirp
+	Err    error
+}
+
+type ProfileLoadedMsg struct {
+	Profile *models.ProfileResponse
+	Err     error
+}
+
+type FollowersLoadedMsg struct {
+	Followers []models.FollowerRow
+	Err       error
+}
+
+type FollowingLoadedMsg struct {
+	Following []models.FollowingRow
+	Err       error
+}
+
+type FollowUnfollowSuccessMsg struct {
+	UserID string
+}
+
+type SearchResultsMsg struct {
+	Chirps     []models.Chirp
+	NextCursor string
+	Append     bool
+	Err        error
+}
+
+type UserSearchResultsMsg struct {
+	Users      []models.UserSearchResult
+	NextCursor string
+	Append     bool
+	Err        error
+}
+
+type StreamConnectedMsg struct {
+	Events <-chan models.StreamEvent
+}
+
+type StreamEventMsg struct {
+	Event  models.StreamEvent
+	Events <-chan models.StreamEvent
+}
+
+type StreamClosedMsg struct {
+	Err error
+}
+
+type streamReconnectMsg struct{}
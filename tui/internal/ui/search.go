@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/muesli/reflow/wordwrap"
+
+	"github.com/shubh-man007/Chirpy/tui/internal/api"
+	"github.com/shubh-man007/Chirpy/tui/internal/models"
+)
+
+// SearchModel runs full-text chirp searches against /api/search/chirps.
+type SearchModel struct {
+	client *api.Chirpy
+
+	width  int
+	height int
+
+	input    textinput.Model
+	viewport viewport.Model
+
+	query         string
+	followingOnly bool
+	results       []models.Chirp
+	nextCursor    string
+	searched      bool
+
+	limit int
+
+	loading  bool
+	errorMsg string
+	spin     spinner.Model
+}
+
+func NewSearchModel(client *api.Chirpy) SearchModel {
+	ti := textinput.New()
+	ti.Placeholder = `words, "exact phrase", prefix*`
+	ti.Prompt = "Search: "
+	ti.Focus()
+
+	vp := viewport.New(0, 0)
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+
+	return SearchModel{
+		client:   client,
+		input:    ti,
+		viewport: vp,
+		limit:    20,
+		spin:     s,
+	}
+}
+
+func (m SearchModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.recalculateViewport()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			query := strings.TrimSpace(m.input.Value())
+			if query == "" {
+				m.errorMsg = "Type something to search for."
+				m.buildViewportContent()
+				return m, nil
+			}
+			return m, m.startSearch(query)
+		case "ctrl+f":
+			// Toggle "only people I follow" and re-run the current search.
+			m.followingOnly = !m.followingOnly
+			if m.query == "" {
+				m.buildViewportContent()
+				return m, nil
+			}
+			return m, m.startSearch(m.query)
+		}
+
+	case SearchResultsMsg:
+		m.loading = false
+		if msg.Err != nil {
+			m.errorMsg = msg.Err.Error()
+		} else {
+			m.errorMsg = ""
+			if msg.Append {
+				m.results = append(m.results, msg.Chirps...)
+			} else {
+				m.results = msg.Chirps
+			}
+			m.nextCursor = msg.NextCursor
+		}
+		m.buildViewportContent()
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	cmds = append(cmds, cmd)
+	m.viewport, cmd = m.viewport.Update(msg)
+	cmds = append(cmds, cmd)
+	if m.loading {
+		m.spin, cmd = m.spin.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	if m.viewport.AtBottom() && !m.loading && m.nextCursor != "" {
+		m.loading = true
+		cmds = append(cmds, tea.Batch(
+			m.spin.Tick,
+			searchChirpsCmd(m.client, m.query, m.nextCursor, m.limit, m.followingOnly, true),
+		))
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m *SearchModel) startSearch(query string) tea.Cmd {
+	m.query = query
+	m.searched = true
+	m.loading = true
+	m.errorMsg = ""
+	m.results = nil
+	m.nextCursor = ""
+	m.viewport.GotoTop()
+	m.buildViewportContent()
+	return tea.Batch(
+		m.spin.Tick,
+		searchChirpsCmd(m.client, query, "", m.limit, m.followingOnly, false),
+	)
+}
+
+func (m *SearchModel) recalculateViewport() {
+	if m.width == 0 || m.height == 0 {
+		return
+	}
+
+	header := headerStyle.Width(m.width).Render(" Chirpy | Search ")
+	footer := footerStyle.Width(m.width).Render("")
+
+	contentHeight := m.height - lipgloss.Height(header) - lipgloss.Height(footer) - 4
+	if contentHeight < 1 {
+		contentHeight = 1
+	}
+
+	m.viewport.Width = m.width - 4
+	if m.viewport.Width < 20 {
+		m.viewport.Width = m.width
+	}
+	m.viewport.Height = contentHeight
+
+	m.buildViewportContent()
+}
+
+func (m *SearchModel) buildViewportContent() {
+	var lines []string
+
+	switch {
+	case m.loading && len(m.results) == 0:
+		lines = append(lines,
+			lipgloss.NewStyle().Foreground(colorMuted).
+				Render(m.spin.View()+" Searching..."),
+		)
+	case !m.searched:
+		lines = append(lines,
+			lipgloss.NewStyle().Foreground(colorMuted).
+				Render("Search chirps by keyword. Use quotes for phrases and * for prefixes."),
+		)
+	case len(m.results) == 0:
+		lines = append(lines,
+			lipgloss.NewStyle().Foreground(colorMuted).
+				Render("No chirps match \""+m.query+"\"."),
+		)
+	default:
+		for _, c := range m.results {
+			lines = append(lines, m.renderResult(c))
+		}
+		if m.nextCursor != "" {
+			lines = append(lines, "", lipgloss.NewStyle().
+				Foreground(colorMuted).
+				Render("↓ More results loading as you scroll ↓"))
+		}
+	}
+
+	if m.errorMsg != "" {
+		lines = append([]string{errorStyle.Render("⚠ " + m.errorMsg), ""}, lines...)
+	}
+
+	m.viewport.SetContent(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+func (m *SearchModel) renderResult(c models.Chirp) string {
+	author := c.UserID
+	if c.AuthorHandle != "" {
+		author = "@" + c.AuthorHandle
+	}
+	header := fmt.Sprintf("%s · %s", truncate.StringWithTail(author, 24, "…"), relativeTime(c.CreatedAt))
+	body := wordwrap.String(c.Body, m.viewport.Width-4)
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		authorStyle.Render(header),
+		body,
+	)
+	return chirpBoxStyle.Width(m.viewport.Width).Render(content)
+}
+
+func (m SearchModel) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Search"
+	}
+
+	header := headerStyle.Width(m.width).Render(" Chirpy | Search ")
+
+	scope := "Everyone"
+	if m.followingOnly {
+		scope = "People I follow"
+	}
+
+	body := contentStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.input.View(),
+		lipgloss.NewStyle().Foreground(colorMuted).Render("Scope: "+scope),
+		"",
+		m.viewport.View(),
+	))
+
+	now := time.Now().Format("2006-01-02 15:04")
+	left := "[Enter] Search  [ctrl+f] Toggle following only  [esc] Menu"
+	space := ""
+	totalWidth := lipgloss.Width(left + now)
+	if m.width > totalWidth {
+		space = strings.Repeat(" ", m.width-totalWidth)
+	}
+	footer := footerStyle.Width(m.width).Render(left + space + now)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		body,
+		footer,
+	)
+}
+
+func searchChirpsCmd(client *api.Chirpy, query, cursor string, limit int, followingOnly, append bool) tea.Cmd {
+	return func() tea.Msg {
+		res, err := client.SearchChirps(query, cursor, limit, followingOnly)
+		if err != nil {
+			return SearchResultsMsg{Err: err, Append: append}
+		}
+		var next string
+		if res.NextCursor != nil {
+			next = *res.NextCursor
+		}
+		return SearchResultsMsg{
+			Chirps:     res.Results,
+			NextCursor: next,
+			Append:     append,
+		}
+	}
+}